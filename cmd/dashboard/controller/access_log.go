@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// recordAccess 记录一次后台访问，国家与 ASN 异步查询
+func recordAccess(c *gin.Context, event string, userID uint64, username string) {
+	ip := c.GetString(model.CtxKeyRealIPStr)
+	if ip == "" {
+		ip = c.RemoteIP()
+	}
+	singleton.RecordAccess(&model.AccessLog{
+		Event:     event,
+		UserID:    userID,
+		Username:  username,
+		IP:        ip,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		UserAgent: c.Request.UserAgent(),
+	})
+}
+
+// accessLog 记录已登录用户修改数据的请求，GET 请求太多不记录
+func accessLog(c *gin.Context) {
+	c.Next()
+	if c.Request.Method == http.MethodGet {
+		return
+	}
+	user, ok := c.Get(model.CtxKeyAuthorizedUser)
+	if !ok {
+		return
+	}
+	u := user.(*model.User)
+	recordAccess(c, model.AccessEventRequest, u.ID, u.Username)
+}
+
+// List access logs
+// @Summary List access logs
+// @Security BearerAuth
+// @Schemes
+// @Description List dashboard logins and changes with the country and ASN of the client, newest first
+// @Tags admin required
+// @Param limit query uint false "Page limit"
+// @Param offset query uint false "Page offset"
+// @Param event query string false "login, login_failed or request"
+// @Param ip query string false "Client IP"
+// @Param country query string false "Country codes, comma separated"
+// @Param user_id query uint false "User ID"
+// @Produce json
+// @Success 200 {object} model.PaginatedResponse[[]model.AccessLog, model.AccessLog]
+// @Router /access-log [get]
+func listAccessLog(c *gin.Context) (*model.Value[[]*model.AccessLog], error) {
+	if u, ok := c.Get(model.CtxKeyAuthorizedUser); !ok || !u.(*model.User).Role.IsAdmin() {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	query := singleton.DB.Model(&model.AccessLog{})
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if ip := c.Query("ip"); ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	if countries := model.GeoFilterCtx(c).Countries; len(countries) > 0 {
+		query = query.Where("country IN (?)", countries)
+	}
+	if userID, err := strconv.ParseUint(c.Query("user_id"), 10, 64); err == nil {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	// 统计总数后还要继续查询
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	var logs []*model.AccessLog
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	return &model.Value[[]*model.AccessLog]{
+		Value: logs,
+		Pagination: model.Pagination{
+			Offset: offset,
+			Limit:  limit,
+			Total:  total,
+		},
+	}, nil
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// Start agent rollout
+// @Summary Start agent rollout
+// @Security BearerAuth
+// @Schemes
+// @Description Upgrade agents group by group to the release at url, which the agent verifies against checksums before swapping binaries (see model.AgentUpgradeTask). Each group is sent the upgrade task only after every online server of the previous group reconnected with the target version; the rollout stops when a group does not finish within stage_timeout
+// @Tags admin required
+// @Accept json
+// @param request body model.AgentRolloutForm true "AgentRolloutForm"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.AgentRollout]
+// @Router /agent-rollout [post]
+func startAgentRollout(c *gin.Context) (*model.AgentRollout, error) {
+	var rf model.AgentRolloutForm
+	if err := c.ShouldBindJSON(&rf); err != nil {
+		return nil, err
+	}
+	if rf.Version == "" {
+		return nil, singleton.Localizer.ErrorT("version is not set")
+	}
+	if len(rf.ServerGroups) == 0 {
+		return nil, singleton.Localizer.ErrorT("need to configure at least a single server group")
+	}
+	if rf.StageTimeout == 0 {
+		rf.StageTimeout = 600
+	}
+	if rf.StageTimeout < 60 {
+		return nil, singleton.Localizer.ErrorT("stage_timeout need to be at least 60")
+	}
+
+	var groups []uint64
+	if err := singleton.DB.Model(&model.ServerGroup{}).Where("id IN (?)", rf.ServerGroups).Pluck("id", &groups).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	for _, id := range rf.ServerGroups {
+		if !slices.Contains(groups, id) {
+			return nil, singleton.Localizer.ErrorT("group id %d does not exist", id)
+		}
+	}
+
+	return singleton.StartAgentRollout(&rf)
+}
+
+// Get agent rollout
+// @Summary Get agent rollout
+// @Security BearerAuth
+// @Schemes
+// @Description Progress of the current or last agent rollout, null when none was started
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.AgentRollout]
+// @Router /agent-rollout [get]
+func getAgentRollout(c *gin.Context) (*model.AgentRollout, error) {
+	return singleton.GetAgentRollout(), nil
+}
+
+// Cancel agent rollout
+// @Summary Cancel agent rollout
+// @Security BearerAuth
+// @Schemes
+// @Description Stop the agent rollout in progress. Upgrade tasks already sent are not reverted
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /agent-rollout/cancel [post]
+func cancelAgentRollout(c *gin.Context) (any, error) {
+	return nil, singleton.CancelAgentRollout()
+}
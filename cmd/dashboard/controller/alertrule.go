@@ -173,6 +173,13 @@ func validateRule(c *gin.Context, r *model.AlertRule) error {
 				return singleton.Localizer.ErrorT("permission denied")
 			}
 
+			if rule.Type == "custom_metric" && rule.Metric == "" {
+				return singleton.Localizer.ErrorT("metric is not set")
+			}
+			if rule.IsCountryChangeRule() {
+				// 国家变化只看最后一次检查，不需要持续时间
+				continue
+			}
 			if !rule.IsTransferDurationRule() {
 				if rule.Duration < 3 {
 					return singleton.Localizer.ErrorT("duration need to be at least 3")
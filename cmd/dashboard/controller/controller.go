@@ -27,6 +27,8 @@ import (
 )
 
 func ServeWeb(frontendDist fs.FS) http.Handler {
+	waf.WarnGeoBlockWithoutProxy()
+
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
 
@@ -43,6 +45,10 @@ func ServeWeb(frontendDist fs.FS) http.Handler {
 	r.Use(waf.Waf)
 	r.Use(recordPath)
 
+	if singleton.Conf.EnableMetrics {
+		r.GET("/metrics", metricsHandler())
+	}
+
 	routers(r, frontendDist)
 
 	return r
@@ -57,13 +63,13 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 		log.Fatal("authMiddleware.MiddlewareInit Error:" + err.Error())
 	}
 	api := r.Group("api/v1")
-	api.POST("/login", authMiddleware.LoginHandler)
-	api.GET("/oauth2/:provider", commonHandler(oauth2redirect))
+	api.POST("/login", waf.GeoBlock, authMiddleware.LoginHandler)
+	api.GET("/oauth2/:provider", waf.GeoBlock, commonHandler(oauth2redirect))
 
 	fallbackAuthMw := fallbackAuthMiddleware(authMiddleware)
 	fallbackAuth := api.Group("", fallbackAuthMw)
 	fallbackAuth.GET("/setting", commonHandler(listConfig))
-	fallbackAuth.GET("/oauth2/callback", commonHandler(oauth2callback(authMiddleware)))
+	fallbackAuth.GET("/oauth2/callback", waf.GeoBlock, commonHandler(oauth2callback(authMiddleware)))
 
 	authMw := authMiddleware.MiddlewareFunc()
 	optionalAuthMw := utils.IfOr(singleton.Conf.ForceAuth, authMw, fallbackAuthMw)
@@ -71,12 +77,14 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	optionalAuth := api.Group("", optionalAuthMw)
 	optionalAuth.GET("/ws/server", commonHandler(serverStream))
 	optionalAuth.GET("/server-group", commonHandler(listServerGroup))
+	optionalAuth.GET("/world-map", commonHandler(getWorldMap))
+	optionalAuth.GET("/status-page", commonHandler(getStatusPage))
 
 	optionalAuth.GET("/service", commonHandler(showService))
 	optionalAuth.GET("/service/:id", commonHandler(listServiceHistory))
 	optionalAuth.GET("/service/server", commonHandler(listServerWithServices))
 
-	auth := api.Group("", authMw)
+	auth := api.Group("", authMw, waf.GeoBlockAdmin, accessLog)
 
 	auth.GET("/refresh-token", authMiddleware.RefreshHandler)
 
@@ -109,12 +117,26 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	auth.POST("/batch-delete/notification-group", commonHandler(batchDeleteNotificationGroup))
 
 	auth.GET("/server", listHandler(listServer))
+	auth.GET("/server/geojson", commonHandler(exportServerGeoJSON))
+	auth.GET("/server/:id/containers", commonHandler(listServerContainers))
+	auth.GET("/server/:id/containers/watched", commonHandler(listWatchedContainers))
+	auth.POST("/server/:id/containers/watched", commonHandler(setWatchedContainers))
+	auth.GET("/server/:id/disks", commonHandler(listServerDisks))
+	auth.GET("/server/:id/processes", commonHandler(listServerProcesses))
+	auth.GET("/server/:id/interfaces", commonHandler(listServerInterfaces))
+	auth.GET("/server/:id/units", commonHandler(listServerUnits))
+	auth.POST("/server/:id/units", commonHandler(setServerUnits))
+	auth.GET("/server/:id/metrics", commonHandler(listServerMetrics))
+	auth.GET("/server/:id/metrics/:name", commonHandler(getServerMetricHistory))
 	auth.PATCH("/server/:id", commonHandler(updateServer))
 	auth.GET("/server/config/:id", commonHandler(getServerConfig))
 	auth.POST("/server/config", commonHandler(setServerConfig))
 	auth.POST("/batch-delete/server", commonHandler(batchDeleteServer))
 	auth.POST("/batch-move/server", commonHandler(batchMoveServer))
 	auth.POST("/force-update/server", commonHandler(forceUpdateServer))
+	auth.GET("/agent-rollout", adminHandler(getAgentRollout))
+	auth.POST("/agent-rollout", adminHandler(startAgentRollout))
+	auth.POST("/agent-rollout/cancel", adminHandler(cancelAgentRollout))
 
 	auth.GET("/notification", listHandler(listNotification))
 	auth.POST("/notification", commonHandler(createNotification))
@@ -132,6 +154,11 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	auth.GET("/cron/:id/manual", commonHandler(manualTriggerCron))
 	auth.POST("/batch-delete/cron", commonHandler(batchDeleteCron))
 
+	auth.GET("/metric-plugin", listHandler(listMetricPlugin))
+	auth.POST("/metric-plugin", commonHandler(createMetricPlugin))
+	auth.PATCH("/metric-plugin/:id", commonHandler(updateMetricPlugin))
+	auth.POST("/batch-delete/metric-plugin", commonHandler(batchDeleteMetricPlugin))
+
 	auth.GET("/ddns", listHandler(listDDNS))
 	auth.GET("/ddns/providers", commonHandler(listProviders))
 	auth.POST("/ddns", commonHandler(createDDNS))
@@ -146,11 +173,19 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	auth.GET("/waf", pCommonHandler(listBlockedAddress))
 	auth.POST("/batch-delete/waf", adminHandler(batchDeleteBlockedAddress))
 
+	auth.GET("/access-log", pCommonHandler(listAccessLog))
+
 	auth.GET("/online-user", pCommonHandler(listOnlineUser))
 	auth.POST("/online-user/batch-block", adminHandler(batchBlockOnlineUser))
 
 	auth.PATCH("/setting", adminHandler(updateConfig))
 
+	auth.GET("/geo-rollup", adminHandler(getGeoRollup))
+	auth.GET("/geoip/cache", adminHandler(getGeoIPCacheStats))
+	auth.GET("/geoip/stats", adminHandler(getGeoIPLookupStats))
+	auth.POST("/geoip/cache/purge", adminHandler(purgeGeoIPCache))
+	auth.GET("/geoip/debug", adminHandler(getGeoIPDebug))
+
 	r.NoRoute(fallbackToFrontend(frontendDist))
 }
 
@@ -360,6 +395,12 @@ func fallbackToFrontend(frontendDist fs.FS) func(*gin.Context) {
 
 		fallbackStatusCode := getFallbackStatusCode(c.Request.URL.Path)
 		if strings.HasPrefix(c.Request.URL.Path, "/dashboard") {
+			if singleton.Conf.AdminGeoBlockAll {
+				if err := waf.CheckAdminCountry(c); err != nil {
+					waf.ShowBlockPage(c, err)
+					return
+				}
+			}
 			stripPath := strings.TrimPrefix(c.Request.URL.Path, "/dashboard")
 			localFilePath := path.Join(singleton.Conf.AdminTemplate, stripPath)
 			if checkLocalFileOrFs(c, frontendDist, localFilePath, http.StatusOK) {
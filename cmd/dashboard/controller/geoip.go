@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// Get GeoIP cache statistics
+// @Summary Get GeoIP cache statistics
+// @Security BearerAuth
+// @Schemes
+// @Description Get size, hit rate and per-source counts of the GeoIP lookup cache
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[geoip.CacheStats]
+// @Router /geoip/cache [get]
+func getGeoIPCacheStats(c *gin.Context) (geoip.CacheStats, error) {
+	return geoip.Stats(), nil
+}
+
+// Get recent GeoIP lookup distribution
+// @Summary Get recent GeoIP lookup distribution
+// @Security BearerAuth
+// @Schemes
+// @Description Count lookups per resulting country and ASN over the sliding window set by GEOIP_STATS_WINDOW
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[geoip.LookupStats]
+// @Router /geoip/stats [get]
+func getGeoIPLookupStats(c *gin.Context) (geoip.LookupStats, error) {
+	return geoip.RecentLookups(), nil
+}
+
+// Export server locations as GeoJSON
+// @Summary Export server locations as GeoJSON
+// @Security BearerAuth
+// @Schemes
+// @Description Export the location of every server as a GeoJSON FeatureCollection of points, using city coordinates when known and the country centroid otherwise. The response is the bare FeatureCollection so map libraries can load it directly
+// @Tags auth required
+// @Produce json
+// @Success 200 {object} geoip.FeatureCollection
+// @Router /server/geojson [get]
+func exportServerGeoJSON(c *gin.Context) (any, error) {
+	fc := geoip.NewFeatureCollection()
+	for _, s := range singleton.ServerShared.GetSortedList() {
+		if s.GeoIP == nil || !s.HasPermission(c) {
+			continue
+		}
+		res := &geoip.Result{CountryCode: s.GeoIP.CountryCode}
+		ip := s.GeoIP.IP.IPv4Addr
+		if ip == "" {
+			ip = s.GeoIP.IP.IPv6Addr
+		}
+		// 查询结果已经缓存，这里只是取出城市坐标
+		if ip != "" {
+			if detail, err := geoip.LookupDetailStringContext(c.Request.Context(), ip); err == nil && !detail.Private {
+				res = detail
+			}
+		}
+		fc.Add(res, map[string]any{"id": s.ID, "name": s.Name})
+	}
+	c.JSON(http.StatusOK, fc)
+	return nil, errNoop
+}
+
+// Get server distribution for a world map
+// @Summary Get server distribution for a world map
+// @Schemes
+// @Description Aggregate servers per country and continent with server count, online/offline count, total traffic and centroid coordinates. Guests only see servers that are not hidden
+// @Tags common
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.WorldMap]
+// @Router /world-map [get]
+func getWorldMap(c *gin.Context) (*model.WorldMap, error) {
+	var servers []*model.Server
+	if _, isMember := c.Get(model.CtxKeyAuthorizedUser); isMember {
+		servers = singleton.ServerShared.GetSortedList()
+	} else {
+		servers = singleton.ServerShared.GetSortedListForGuest()
+	}
+	return singleton.NewWorldMap(servers, singleton.Conf.Language, time.Now()), nil
+}
+
+// Get public status page grouped by region
+// @Summary Get public status page grouped by region
+// @Schemes
+// @Description Group servers by country, or by continent with group=continent, with flag, online count and the percentage of online servers per group. Guests only see servers that are not hidden
+// @Tags common
+// @Param group query string false "country or continent, defaults to country"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.StatusPage]
+// @Router /status-page [get]
+func getStatusPage(c *gin.Context) (*model.StatusPage, error) {
+	var servers []*model.Server
+	if _, isMember := c.Get(model.CtxKeyAuthorizedUser); isMember {
+		servers = singleton.ServerShared.GetSortedList()
+	} else {
+		servers = singleton.ServerShared.GetSortedListForGuest()
+	}
+	return singleton.NewStatusPage(servers, c.Query("group"), singleton.Conf.Language, time.Now()), nil
+}
+
+// Get uptime and latency per region
+// @Summary Get uptime and latency per region
+// @Security BearerAuth
+// @Schemes
+// @Description Summarize the per-minute server online samples and the service monitor results by the country or continent of the server, between from and to (YYYY-MM-DD, inclusive, defaults to the current month)
+// @Tags admin required
+// @Param from query string false "First day"
+// @Param to query string false "Last day"
+// @Param group query string false "country or continent, defaults to country"
+// @Param country query string false "Country codes, comma separated"
+// @Param continent query string false "Continent codes, comma separated"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.GeoRollupSummary]
+// @Router /geo-rollup [get]
+func getGeoRollup(c *gin.Context) (*model.GeoRollupSummary, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, v, now.Location()); err != nil {
+			return nil, err
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, v, now.Location()); err != nil {
+			return nil, err
+		}
+	}
+
+	// 把还在内存中的数据写入数据库，返回的结果包含最近几分钟
+	singleton.FlushGeoRollups()
+	var rows []*model.GeoRollup
+	if err := singleton.DB.Where("day >= ? AND day <= ?", from, to).Find(&rows).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	filter := model.GeoFilterCtx(c)
+	rows = slices.DeleteFunc(rows, func(r *model.GeoRollup) bool {
+		var geo model.GeoIP
+		singleton.SetGeoIPCountry(&geo, r.Country, singleton.Conf.Language)
+		return !filter.Match(&model.Server{GeoIP: &geo})
+	})
+
+	summary := singleton.SummarizeGeoRollups(rows, c.Query("group"), singleton.Conf.Language)
+	summary.From, summary.To = from, to
+	for _, item := range summary.Services {
+		if service, ok := singleton.ServiceSentinelShared.Get(item.ServiceID); ok && service != nil {
+			item.ServiceName = service.Name
+		}
+	}
+	return summary, nil
+}
+
+// Purge GeoIP cache
+// @Summary Purge GeoIP cache
+// @Security BearerAuth
+// @Schemes
+// @Description Remove the cached GeoIP results of the given IPs, or the whole cache if the list is empty
+// @Tags admin required
+// @Accept json
+// @Param request body []string false "ip list"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /geoip/cache/purge [post]
+func purgeGeoIPCache(c *gin.Context) (any, error) {
+	var list []string
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&list); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(list) == 0 {
+		geoip.PurgeCache()
+		return nil, nil
+	}
+	ips := make([]net.IP, 0, len(list))
+	for _, s := range list {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, singleton.Localizer.ErrorT("invalid ip %s", s)
+		}
+		ips = append(ips, ip)
+	}
+	for _, ip := range ips {
+		geoip.Invalidate(ip)
+	}
+	return nil, nil
+}
+
+// Get GeoIP internals
+// @Summary Get GeoIP internals
+// @Security BearerAuth
+// @Schemes
+// @Description Show the active database, provider chain, provider health, circuit breakers and cache stats, optionally with a test lookup that bypasses the cache
+// @Tags admin required
+// @Param ip query string false "ip to look up"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[geoip.DebugInfo]
+// @Router /geoip/debug [get]
+func getGeoIPDebug(c *gin.Context) (*geoip.DebugInfo, error) {
+	debug := &geoip.DebugInfo{
+		Health:     geoip.Health(),
+		RateLimits: geoip.RateLimits(),
+		Cache:      geoip.Stats(),
+	}
+	if md, err := geoip.Metadata(); err != nil {
+		debug.DatabaseError = err.Error()
+	} else {
+		debug.Database = md
+	}
+	for _, p := range geoip.Providers() {
+		debug.Chain = append(debug.Chain, p.Name())
+	}
+
+	if s := c.Query("ip"); s != "" {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, singleton.Localizer.ErrorT("invalid ip %s", s)
+		}
+		// 先清除缓存，保证看到的是当前查询链的结果
+		geoip.Invalidate(ip)
+		debug.IP = ip.String()
+		if res, err := geoip.LookupDetailContext(c, ip); err != nil {
+			debug.LookupError = err.Error()
+		} else {
+			debug.Lookup = res
+		}
+	}
+	return debug, nil
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+	"github.com/nezhahq/nezha/pkg/i18n"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+func TestGetGeoIPDebug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	// 默认实例只使用测试库：1.0.0.0/24 → jp
+	dbPath := filepath.Join(dir, "country.mmdb")
+	entries := []builder.Entry{{Network: netip.MustParsePrefix("1.0.0.0/24"), Country: "jp"}}
+	if err := builder.BuildFile(dbPath, builder.Options{Description: "test"}, entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GEOIP_DB_PATH", dbPath)
+	t.Setenv("GEOIP_ASN_DB_PATH", filepath.Join(dir, "missing-asn.mmdb"))
+	t.Setenv("GEOIP_WATCH", "0")
+	t.Setenv("GEOIP_OFFLINE", "true")
+	singleton.Localizer = i18n.NewLocalizer("en_US", "nezha", "translations", i18n.Translations)
+
+	r := gin.New()
+	r.GET("/geoip/debug", func(c *gin.Context) {
+		role := model.RoleAdmin
+		if c.Query("member") != "" {
+			role = model.RoleMember
+		}
+		c.Set(model.CtxKeyAuthorizedUser, &model.User{Role: role})
+	}, adminHandler(getGeoIPDebug))
+	get := func(query string) model.CommonResponse[*geoip.DebugInfo] {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/geoip/debug?"+query, nil))
+		var resp model.CommonResponse[*geoip.DebugInfo]
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", query, w.Code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return resp
+	}
+
+	resp := get("ip=1.0.0.1")
+	if !resp.Success || resp.Data.Lookup == nil || resp.Data.Lookup.CountryCode != "jp" || resp.Data.IP != "1.0.0.1" {
+		t.Fatalf("1.0.0.1 = %+v", resp)
+	}
+	if resp.Data.Database == nil || resp.Data.Database.Path != dbPath || len(resp.Data.Chain) == 0 {
+		t.Fatalf("database = %+v, chain = %v", resp.Data.Database, resp.Data.Chain)
+	}
+
+	if resp := get("ip=192.168.1.1"); !resp.Success || resp.Data.Lookup == nil || !resp.Data.Lookup.Private {
+		t.Fatalf("192.168.1.1 = %+v", resp)
+	}
+	// 查询失败时返回其他状态与错误信息
+	if resp := get("ip=8.8.8.8"); !resp.Success || resp.Data.Lookup != nil || resp.Data.LookupError == "" {
+		t.Fatalf("8.8.8.8 = %+v", resp)
+	}
+	if resp := get("ip=not-an-ip"); resp.Success || !strings.Contains(resp.Error, "invalid ip") {
+		t.Fatalf("not-an-ip = %+v", resp)
+	}
+	if resp := get("ip=1.0.0.1&member=1"); resp.Success || resp.Data != nil {
+		t.Fatalf("member = %+v", resp)
+	}
+}
@@ -110,21 +110,25 @@ func authenticator() func(c *gin.Context) (any, error) {
 			if err == gorm.ErrRecordNotFound {
 				model.BlockIP(singleton.DB, realip, model.WAFBlockReasonTypeLoginFail, model.BlockIDUnknownUser)
 			}
+			recordAccess(c, model.AccessEventLoginFailed, 0, loginVals.Username)
 			return nil, jwt.ErrFailedAuthentication
 		}
 
 		if user.RejectPassword {
 			model.BlockIP(singleton.DB, realip, model.WAFBlockReasonTypeLoginFail, int64(user.ID))
+			recordAccess(c, model.AccessEventLoginFailed, user.ID, loginVals.Username)
 			return nil, jwt.ErrFailedAuthentication
 		}
 
 		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginVals.Password)); err != nil {
 			model.BlockIP(singleton.DB, realip, model.WAFBlockReasonTypeLoginFail, int64(user.ID))
+			recordAccess(c, model.AccessEventLoginFailed, user.ID, loginVals.Username)
 			return nil, jwt.ErrFailedAuthentication
 		}
 
 		model.UnblockIP(singleton.DB, realip, model.BlockIDUnknownUser)
 		model.UnblockIP(singleton.DB, realip, int64(user.ID))
+		recordAccess(c, model.AccessEventLogin, user.ID, loginVals.Username)
 
 		// 返回用户ID和IP地址的组合，用于在payloadFunc中设置JWT claims
 		return map[string]interface{}{
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List metric plugins
+// @Summary List metric plugins
+// @Security BearerAuth
+// @Schemes
+// @Description List custom metric plugins
+// @Tags auth required
+// @Param id query uint false "Resource ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.MetricPlugin]
+// @Router /metric-plugin [get]
+func listMetricPlugin(c *gin.Context) ([]*model.MetricPlugin, error) {
+	slist := singleton.MetricPluginShared.GetSortedList()
+
+	var p []*model.MetricPlugin
+	if err := copier.Copy(&p, &slist); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Create metric plugin
+// @Summary Create metric plugin
+// @Security BearerAuth
+// @Schemes
+// @Description Create a custom metric plugin. The command runs on the covered servers every duration seconds and each output line is either a number, reported as <name>, or "key value", reported as <name>.<key>
+// @Tags auth required
+// @Accept json
+// @param request body model.MetricPluginForm true "MetricPluginForm"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[uint64]
+// @Router /metric-plugin [post]
+func createMetricPlugin(c *gin.Context) (uint64, error) {
+	var pf model.MetricPluginForm
+	if err := c.ShouldBindJSON(&pf); err != nil {
+		return 0, err
+	}
+	if err := validateMetricPlugin(c, &pf); err != nil {
+		return 0, err
+	}
+
+	var p model.MetricPlugin
+	p.UserID = getUid(c)
+	p.Name = pf.Name
+	p.Command = pf.Command
+	p.Duration = pf.Duration
+	p.Servers = pf.Servers
+	p.Cover = pf.Cover
+
+	var err error
+	if p.CronJobID, err = singleton.MetricPluginShared.AddFunc(singleton.MetricPluginScheduler(&p), singleton.MetricPluginTrigger(&p)); err != nil {
+		return 0, err
+	}
+
+	if err = singleton.DB.Create(&p).Error; err != nil {
+		singleton.MetricPluginShared.Remove(p.CronJobID)
+		return 0, newGormError("%v", err)
+	}
+
+	singleton.MetricPluginShared.Update(&p)
+	return p.ID, nil
+}
+
+// Update metric plugin
+// @Summary Update metric plugin
+// @Security BearerAuth
+// @Schemes
+// @Description Update custom metric plugin
+// @Tags auth required
+// @Accept json
+// @param id path uint true "Plugin ID"
+// @param request body model.MetricPluginForm true "MetricPluginForm"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /metric-plugin/{id} [patch]
+func updateMetricPlugin(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var pf model.MetricPluginForm
+	if err := c.ShouldBindJSON(&pf); err != nil {
+		return nil, err
+	}
+	if err := validateMetricPlugin(c, &pf); err != nil {
+		return nil, err
+	}
+
+	var p model.MetricPlugin
+	if err := singleton.DB.First(&p, id).Error; err != nil {
+		return nil, singleton.Localizer.ErrorT("plugin id %d does not exist", id)
+	}
+
+	if !p.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	p.Name = pf.Name
+	p.Command = pf.Command
+	p.Duration = pf.Duration
+	p.Servers = pf.Servers
+	p.Cover = pf.Cover
+
+	if p.CronJobID, err = singleton.MetricPluginShared.AddFunc(singleton.MetricPluginScheduler(&p), singleton.MetricPluginTrigger(&p)); err != nil {
+		return nil, err
+	}
+
+	if err = singleton.DB.Save(&p).Error; err != nil {
+		singleton.MetricPluginShared.Remove(p.CronJobID)
+		return nil, newGormError("%v", err)
+	}
+
+	singleton.MetricPluginShared.Update(&p)
+	return nil, nil
+}
+
+// Batch delete metric plugins
+// @Summary Batch delete metric plugins
+// @Security BearerAuth
+// @Schemes
+// @Description Batch delete custom metric plugins
+// @Tags auth required
+// @Accept json
+// @param request body []uint64 true "id list"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /batch-delete/metric-plugin [post]
+func batchDeleteMetricPlugin(c *gin.Context) (any, error) {
+	var p []uint64
+	if err := c.ShouldBindJSON(&p); err != nil {
+		return nil, err
+	}
+
+	if !singleton.MetricPluginShared.CheckPermission(c, slices.Values(p)) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	if err := singleton.DB.Unscoped().Delete(&model.MetricPlugin{}, "id in (?)", p).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	singleton.MetricPluginShared.Delete(p)
+	return nil, nil
+}
+
+func validateMetricPlugin(c *gin.Context, pf *model.MetricPluginForm) error {
+	if !model.ValidMetricName(pf.Name) {
+		return singleton.Localizer.ErrorT("invalid metric name: %s", pf.Name)
+	}
+	if pf.Command == "" {
+		return singleton.Localizer.ErrorT("command is not set")
+	}
+	if pf.Duration < 10 {
+		return singleton.Localizer.ErrorT("duration need to be at least 10")
+	}
+	if pf.Cover != model.CronCoverIgnoreAll && pf.Cover != model.CronCoverAll {
+		return singleton.Localizer.ErrorT("invalid cover")
+	}
+	if !singleton.ServerShared.CheckPermission(c, slices.Values(pf.Servers)) {
+		return singleton.Localizer.ErrorT("permission denied")
+	}
+	return nil
+}
+
+// List custom metrics of server
+// @Summary List custom metrics of server
+// @Security BearerAuth
+// @Schemes
+// @Description Latest values reported by metric plugins on the server
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[map[string]float64]
+// @Router /server/{id}/metrics [get]
+func listServerMetrics(c *gin.Context) (map[string]float64, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if s.Metrics == nil {
+		return map[string]float64{}, nil
+	}
+	return s.Metrics, nil
+}
+
+// Get custom metric history of server
+// @Summary Get custom metric history of server
+// @Security BearerAuth
+// @Schemes
+// @Description History of a custom metric for charts, oldest first
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Param name path string true "Metric name, e.g. nginx.active"
+// @Param hours query uint false "Hours of history to return, default 24, at most 720"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.MetricHistory]
+// @Router /server/{id}/metrics/{name} [get]
+func getServerMetricHistory(c *gin.Context) ([]*model.MetricHistory, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := strconv.Atoi(c.Query("hours"))
+	if err != nil || hours < 1 {
+		hours = 24
+	}
+	hours = min(hours, 720)
+
+	var history []*model.MetricHistory
+	if err := singleton.DB.Where("server_id = ? AND name = ? AND created_at > ?", s.ID, c.Param("name"), time.Now().Add(-time.Duration(hours)*time.Hour)).
+		Order("created_at").Find(&history).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return history, nil
+}
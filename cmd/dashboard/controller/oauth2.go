@@ -175,6 +175,7 @@ func oauth2callback(jwtConfig *jwt.GinJWTMiddleware) func(c *gin.Context) (any,
 			if err := singleton.DB.Where("provider = ? AND open_id = ?", state.Provider, openId).First(&bind).Error; err != nil {
 				return nil, singleton.Localizer.ErrorT("oauth2 user not binded yet")
 			}
+			recordAccess(c, model.AccessEventLogin, bind.UserID, "")
 		}
 
 		tokenString, _, err := jwtConfig.TokenGenerator(map[string]interface{}{
@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"context"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,11 +25,13 @@ import (
 // @Description List server
 // @Tags auth required
 // @Param id query uint false "Resource ID"
+// @Param country query string false "Country codes, comma separated"
+// @Param continent query string false "Continent codes, comma separated"
 // @Produce json
 // @Success 200 {object} model.CommonResponse[[]model.Server]
 // @Router /server [get]
 func listServer(c *gin.Context) ([]*model.Server, error) {
-	slist := singleton.ServerShared.GetSortedList()
+	slist := model.GeoFilterCtx(c).FilterServers(singleton.ServerShared.GetSortedList())
 
 	var ssl []*model.Server
 	if err := copier.Copy(&ssl, &slist); err != nil {
@@ -196,6 +200,283 @@ func forceUpdateServer(c *gin.Context) (*model.ServerTaskResponse, error) {
 	return forceUpdateResp, nil
 }
 
+// List containers on server
+// @Summary List containers on server
+// @Security BearerAuth
+// @Schemes
+// @Description Run docker ps and docker stats on the agent and list its containers with CPU and memory usage. Requires command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.Container]
+// @Router /server/{id}/containers [get]
+func listServerContainers(c *gin.Context) ([]*model.Container, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	output, err := singleton.RunAgentCommand(ctx, s, model.ContainerCommand)
+	if err != nil {
+		return nil, err
+	}
+	return model.ParseContainers(output)
+}
+
+// List watched containers of server
+// @Summary List watched containers of server
+// @Security BearerAuth
+// @Schemes
+// @Description Read the state of the Docker containers watched on the server through docker ps on the agent. Requires command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.ContainerStatus]
+// @Router /server/{id}/containers/watched [get]
+func listWatchedContainers(c *gin.Context) ([]*model.ContainerStatus, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	return singleton.UpdateContainerStatus(ctx, s)
+}
+
+// Set watched containers of server
+// @Summary Set watched containers of server
+// @Security BearerAuth
+// @Schemes
+// @Description Set the Docker containers whose state is polled every minute and can be alerted with container_exited rules
+// @Tags auth required
+// @Accept json
+// @Param id path uint true "Server ID"
+// @param request body []string true "Container names"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /server/{id}/containers/watched [post]
+func setWatchedContainers(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if err := c.ShouldBindJSON(&names); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !model.ValidContainerName(name) {
+			return nil, singleton.Localizer.ErrorT("invalid container name: %s", name)
+		}
+	}
+
+	var s model.Server
+	if err := singleton.DB.First(&s, id).Error; err != nil {
+		return nil, singleton.Localizer.ErrorT("server id %d does not exist", id)
+	}
+	if !s.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	namesRaw, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	if err := singleton.DB.Model(&s).Update("watched_containers_raw", string(namesRaw)).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	if rs, ok := singleton.ServerShared.Get(s.ID); ok && rs != nil {
+		singleton.ServerShared.SetPolledStatus(rs, func() {
+			rs.WatchedContainers = names
+			rs.ContainerStatus = nil
+		})
+	}
+	return nil, nil
+}
+
+// List disk health of server
+// @Summary List disk health of server
+// @Security BearerAuth
+// @Schemes
+// @Description Run smartctl on the agent and list SMART health, reallocated sectors and wear level of each disk. Requires smartmontools and command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.DiskHealth]
+// @Router /server/{id}/disks [get]
+func listServerDisks(c *gin.Context) ([]*model.DiskHealth, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	return singleton.UpdateDiskHealth(ctx, s)
+}
+
+// List top processes of server
+// @Summary List top processes of server
+// @Security BearerAuth
+// @Schemes
+// @Description Run ps on the agent and list the processes using the most CPU, or the most memory with sort=memory. Linux and macOS only, requires command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Param sort query string false "cpu or memory, defaults to cpu"
+// @Param limit query uint false "Number of processes, defaults to 10"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.Process]
+// @Router /server/{id}/processes [get]
+func listServerProcesses(c *gin.Context) ([]*model.Process, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if s.Host != nil && strings.EqualFold(s.Host.Platform, "windows") {
+		return nil, singleton.Localizer.ErrorT("not supported on windows")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	output, err := singleton.RunAgentCommand(ctx, s, model.ProcessCommand)
+	if err != nil {
+		return nil, err
+	}
+	return model.ParseProcesses(output, c.Query("sort"), limit)
+}
+
+// List network interfaces of server
+// @Summary List network interfaces of server
+// @Security BearerAuth
+// @Schemes
+// @Description Read /proc/net/dev on the agent and list traffic, errors and drops per interface since boot. Linux only, requires command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Param exclude query string false "Interface name patterns to skip, comma separated, e.g. lo,docker*,veth*"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.NetInterface]
+// @Router /server/{id}/interfaces [get]
+func listServerInterfaces(c *gin.Context) ([]*model.NetInterface, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var exclude []string
+	for pattern := range strings.SplitSeq(c.Query("exclude"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			exclude = append(exclude, pattern)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	output, err := singleton.RunAgentCommand(ctx, s, model.NetInterfaceCommand)
+	if err != nil {
+		return nil, err
+	}
+	return model.ParseNetInterfaces(output, exclude)
+}
+
+// List watched units of server
+// @Summary List watched units of server
+// @Security BearerAuth
+// @Schemes
+// @Description Read the active state of the systemd units watched on the server through systemctl on the agent. Linux only, requires command execution to be enabled on the agent
+// @Tags auth required
+// @Param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.UnitStatus]
+// @Router /server/{id}/units [get]
+func listServerUnits(c *gin.Context) ([]*model.UnitStatus, error) {
+	s, err := getCommandServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	return singleton.UpdateUnitStatus(ctx, s)
+}
+
+// Set watched units of server
+// @Summary Set watched units of server
+// @Security BearerAuth
+// @Schemes
+// @Description Set the systemd units whose active state is polled every minute and can be alerted with unit_inactive rules
+// @Tags auth required
+// @Accept json
+// @Param id path uint true "Server ID"
+// @param request body []string true "Unit names, e.g. nginx.service"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /server/{id}/units [post]
+func setServerUnits(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var units []string
+	if err := c.ShouldBindJSON(&units); err != nil {
+		return nil, err
+	}
+	for _, unit := range units {
+		if !model.ValidUnitName(unit) {
+			return nil, singleton.Localizer.ErrorT("invalid unit name: %s", unit)
+		}
+	}
+
+	var s model.Server
+	if err := singleton.DB.First(&s, id).Error; err != nil {
+		return nil, singleton.Localizer.ErrorT("server id %d does not exist", id)
+	}
+	if !s.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	unitsRaw, err := json.Marshal(units)
+	if err != nil {
+		return nil, err
+	}
+	if err := singleton.DB.Model(&s).Update("watched_units_raw", string(unitsRaw)).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	if rs, ok := singleton.ServerShared.Get(s.ID); ok && rs != nil {
+		singleton.ServerShared.SetPolledStatus(rs, func() {
+			rs.WatchedUnits = units
+			rs.UnitStatus = nil
+		})
+	}
+	return nil, nil
+}
+
+// getCommandServer 返回路径参数 id 对应的服务器，用于需要在 Agent 上执行命令的接口
+func getCommandServer(c *gin.Context) (*model.Server, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := singleton.ServerShared.Get(id)
+	if !ok || s == nil {
+		return nil, singleton.Localizer.ErrorT("server not found")
+	}
+	if !s.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+	return s, nil
+}
+
 // Get server config
 // @Summary Get server config
 // @Security BearerAuth
@@ -248,7 +529,7 @@ func getServerConfig(c *gin.Context) (string, error) {
 // @Summary Set server config
 // @Security BearerAuth
 // @Schemes
-// @Description Set server config
+// @Description Push agent config to the listed servers and to every server in server_groups over the existing task stream. A non-empty config must be a JSON object; an empty config is sent as is, as before. The dashboard only forwards the config, which keys take effect without restarting is decided by the agent
 // @Tags auth required
 // @Accept json
 // @Param body body model.ServerConfigForm true "ServerConfigForm"
@@ -261,6 +542,29 @@ func setServerConfig(c *gin.Context) (*model.ServerTaskResponse, error) {
 		return nil, err
 	}
 
+	// 空配置与之前一样原样发送，其余必须是 JSON 对象，json.Unmarshal 遇到 null 时不报错，需要单独判断
+	if configForm.Config != "" {
+		var config map[string]any
+		if err := json.Unmarshal([]byte(configForm.Config), &config); err != nil {
+			return nil, singleton.Localizer.ErrorT("invalid config: %v", err)
+		}
+		if config == nil {
+			return nil, singleton.Localizer.ErrorT("invalid config: %v", "not a json object")
+		}
+	}
+
+	if len(configForm.ServerGroups) > 0 {
+		var groupServers []uint64
+		if err := singleton.DB.Model(&model.ServerGroupServer{}).Where("server_group_id IN (?)", configForm.ServerGroups).
+			Pluck("server_id", &groupServers).Error; err != nil {
+			return nil, newGormError("%v", err)
+		}
+		configForm.Servers = append(configForm.Servers, groupServers...)
+	}
+	// 同一台服务器只推送一次
+	slices.Sort(configForm.Servers)
+	configForm.Servers = slices.Compact(configForm.Servers)
+
 	var resp model.ServerTaskResponse
 	slist := singleton.ServerShared.GetList()
 	servers := make([]*model.Server, 0, len(configForm.Servers))
@@ -73,6 +73,8 @@ func listService(c *gin.Context) ([]*model.Service, error) {
 // @Description List service histories by server id
 // @Tags common
 // @param id path uint true "Server ID"
+// @Param country query string false "Country codes, comma separated"
+// @Param continent query string false "Continent codes, comma separated"
 // @Produce json
 // @Success 200 {object} model.CommonResponse[[]model.ServiceInfos]
 // @Router /service/{id} [get]
@@ -96,6 +98,10 @@ func listServiceHistory(c *gin.Context) ([]*model.ServiceInfos, error) {
 		return nil, singleton.Localizer.ErrorT("unauthorized")
 	}
 
+	if !model.GeoFilterCtx(c).Match(server) {
+		return []*model.ServiceInfos{}, nil
+	}
+
 	var serviceHistories []*model.ServiceHistory
 	if err := singleton.DB.Model(&model.ServiceHistory{}).Select("service_id, created_at, server_id, avg_delay").
 		Where("server_id = ?", id).Where("created_at >= ?", time.Now().Add(-24*time.Hour)).Order("service_id, created_at").
@@ -136,6 +142,8 @@ func listServiceHistory(c *gin.Context) ([]*model.ServiceInfos, error) {
 // @Schemes
 // @Description List server with service
 // @Tags common
+// @Param country query string false "Country codes, comma separated"
+// @Param continent query string false "Continent codes, comma separated"
 // @Produce json
 // @Success 200 {object} model.CommonResponse[[]uint64]
 // @Router /service/server [get]
@@ -150,6 +158,7 @@ func listServerWithServices(c *gin.Context) ([]uint64, error) {
 
 	_, isMember := c.Get(model.CtxKeyAuthorizedUser)
 	authorized := isMember // TODO || isViewPasswordVerfied
+	geoFilter := model.GeoFilterCtx(c)
 
 	var ret []uint64
 	for _, id := range serverIdsWithService {
@@ -157,7 +166,7 @@ func listServerWithServices(c *gin.Context) ([]uint64, error) {
 		if !ok || server == nil {
 			return nil, singleton.Localizer.ErrorT("server not found")
 		}
-		if !server.HideForGuest || authorized {
+		if (!server.HideForGuest || authorized) && geoFilter.Match(server) {
 			ret = append(ret, id)
 		}
 	}
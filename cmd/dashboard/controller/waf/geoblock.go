@@ -0,0 +1,78 @@
+package waf
+
+import (
+	"fmt"
+	"log"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// CheckAdminCountry 检查请求的真实 IP 是否属于 admin_allow_countries，没有配置时不限制
+// admin_allow_ips 中的地址与内网地址总是允许，查询不到国家的地址不允许
+func CheckAdminCountry(c *gin.Context) error {
+	allowed := singleton.Conf.AdminAllowCountries
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	ip := c.GetString(model.CtxKeyRealIPStr)
+	if ip == "" {
+		ip = c.RemoteIP()
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("invalid client ip %q", ip)
+	}
+	addr = addr.Unmap()
+	if slices.ContainsFunc(singleton.Conf.AdminAllowIPPrefixes(), func(p netip.Prefix) bool { return p.Contains(addr) }) {
+		return nil
+	}
+
+	country := "unknown"
+	if detail, err := geoip.LookupDetailStringContext(c.Request.Context(), addr.String()); err == nil {
+		if detail.Private {
+			return nil
+		}
+		if detail.CountryCode != "" {
+			country = strings.ToLower(detail.CountryCode)
+		}
+	}
+	if !slices.Contains(allowed, country) {
+		return fmt.Errorf("access from %s is not allowed", country)
+	}
+	return nil
+}
+
+// WarnGeoBlockWithoutProxy 在限制了后台国家、却没有配置 trusted_proxies 与 web_real_ip_header 时提示一次
+// 这时按连接地址判断国家，面板在反向代理后面时所有请求都来自代理的内网地址，总是被允许
+func WarnGeoBlockWithoutProxy() {
+	if len(singleton.Conf.AdminAllowCountries) == 0 || len(singleton.Conf.TrustedProxies) > 0 || singleton.Conf.WebRealIPHeader != "" {
+		return
+	}
+	log.Println("NEZHA>> admin_allow_countries is set without trusted_proxies or web_real_ip_header, the country is checked against the connecting address, requests through a reverse proxy on a private address are always allowed")
+}
+
+// GeoBlock 拒绝 CheckAdminCountry 不通过的请求，用于登录接口
+func GeoBlock(c *gin.Context) {
+	if err := CheckAdminCountry(c); err != nil {
+		ShowBlockPage(c, err)
+		return
+	}
+	c.Next()
+}
+
+// GeoBlockAdmin 在开启 admin_geo_block_all 时与 GeoBlock 相同，用于需要登录的接口
+func GeoBlockAdmin(c *gin.Context) {
+	if !singleton.Conf.AdminGeoBlockAll {
+		c.Next()
+		return
+	}
+	GeoBlock(c)
+}
@@ -0,0 +1,88 @@
+package waf
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// staticProvider 只返回 countries 中的国家，其他地址查询失败
+type staticProvider map[string]string
+
+func (staticProvider) Name() string { return "test" }
+
+func (p staticProvider) Lookup(_ context.Context, ip net.IP) (*geoip.Result, error) {
+	if country, ok := p[ip.String()]; ok {
+		return &geoip.Result{CountryCode: country}, nil
+	}
+	return nil, geoip.ErrNotFound
+}
+
+func TestCheckAdminCountry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	// 默认实例只查询 staticProvider，不读取内置数据库与覆盖表
+	t.Setenv("GEOIP_OFFLINE", "true")
+	t.Setenv("GEOIP_DB_PATH", filepath.Join(dir, "missing.mmdb"))
+	t.Setenv("GEOIP_WATCH", "0")
+	geoip.Register(staticProvider{"1.0.0.1": "JP", "2.0.0.1": "de", "3.0.0.1": "de", "5.0.0.1": ""})
+	if err := geoip.SetChain("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	config := "jwt_secret_key: test\nagent_secret_key: test\nadmin_allow_countries: [\"jp\"]\nadmin_allow_ips: [\"3.0.0.0/24\"]\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := singleton.InitConfigFromPath(path); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		ip   string
+		err  string // 为空时允许访问
+	}{
+		{"allowed country", "1.0.0.1", ""},
+		{"allowed country mapped to ipv6", "::ffff:1.0.0.1", ""},
+		{"denied country", "2.0.0.1", "access from de"},
+		{"allowed ip in denied country", "3.0.0.1", ""},
+		{"lookup failed", "4.0.0.1", "access from unknown"},
+		{"unknown country", "5.0.0.1", "access from unknown"},
+		{"private", "192.168.1.1", ""},
+		{"loopback", "127.0.0.1", ""},
+		{"ipv6 loopback", "::1", ""},
+		{"invalid", "not-an-ip", "invalid client ip"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/v1/login", nil)
+			c.Set(model.CtxKeyRealIPStr, tc.ip)
+			err := CheckAdminCountry(c)
+			if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Fatalf("CheckAdminCountry(%s) = %v, want %q", tc.ip, err, tc.err)
+			}
+		})
+	}
+
+	// 没有配置国家时不限制
+	singleton.Conf.AdminAllowCountries = nil
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/api/v1/login", nil)
+	c.Set(model.CtxKeyRealIPStr, "2.0.0.1")
+	if err := CheckAdminCountry(c); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
 	"github.com/nezhahq/nezha/pkg/utils"
 	"github.com/nezhahq/nezha/service/singleton"
 )
@@ -16,6 +17,12 @@ import (
 var errorPageTemplate string
 
 func RealIp(c *gin.Context) {
+	if trusted := singleton.Conf.TrustedProxyPrefixes(); len(trusted) > 0 {
+		c.Set(model.CtxKeyRealIPStr, geoip.RealIP(c.Request, trusted).String())
+		c.Next()
+		return
+	}
+
 	if singleton.Conf.WebRealIPHeader == "" {
 		c.Next()
 		return
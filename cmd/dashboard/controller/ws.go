@@ -168,9 +168,9 @@ func getServerStat(withPublicNote, authorized bool) ([]byte, error) {
 
 		servers := make([]model.StreamServer, 0, len(serverList))
 		for _, server := range serverList {
-			var countryCode string
+			var geoip model.GeoIP
 			if server.GeoIP != nil {
-				countryCode = server.GeoIP.CountryCode
+				geoip = *server.GeoIP
 			}
 			servers = append(servers, model.StreamServer{
 				ID:           server.ID,
@@ -179,7 +179,9 @@ func getServerStat(withPublicNote, authorized bool) ([]byte, error) {
 				DisplayIndex: server.DisplayIndex,
 				Host:         utils.IfOr(authorized, server.Host, server.Host.Filter()),
 				State:        server.State,
-				CountryCode:  countryCode,
+				CountryCode:  geoip.CountryCode,
+				CountryName:  geoip.CountryName,
+				FlagEmoji:    geoip.FlagEmoji,
 				LastActive:   server.LastActive,
 			})
 		}
@@ -23,6 +23,7 @@ import (
 	"github.com/nezhahq/nezha/cmd/dashboard/controller/waf"
 	"github.com/nezhahq/nezha/cmd/dashboard/rpc"
 	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
 	"github.com/nezhahq/nezha/pkg/utils"
 	"github.com/nezhahq/nezha/proto"
 	"github.com/nezhahq/nezha/service/singleton"
@@ -74,6 +75,35 @@ func initSystem(bus chan<- *model.Service) error {
 	if _, err := singleton.CronShared.AddFunc("0 0 * * * *", func() { singleton.RecordTransferHourlyUsage() }); err != nil {
 		return err
 	}
+
+	// 每分钟按国家采样服务器在线状态，每 10 分钟写入数据库
+	if _, err := singleton.CronShared.AddFunc("30 * * * * *", singleton.SampleServerGeoRollup); err != nil {
+		return err
+	}
+	if _, err := singleton.CronShared.AddFunc("0 */10 * * * *", singleton.FlushGeoRollups); err != nil {
+		return err
+	}
+
+	// 配置了硬盘报警规则时每 30 分钟读取一次 SMART 信息
+	if _, err := singleton.CronShared.AddFunc("0 */30 * * * *", singleton.PollDiskHealth); err != nil {
+		return err
+	}
+
+	// 每分钟读取一次服务器监控的 systemd unit 状态
+	if _, err := singleton.CronShared.AddFunc("0 * * * * *", singleton.PollUnitStatus); err != nil {
+		return err
+	}
+
+	// 每分钟读取一次服务器监控的 Docker 容器状态
+	if _, err := singleton.CronShared.AddFunc("0 * * * * *", singleton.PollContainerStatus); err != nil {
+		return err
+	}
+
+	// 按 IP 定位自动分组，服务器国家变化时也会立即同步
+	singleton.SyncGeoGroups()
+	if _, err := singleton.CronShared.AddFunc("0 15 * * * *", singleton.SyncGeoGroups); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -172,6 +202,10 @@ func main() {
 	}, func(c context.Context) error {
 		log.Println("NEZHA>> Graceful::START")
 		singleton.RecordTransferHourlyUsage()
+		if err := singleton.CloseAccessLog(c); err != nil {
+			log.Printf("NEZHA>> Failed to flush access logs: %v", err)
+		}
+		geoip.Close()
 		log.Println("NEZHA>> Graceful::END")
 		var err error
 		if muxServerHTTPS != nil {
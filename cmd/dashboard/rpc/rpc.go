@@ -15,6 +15,7 @@ import (
 
 	"github.com/hashicorp/go-uuid"
 	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
 	"github.com/nezhahq/nezha/pkg/utils"
 	"github.com/nezhahq/nezha/proto"
 	rpcService "github.com/nezhahq/nezha/service/rpc"
@@ -22,9 +23,10 @@ import (
 )
 
 func ServeRPC() *grpc.Server {
-	server := grpc.NewServer(grpc.ChainUnaryInterceptor(getRealIp, waf))
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(getRealIp, waf), grpc.ChainStreamInterceptor(getRealIpStream))
 	rpcService.NezhaHandlerSingleton = rpcService.NewNezhaHandler()
 	proto.RegisterNezhaServiceServer(server, rpcService.NezhaHandlerSingleton)
+	proto.RegisterGeoIPServiceServer(server, rpcService.NewGeoIPHandler(rpcService.NezhaHandlerSingleton.Auth))
 	return server
 }
 
@@ -37,18 +39,68 @@ func waf(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.
 }
 
 func getRealIp(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-	var ip, connectingIp string
+	ctx, err := withRealIP(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// getRealIpStream 让 RequestTask 等流式接口也能拿到 Agent 的真实 IP
+// 流式接口之前不检查真实 IP，缺少或无法解析真实 IP 请求头时回退到直连地址，不拒绝已有的 Agent
+func getRealIpStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := withRealIP(ss.Context())
+	if err != nil {
+		connectingIp := peerIP(ss.Context())
+		log.Printf("NEZHA>> gRPC %s: %v, using connecting IP %s\n", info.FullMethod, err, connectingIp)
+		ctx = context.WithValue(ss.Context(), model.CtxKeyConnectingIP{}, connectingIp)
+		ctx = context.WithValue(ctx, model.CtxKeyRealIP{}, connectingIp)
+	}
+	return handler(srv, &realIPStream{ServerStream: ss, ctx: ctx})
+}
+
+type realIPStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *realIPStream) Context() context.Context {
+	return s.ctx
+}
+
+// peerIP 返回 Agent 的直连 IP，获取不到时返回空字符串
+func peerIP(ctx context.Context) string {
 	p, ok := peer.FromContext(ctx)
 	if ok {
 		addrPort, err := netip.ParseAddrPort(p.Addr.String())
 		if err == nil {
-			connectingIp = addrPort.Addr().String()
+			return addrPort.Addr().String()
 		}
 	}
+	return ""
+}
+
+// withRealIP 把 Agent 的直连 IP 与真实 IP 写入 ctx
+func withRealIP(ctx context.Context) (context.Context, error) {
+	var ip string
+	connectingIp := peerIP(ctx)
 	ctx = context.WithValue(ctx, model.CtxKeyConnectingIP{}, connectingIp)
 
+	if trusted := singleton.Conf.TrustedProxyPrefixes(); len(trusted) > 0 {
+		remote, _ := netip.ParseAddr(connectingIp)
+		md, _ := metadata.FromIncomingContext(ctx)
+		var realIP string
+		if vals := md.Get("x-real-ip"); len(vals) > 0 {
+			realIP = vals[0]
+		}
+		if addr := geoip.ForwardedIP(remote, md.Get("x-forwarded-for"), realIP, trusted); addr.IsValid() {
+			ip = addr.String()
+		}
+		return context.WithValue(ctx, model.CtxKeyRealIP{}, ip), nil
+	}
+
 	if singleton.Conf.AgentRealIPHeader == "" {
-		return handler(ctx, req)
+		return ctx, nil
 	}
 
 	if singleton.Conf.AgentRealIPHeader == model.ConfigUsePeerIP {
@@ -71,8 +123,7 @@ func getRealIp(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler
 		log.Printf("NEZHA>> gRPC Agent Real IP: %s, connecting IP: %s\n", ip, connectingIp)
 	}
 
-	ctx = context.WithValue(ctx, model.CtxKeyRealIP{}, ip)
-	return handler(ctx, req)
+	return context.WithValue(ctx, model.CtxKeyRealIP{}, ip), nil
 }
 
 func DispatchTask(serviceSentinelDispatchBus <-chan *model.Service) {
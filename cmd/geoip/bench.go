@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	rf := addResolverFlags(fs)
+	n := fs.Int("n", 100000, "查询次数")
+	concurrency := fs.Int("c", 8, "并发数")
+	random := fs.Int("random", 1000, "没有指定 IP 时随机生成多少个公网 IPv4")
+	fs.Parse(args)
+	// bench 默认不访问在线接口，避免消耗额度
+	if !isFlagSet(fs, "offline") {
+		*rf.offline = true
+	}
+
+	var ips []net.IP
+	if fs.NArg() > 0 {
+		list, err := readIPs(fs.Args(), os.Stdin)
+		if err != nil {
+			return err
+		}
+		for _, s := range list {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return fmt.Errorf("invalid ip %q", s)
+			}
+			ips = append(ips, ip)
+		}
+	} else {
+		for range *random {
+			ips = append(ips, net.IPv4(byte(1+rand.N(222)), byte(rand.N(256)), byte(rand.N(256)), byte(1+rand.N(254))))
+		}
+	}
+	if len(ips) == 0 || *n <= 0 || *concurrency <= 0 {
+		return fmt.Errorf("nothing to do")
+	}
+
+	r := rf.resolver()
+	defer r.Close()
+	// 第一次查询会打开数据库，不计入结果
+	r.Lookup(ips[0])
+
+	var (
+		next      atomic.Int64
+		failed    atomic.Int64
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, *n)
+		wg        sync.WaitGroup
+	)
+	start := time.Now()
+	for range *concurrency {
+		wg.Go(func() {
+			local := make([]time.Duration, 0, *n / *concurrency + 1)
+			for i := next.Add(1) - 1; i < int64(*n); i = next.Add(1) - 1 {
+				t := time.Now()
+				if _, err := r.Lookup(ips[i%int64(len(ips))]); err != nil {
+					failed.Add(1)
+				}
+				local = append(local, time.Since(t))
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	slices.Sort(latencies)
+	percentile := func(p float64) time.Duration {
+		return latencies[min(int(float64(len(latencies))*p), len(latencies)-1)]
+	}
+	fmt.Printf("lookups: %d (%d failed), concurrency: %d, elapsed: %s\n", *n, failed.Load(), *concurrency, elapsed.Round(time.Millisecond))
+	fmt.Printf("qps: %.0f, p50: %s, p99: %s, max: %s\n", float64(*n)/elapsed.Seconds(), percentile(0.5), percentile(0.99), latencies[len(latencies)-1])
+	return nil
+}
+
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+func runBuild(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	output := fs.String("o", "custom.mmdb", "输出的 mmdb 路径")
+	overrides := fs.String("overrides", "", "纠错数据，多个文件用逗号分隔，覆盖输入文件中重叠的网段")
+	dbType := fs.String("type", builder.DefaultDatabaseType, "数据库的 database_type")
+	desc := fs.String("desc", "", "数据库的描述")
+	fs.Parse(args)
+	if fs.NArg() == 0 && *overrides == "" {
+		return errors.New("no input files")
+	}
+
+	base, err := loadAll(fs.Args())
+	if err != nil {
+		return err
+	}
+	var fixes []builder.Entry
+	if *overrides != "" {
+		if fixes, err = loadAll(strings.Split(*overrides, ",")); err != nil {
+			return err
+		}
+	}
+	opts := builder.Options{DatabaseType: *dbType, Description: *desc}
+	if err := builder.BuildFile(*output, opts, base, fixes); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d networks (%d overrides) to %s\n", len(base)+len(fixes), len(fixes), *output)
+	return nil
+}
+
+func loadAll(paths []string) ([]builder.Entry, error) {
+	var all []builder.Entry
+	for _, path := range paths {
+		entries, err := builder.Load(strings.TrimSpace(path))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, entries...)
+	}
+	return all, nil
+}
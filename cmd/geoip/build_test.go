@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// buildTestDB 用 build 命令把 1.0.0.0/24 → jp、2.0.0.0/24 → de 编译为 mmdb，其中 2.0.0.0/24 来自纠错数据
+func buildTestDB(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	ranges, fixes := filepath.Join(dir, "ranges.csv"), filepath.Join(dir, "fix.csv")
+	if err := os.WriteFile(ranges, []byte("cidr,country\n1.0.0.0/24,JP\n2.0.0.0/24,us\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fixes, []byte("2.0.0.0/24,de\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "custom.mmdb")
+	captureStdout(t, func() {
+		if err := runBuild([]string{"-o", out, "-desc", "test", "-overrides", fixes, ranges}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	path := buildTestDB(t)
+	r := geoip.New(
+		geoip.WithDBPaths(path),
+		geoip.WithOverridePaths(),
+		geoip.WithChain(geoip.ProviderMMDB),
+		geoip.WithOffline(true),
+		geoip.WithWatch(false),
+	)
+	defer r.Close()
+
+	for ip, want := range map[string]string{"1.0.0.1": "jp", "2.0.0.1": "de"} {
+		if got, err := r.Lookup(net.ParseIP(ip)); err != nil || got != want {
+			t.Fatalf("%s: got %q, %v, want %q", ip, got, err, want)
+		}
+	}
+
+	if err := runBuild([]string{"-o", filepath.Join(t.TempDir(), "empty.mmdb")}); err == nil {
+		t.Fatal("expected error without input files")
+	}
+}
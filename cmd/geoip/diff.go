@@ -0,0 +1,86 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	samples := fs.Int("samples", 20, "最多显示多少个变化的网段")
+	top := fs.Int("top", 10, "显示变化最多的前几种国家变化")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errors.New("usage: geoip diff [-samples n] <old.mmdb|embedded> <new.mmdb>")
+	}
+
+	oldPath, newPath := fs.Arg(0), fs.Arg(1)
+	if oldPath == "embedded" {
+		oldPath = ""
+	}
+	diff, err := geoip.DiffDBFiles(oldPath, newPath, *samples)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+
+	describe := func(md *geoip.DBMetadata) string {
+		name := md.Path
+		if name == "" {
+			name = md.Source
+		}
+		return fmt.Sprintf("%s (%s, built %s)", name, md.DatabaseType, md.BuildTime.Format("2006-01-02"))
+	}
+	fmt.Println("old:", describe(diff.Old))
+	fmt.Println("new:", describe(diff.New))
+	fmt.Printf("networks: %d, changed: %d, added: %d, removed: %d\n", diff.Networks, diff.Changed, diff.Added, diff.Removed)
+
+	type transition struct {
+		key   string
+		count int
+	}
+	transitions := make([]transition, 0, len(diff.Transitions))
+	for key, count := range diff.Transitions {
+		transitions = append(transitions, transition{key, count})
+	}
+	slices.SortFunc(transitions, func(a, b transition) int {
+		return cmp.Or(b.count-a.count, strings.Compare(a.key, b.key))
+	})
+	if len(transitions) > *top {
+		transitions = transitions[:*top]
+	}
+	if len(transitions) > 0 {
+		fmt.Println("\ntop changes:")
+		for _, t := range transitions {
+			from, to, _ := strings.Cut(t.key, ">")
+			fmt.Printf("  %-4s -> %-4s %d\n", orDash(from), orDash(to), t.count)
+		}
+	}
+	if len(diff.Samples) > 0 {
+		fmt.Println("\nsamples:")
+		for _, s := range diff.Samples {
+			fmt.Printf("  %-43s %-4s -> %s\n", s.Network, orDash(s.Old), orDash(s.New))
+		}
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
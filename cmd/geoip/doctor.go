@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	rf := addResolverFlags(fs)
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	timeout := fs.Duration("timeout", 30*time.Second, "整个自检的超时")
+	fs.Parse(args)
+
+	r := rf.resolver()
+	defer r.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	rep := r.Diagnose(ctx)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, c := range rep.Checks {
+			fmt.Fprintf(w, "[%s]\t%s\t%s\n", strings.ToUpper(string(c.Status)), c.Name, c.Detail)
+		}
+		w.Flush()
+	}
+	if !rep.OK {
+		return errors.New("some checks failed")
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// enrichChunk 是每批读取的行数，同一批中的 IP 去重后并发查询
+const enrichChunk = 1000
+
+// enrichColumns 是追加到每行末尾的列
+var enrichColumns = []string{"geo_country", "geo_asn", "geo_as_org", "geo_city"}
+
+// enricher 查询一批 IP，整个文件中相同的 IP 只查询一次
+type enricher struct {
+	r           *geoip.Resolver
+	concurrency int
+	results     map[string]*geoip.Result // 查询失败的 IP 为 nil
+	failed      int
+}
+
+func (e *enricher) resolve(ips []string) {
+	var pending []string
+	for _, ip := range ips {
+		if _, ok := e.results[ip]; ok || ip == "" {
+			continue
+		}
+		e.results[ip] = nil
+		pending = append(pending, ip)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, e.concurrency)
+	)
+	for _, ip := range pending {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			res, err := e.r.LookupDetailStringContext(context.Background(), ip)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				e.failed++
+				return
+			}
+			e.results[ip] = res
+		})
+	}
+	wg.Wait()
+}
+
+// values 返回 enrichColumns 对应的值，查不到的列为空
+func (e *enricher) values(ip string) []string {
+	values := make([]string, len(enrichColumns))
+	res := e.results[ip]
+	if res == nil {
+		return values
+	}
+	values[0] = res.Code()
+	if res.ASN != nil {
+		if res.ASN.Number != 0 {
+			values[1] = "AS" + strconv.FormatUint(uint64(res.ASN.Number), 10)
+		}
+		values[2] = res.ASN.Organization
+	}
+	if res.City != nil {
+		values[3] = res.City.Name
+	}
+	return values
+}
+
+func runEnrich(args []string) error {
+	fs := flag.NewFlagSet("enrich", flag.ExitOnError)
+	rf := addResolverFlags(fs)
+	format := fs.String("format", "", "输入格式，csv 或 jsonl，默认按文件扩展名判断")
+	column := fs.String("column", "ip", "IP 所在的列名或 JSON 字段名")
+	output := fs.String("o", "-", "输出文件，- 为标准输出")
+	concurrency := fs.Int("c", 16, "并发查询数")
+	fs.Parse(args)
+	if fs.NArg() > 1 || *concurrency <= 0 {
+		return errors.New("usage: geoip enrich [-format csv|jsonl] [-column ip] [-o out] [file | -]")
+	}
+
+	in := io.Reader(os.Stdin)
+	name := fs.Arg(0)
+	if name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".jsonl", ".ndjson", ".json":
+			*format = "jsonl"
+		default:
+			*format = "csv"
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+
+	r := rf.resolver()
+	defer r.Close()
+	e := &enricher{r: r, concurrency: *concurrency, results: make(map[string]*geoip.Result)}
+
+	var err error
+	switch *format {
+	case "csv":
+		err = enrichCSV(e, in, w, *column)
+	case "jsonl":
+		err = enrichJSONL(e, in, w, *column)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if e.failed > 0 {
+		fmt.Fprintf(os.Stderr, "geoip: %d of %d distinct ips could not be resolved\n", e.failed, len(e.results))
+	}
+	return nil
+}
+
+// enrichCSV 在表头与每行末尾追加 enrichColumns，第一行必须是表头
+func enrichCSV(e *enricher, in io.Reader, out io.Writer, column string) error {
+	cr := csv.NewReader(in)
+	cr.FieldsPerRecord = -1
+	cw := csv.NewWriter(out)
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	idx := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("column %q not found in header", column)
+	}
+	if err := cw.Write(append(header, enrichColumns...)); err != nil {
+		return err
+	}
+
+	for {
+		var chunk [][]string
+		for len(chunk) < enrichChunk {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			chunk = append(chunk, record)
+		}
+		if len(chunk) == 0 {
+			break
+		}
+		ips := make([]string, len(chunk))
+		for i, record := range chunk {
+			if idx < len(record) {
+				ips[i] = strings.TrimSpace(record[idx])
+			}
+		}
+		e.resolve(ips)
+		for i, record := range chunk {
+			if err := cw.Write(append(record, e.values(ips[i])...)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enrichJSONL 在每个 JSON 对象末尾追加 enrichColumns 字段，保留原有字段的顺序，空行原样输出
+func enrichJSONL(e *enricher, in io.Reader, out io.Writer, field string) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for {
+		var chunk [][]byte
+		for len(chunk) < enrichChunk && scanner.Scan() {
+			chunk = append(chunk, bytes.Clone(scanner.Bytes()))
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		ips := make([]string, len(chunk))
+		for i, raw := range chunk {
+			line++
+			if len(bytes.TrimSpace(raw)) == 0 {
+				continue
+			}
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			json.Unmarshal(obj[field], &ips[i])
+		}
+		e.resolve(ips)
+
+		for i, raw := range chunk {
+			raw = bytes.TrimSpace(raw)
+			if len(raw) == 0 {
+				fmt.Fprintln(out)
+				continue
+			}
+			body := bytes.TrimSpace(raw[1 : len(raw)-1])
+			var b bytes.Buffer
+			b.WriteByte('{')
+			b.Write(body)
+			for j, value := range e.values(ips[i]) {
+				if j > 0 || len(body) > 0 {
+					b.WriteByte(',')
+				}
+				key, _ := json.Marshal(enrichColumns[j])
+				val, _ := json.Marshal(value)
+				b.Write(key)
+				b.WriteByte(':')
+				b.Write(val)
+			}
+			b.WriteString("}\n")
+			if _, err := out.Write(b.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+}
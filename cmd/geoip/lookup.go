@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// resolverFlags 是 lookup、bench 等查询命令共用的参数，默认使用与面板相同的环境变量
+type resolverFlags struct {
+	offline *bool
+	noCache *bool
+}
+
+func addResolverFlags(fs *flag.FlagSet) resolverFlags {
+	return resolverFlags{
+		offline: fs.Bool("offline", false, "只使用离线库，不访问在线接口"),
+		noCache: fs.Bool("nocache", false, "不使用查询结果缓存（GEOIP_CACHE_FILE、GEOIP_REDIS_URL 等）"),
+	}
+}
+
+func (f resolverFlags) resolver() *geoip.Resolver {
+	opts := []geoip.Option{geoip.WithWatch(false)}
+	if *f.offline {
+		opts = append(opts, geoip.WithOffline(true))
+	}
+	if *f.noCache {
+		opts = append(opts, geoip.WithCache(nil), geoip.WithNegativeCache(0))
+	}
+	return geoip.NewFromEnv(opts...)
+}
+
+// readIPs 返回参数中的 IP，没有参数或参数为 - 时从标准输入逐行读取，忽略空行与 # 开头的注释
+func readIPs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) > 0 && (len(args) != 1 || args[0] != "-") {
+		return args, nil
+	}
+	var ips []string
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ips = append(ips, line)
+	}
+	return ips, scanner.Err()
+}
+
+// lookupOutput 是 lookup -json 每行输出的结果
+type lookupOutput struct {
+	IP     string        `json:"ip"`
+	Result *geoip.Result `json:"result,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+func runLookup(args []string) error {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	rf := addResolverFlags(fs)
+	asJSON := fs.Bool("json", false, "每行输出一个 JSON 对象")
+	fs.Parse(args)
+
+	ips, err := readIPs(fs.Args(), os.Stdin)
+	if err != nil {
+		return err
+	}
+	r := rf.resolver()
+	defer r.Close()
+
+	enc := json.NewEncoder(os.Stdout)
+	failed := 0
+	for _, ip := range ips {
+		res, err := r.LookupDetailStringContext(context.Background(), ip)
+		if err != nil {
+			failed++
+		}
+		if *asJSON {
+			out := lookupOutput{IP: ip, Result: res}
+			if err != nil {
+				out.Error = err.Error()
+			}
+			if err := enc.Encode(out); err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case err != nil:
+			fmt.Printf("%s\terror: %v\n", ip, err)
+		case res.Private:
+			fmt.Printf("%s\tprivate\n", ip)
+		default:
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", ip, res.Code(), res.CountryName, res.Source, res.Network)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lookups failed", failed, len(ips))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// captureStdout 返回 fn 写到标准输出的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+func TestReadIPs(t *testing.T) {
+	if ips, err := readIPs([]string{"1.1.1.1", "8.8.8.8"}, nil); err != nil || !slices.Equal(ips, []string{"1.1.1.1", "8.8.8.8"}) {
+		t.Fatalf("args: %v, %v", ips, err)
+	}
+	stdin := strings.NewReader("# comment\n1.1.1.1\n\n  8.8.8.8  \n")
+	for _, args := range [][]string{nil, {"-"}} {
+		if ips, err := readIPs(args, stdin); err != nil || !slices.Equal(ips, []string{"1.1.1.1", "8.8.8.8"}) {
+			t.Fatalf("stdin %v: %v, %v", args, ips, err)
+		}
+		stdin = strings.NewReader("1.1.1.1\n8.8.8.8\n")
+	}
+}
+
+func TestLookupCommand(t *testing.T) {
+	path := buildTestDB(t)
+	dir := t.TempDir()
+	t.Setenv("GEOIP_DB_PATH", path)
+	t.Setenv("GEOIP_ASN_DB_PATH", filepath.Join(dir, "missing-asn.mmdb"))
+	t.Setenv("GEOIP_CACHE_FILE", "")
+	t.Setenv("GEOIP_REDIS_URL", "")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runLookup([]string{"-offline", "-nocache", "-json", "1.0.0.1", "192.168.1.1", "not-an-ip"})
+	})
+	// 有查询失败时返回错误，但其他 IP 的结果照常输出
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Fatalf("err = %v", err)
+	}
+
+	var results []lookupOutput
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var res lookupOutput
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatalf("%q: %v", scanner.Text(), err)
+		}
+		results = append(results, res)
+	}
+	if len(results) != 3 {
+		t.Fatalf("output = %q", out)
+	}
+	if r := results[0]; r.IP != "1.0.0.1" || r.Result == nil || r.Result.CountryCode != "jp" || r.Result.Source != "external-mmdb" {
+		t.Fatalf("1.0.0.1 = %+v", r)
+	}
+	if r := results[1]; r.Result == nil || !r.Result.Private {
+		t.Fatalf("192.168.1.1 = %+v", r)
+	}
+	if r := results[2]; r.Error == "" || r.Result != nil {
+		t.Fatalf("not-an-ip = %+v", r)
+	}
+}
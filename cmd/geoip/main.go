@@ -0,0 +1,51 @@
+// geoip 是离线库相关的命令行工具
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"build":  {"build [-o out.mmdb] [-overrides fix.yaml] ranges.csv...  将 CIDR→国家 列表编译为 mmdb", runBuild},
+	"diff":   {"diff [-samples 20] [-json] <old.mmdb|embedded> <new.mmdb>  统计两个 mmdb 之间国家发生变化的网段", runDiff},
+	"lookup": {"lookup [-json] [-offline] [-nocache] [ip... | -]  使用与面板相同的配置查询 IP，没有参数时从标准输入读取", runLookup},
+	"meta":   {"meta [-json]  显示当前使用的离线库与查询顺序", runMeta},
+	"enrich": {"enrich [-format csv|jsonl] [-column ip] [-o out] [-c 16] [-offline] [file | -]  为 CSV/JSONL 中的每行追加国家、ASN 与城市列", runEnrich},
+	"doctor": {"doctor [-json] [-offline] [-timeout 30s]  检查离线库、token 与各数据源能否正常使用，在线数据源会各消耗一次额度", runDoctor},
+	"bench":  {"bench [-n 100000] [-c 8] [-offline=false] [ip... | -]  测试查询性能，默认只使用离线库", runBench},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: geoip <command> [arguments]")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  geoip "+commands[name].usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "geoip:", err)
+		os.Exit(1)
+	}
+}
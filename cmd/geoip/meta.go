@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// metaOutput 是 meta -json 的输出
+type metaOutput struct {
+	Database *geoip.DBMetadata `json:"database"`
+	Chain    []string          `json:"chain"`
+}
+
+func runMeta(args []string) error {
+	fs := flag.NewFlagSet("meta", flag.ExitOnError)
+	rf := addResolverFlags(fs)
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	r := rf.resolver()
+	defer r.Close()
+	md, err := r.Metadata()
+	if err != nil {
+		return err
+	}
+	var chain []string
+	for _, p := range r.Providers() {
+		chain = append(chain, p.Name())
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(metaOutput{Database: md, Chain: chain})
+	}
+	printMeta("database", md)
+	for _, layer := range md.Layers {
+		printMeta("layer", &layer)
+	}
+	if md.ASN != nil {
+		printMeta("asn", md.ASN)
+	}
+	fmt.Println("chain:", strings.Join(chain, " → "))
+	return nil
+}
+
+func printMeta(label string, md *geoip.DBMetadata) {
+	name := md.Path
+	if name == "" {
+		name = md.Source
+	}
+	stale := ""
+	if md.Stale {
+		stale = ", stale"
+	}
+	fmt.Printf("%s: %s (%s, built %s, %d nodes%s)\n", label, name, md.DatabaseType, md.BuildTime.Format("2006-01-02"), md.NodeCount, stale)
+}
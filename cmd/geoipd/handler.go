@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// batchWorkers 是 /v1/batch 的最大并发查询数
+const batchWorkers = 16
+
+// lookupResponse 是一个 IP 的查询结果，Error 不为空时 Result 为空
+type lookupResponse struct {
+	IP     string        `json:"ip"`
+	Result *geoip.Result `json:"result,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type handler struct {
+	r        *geoip.Resolver
+	maxBatch int
+}
+
+func newHandler(r *geoip.Resolver, maxBatch int) http.Handler {
+	h := &handler{r: r, maxBatch: maxBatch}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(r.Collector())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/lookup", h.lookup)
+	mux.HandleFunc("POST /v1/batch", h.batch)
+	mux.HandleFunc("GET /v1/metadata", h.metadata)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.Handle("GET /metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// lookup 处理 GET /v1/lookup?ip=1.2.3.4
+func (h *handler) lookup(w http.ResponseWriter, req *http.Request) {
+	ip := req.URL.Query().Get("ip")
+	if ip == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing ip parameter"))
+		return
+	}
+	res, err := h.resolve(req, ip)
+	status := http.StatusOK
+	switch {
+	case err == nil:
+	case errors.Is(err, geoip.ErrInvalidIP):
+		status = http.StatusBadRequest
+	case errors.Is(err, geoip.ErrNotFound):
+		status = http.StatusNotFound
+	default:
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, res)
+}
+
+// batch 处理 POST /v1/batch，请求体为 IP 字符串数组，按请求的顺序返回每个 IP 的结果
+func (h *handler) batch(w http.ResponseWriter, req *http.Request) {
+	var ips []string
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&ips); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(ips) > h.maxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("too many ips: %d, at most %d", len(ips), h.maxBatch))
+		return
+	}
+
+	results := make([]lookupResponse, len(ips))
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			results[i], _ = h.resolve(req, ip)
+		})
+	}
+	wg.Wait()
+	writeJSON(w, http.StatusOK, results)
+}
+
+// metadata 处理 GET /v1/metadata
+func (h *handler) metadata(w http.ResponseWriter, req *http.Request) {
+	md, err := h.r.Metadata()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, md)
+}
+
+func (h *handler) resolve(req *http.Request, ip string) (lookupResponse, error) {
+	res, err := h.r.LookupDetailStringContext(req.Context(), ip)
+	out := lookupResponse{IP: ip, Result: res}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	return out, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
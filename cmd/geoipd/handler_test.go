@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+// newTestHandler 返回只查询临时 mmdb 的 handler，库中只有 1.0.0.0/24 → jp
+func newTestHandler(t *testing.T, maxBatch int) http.Handler {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "country.mmdb")
+	entries := []builder.Entry{{Network: netip.MustParsePrefix("1.0.0.0/24"), Country: "jp"}}
+	if err := builder.BuildFile(path, builder.Options{Description: "test"}, entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	r := geoip.New(
+		geoip.WithDBPaths(path),
+		geoip.WithASNDBPaths(filepath.Join(dir, "missing-asn.mmdb")),
+		geoip.WithOverridePaths(),
+		geoip.WithChain(geoip.ProviderMMDB),
+		geoip.WithOffline(true),
+		geoip.WithWatch(false),
+	)
+	t.Cleanup(func() { r.Close() })
+	return newHandler(r, maxBatch)
+}
+
+func TestLookup(t *testing.T) {
+	h := newTestHandler(t, 10)
+	cases := []struct {
+		query   string
+		status  int
+		country string
+		private bool
+		err     string
+	}{
+		{"ip=1.0.0.1", http.StatusOK, "jp", false, ""},
+		{"ip=192.168.1.1", http.StatusOK, "", true, ""},
+		{"ip=::1", http.StatusOK, "", true, ""},
+		{"ip=not-an-ip", http.StatusBadRequest, "", false, "invalid"},
+		{"ip=8.8.8.8", http.StatusNotFound, "", false, "not found"},
+		{"", http.StatusBadRequest, "", false, "missing ip parameter"},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/v1/lookup?"+c.query, nil))
+		if w.Code != c.status {
+			t.Fatalf("%s: status = %d, want %d, body %s", c.query, w.Code, c.status, w.Body)
+		}
+		var resp lookupResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: %v", c.query, err)
+		}
+		if c.err != "" {
+			if !strings.Contains(resp.Error, c.err) {
+				t.Fatalf("%s: error = %q, want %q", c.query, resp.Error, c.err)
+			}
+			continue
+		}
+		if resp.Result == nil || resp.Result.CountryCode != c.country || resp.Result.Private != c.private {
+			t.Fatalf("%s: result = %+v", c.query, resp.Result)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	h := newTestHandler(t, 3)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/v1/batch", strings.NewReader(`["1.0.0.1","bad","10.0.0.1"]`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	var results []lookupResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	// 按请求的顺序返回，单个 IP 失败不影响其他结果
+	if len(results) != 3 || results[0].IP != "1.0.0.1" || results[0].Result.CountryCode != "jp" ||
+		results[1].Error == "" || results[1].Result != nil || !results[2].Result.Private {
+		t.Fatalf("results = %+v", results)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/v1/batch", strings.NewReader(`["1.0.0.1","1.0.0.2","1.0.0.3","1.0.0.4"]`)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("too many ips: status = %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/v1/batch", strings.NewReader(`{"ip":"1.0.0.1"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid body: status = %d", w.Code)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	h := newTestHandler(t, 10)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/v1/metadata", nil))
+	var md geoip.DBMetadata
+	if err := json.Unmarshal(w.Body.Bytes(), &md); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s, %v", w.Code, w.Body, err)
+	}
+	if !strings.HasSuffix(md.Path, "country.mmdb") || md.DatabaseType != builder.DefaultDatabaseType {
+		t.Fatalf("metadata = %+v", md)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("healthz = %d %s", w.Code, w.Body)
+	}
+}
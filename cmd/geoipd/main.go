@@ -0,0 +1,47 @@
+// geoipd 通过 HTTP 提供与面板相同的 IP 定位查询，查询顺序、离线库与缓存都使用相同的 GEOIP_* 环境变量
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+func main() {
+	listen := flag.String("listen", ":8090", "监听地址")
+	maxBatch := flag.Int("max-batch", 1000, "/v1/batch 每次最多查询的 IP 数")
+	flag.Parse()
+
+	r := geoip.NewFromEnv()
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           newHandler(r, *maxBatch),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("NEZHA>> geoipd listening on %s", *listen)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("NEZHA>> geoipd: %v", err)
+	}
+	// 持久化的缓存在 Close 时写入文件
+	if err := r.Close(); err != nil {
+		log.Printf("NEZHA>> geoipd: %v", err)
+	}
+}
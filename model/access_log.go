@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// 访问记录的类型
+const (
+	AccessEventLogin       = "login"        // 登录成功
+	AccessEventLoginFailed = "login_failed" // 登录失败
+	AccessEventRequest     = "request"      // 已登录用户修改数据的请求
+)
+
+// AccessLog 是后台的访问记录，记录时按 IP 查询国家与 ASN
+type AccessLog struct {
+	ID        uint64    `gorm:"primaryKey" json:"id,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at,omitempty"`
+	Event     string    `gorm:"index" json:"event,omitempty"`
+	UserID    uint64    `gorm:"index" json:"user_id,omitempty"`
+	Username  string    `json:"username,omitempty"` // 登录失败时为尝试的用户名
+	IP        string    `gorm:"index" json:"ip,omitempty"`
+	Country   string    `gorm:"index" json:"country,omitempty"` // 2 位小写国家码，内网地址或查不到时为空
+	ASN       uint      `json:"asn,omitempty"`
+	ASOrg     string    `json:"as_org,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// 分批升级的状态
+const (
+	AgentRolloutPending  = "pending"
+	AgentRolloutRunning  = "running"
+	AgentRolloutDone     = "done"
+	AgentRolloutFailed   = "failed"
+	AgentRolloutCanceled = "canceled"
+)
+
+// AgentUpgradeTask 是分批升级时 TaskTypeUpgrade 的 Data（JSON），Data 为空时 Agent 升级到上游最新版本
+//
+// 支持的 Agent 把 URL 中的 {os}、{arch} 替换为自身的 GOOS、GOARCH 后下载，
+// 下载文件的 SHA256 必须等于 Checksums 中该文件名对应的值，否则放弃升级并继续运行当前版本；
+// 替换成功后以 Version 重新上报。不认识 Data 的旧版 Agent 仍会升级到最新版本，版本对不上时该组会超时失败
+type AgentUpgradeTask struct {
+	Version   string            `json:"version"`
+	URL       string            `json:"url"`       // 如 https://example.com/v1.12.0/nezha-agent_{os}_{arch}.zip
+	Checksums map[string]string `json:"checksums"` // 文件名: 小写十六进制 SHA256
+}
+
+// Validate 检查下载地址与校验值，每个文件都必须有 SHA256
+func (t *AgentUpgradeTask) Validate() error {
+	if t.Version == "" {
+		return errors.New("version is not set")
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid url %q, an https url is required", t.URL)
+	}
+	if len(t.Checksums) == 0 {
+		return errors.New("checksums are not set")
+	}
+	for name, sum := range t.Checksums {
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != 32 || sum != strings.ToLower(sum) {
+			return fmt.Errorf("invalid sha256 of %s", name)
+		}
+	}
+	if !strings.Contains(t.URL, "{os}") && !strings.Contains(t.URL, "{arch}") {
+		// 所有平台使用同一个文件时必须有它的校验值
+		if _, ok := t.Checksums[path.Base(u.Path)]; !ok {
+			return fmt.Errorf("checksum of %s is not set", path.Base(u.Path))
+		}
+	}
+	return nil
+}
+
+type AgentRolloutForm struct {
+	Version      string            `json:"version" minLength:"1"`                     // 目标版本，Agent 重连后上报的版本与之相同才算升级成功
+	URL          string            `json:"url" minLength:"1"`                         // 见 AgentUpgradeTask.URL
+	Checksums    map[string]string `json:"checksums"`                                 // 见 AgentUpgradeTask.Checksums
+	ServerGroups []uint64          `json:"server_groups" minItems:"1"`                // 按顺序升级的服务器分组，前一组全部成功后才升级下一组
+	StageTimeout uint64            `json:"stage_timeout,omitempty" default:"600"`     // 每组的等待时间 (秒)，超时仍有服务器没有升级成功时停止
+	StageDelay   uint64            `json:"stage_delay,omitempty" validate:"optional"` // 每组成功后等待多久再升级下一组 (秒)
+}
+
+// AgentRolloutStage 是分批升级中的一个服务器分组
+type AgentRolloutStage struct {
+	ServerGroupID uint64   `json:"server_group_id"`
+	Status        string   `json:"status"`
+	Servers       []uint64 `json:"servers"`
+	Upgraded      []uint64 `json:"upgraded,omitempty" validate:"optional"`
+	Offline       []uint64 `json:"offline,omitempty" validate:"optional"` // 开始升级时不在线，不影响结果
+}
+
+// AgentRollout 是一次按分组分批升级 Agent 的进度
+type AgentRollout struct {
+	Version    string               `json:"version"`
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty" validate:"optional"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt time.Time            `json:"finished_at,omitempty" validate:"optional"`
+	Stages     []*AgentRolloutStage `json:"stages"`
+}
+
+// AgentVersionMatch 比较 Agent 上报的版本与目标版本，忽略 v 前缀
+func AgentVersionMatch(reported, target string) bool {
+	return reported != "" && strings.TrimPrefix(reported, "v") == strings.TrimPrefix(target, "v")
+}
@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+func TestAgentUpgradeTask(t *testing.T) {
+	sum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	for _, c := range []struct {
+		task AgentUpgradeTask
+		ok   bool
+	}{
+		{AgentUpgradeTask{Version: "1.12.0", URL: "https://example.com/nezha-agent_{os}_{arch}.zip", Checksums: map[string]string{"nezha-agent_linux_amd64.zip": sum}}, true},
+		{AgentUpgradeTask{Version: "1.12.0", URL: "https://example.com/nezha-agent.zip", Checksums: map[string]string{"nezha-agent.zip": sum}}, true},
+		{AgentUpgradeTask{Version: "1.12.0", URL: "https://example.com/nezha-agent.zip", Checksums: map[string]string{"other.zip": sum}}, false},
+		{AgentUpgradeTask{Version: "1.12.0", URL: "http://example.com/nezha-agent.zip", Checksums: map[string]string{"nezha-agent.zip": sum}}, false},
+		{AgentUpgradeTask{Version: "1.12.0", URL: "https://example.com/nezha-agent.zip"}, false},
+		{AgentUpgradeTask{Version: "1.12.0", URL: "https://example.com/nezha-agent.zip", Checksums: map[string]string{"nezha-agent.zip": "abc"}}, false},
+		{AgentUpgradeTask{URL: "https://example.com/nezha-agent.zip", Checksums: map[string]string{"nezha-agent.zip": sum}}, false},
+	} {
+		if err := c.task.Validate(); (err == nil) != c.ok {
+			t.Fatalf("Validate(%+v) = %v", c.task, err)
+		}
+	}
+}
+
+func TestAgentVersionMatch(t *testing.T) {
+	for _, c := range []struct {
+		reported, target string
+		match            bool
+	}{
+		{"1.12.0", "1.12.0", true},
+		{"v1.12.0", "1.12.0", true},
+		{"1.12.0", "v1.12.0", true},
+		{"1.11.3", "1.12.0", false},
+		{"", "", false},
+		{"1.12.0-rc1", "1.12.0", false},
+	} {
+		if AgentVersionMatch(c.reported, c.target) != c.match {
+			t.Fatalf("AgentVersionMatch(%q, %q) != %v", c.reported, c.target, c.match)
+		}
+	}
+}
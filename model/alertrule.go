@@ -73,6 +73,16 @@ func (r *AlertRule) Snapshot(cycleTransferStats *CycleTransferStats, server *Ser
 	return point
 }
 
+// CountryChange 返回服务器最近一次国家变化前后的国家码，没有国家变化规则或没有变化过时返回 false
+func (r *AlertRule) CountryChange(serverID uint64) (from, to string, ok bool) {
+	for _, rule := range r.Rules {
+		if change, found := rule.CountryChanges[serverID]; rule.IsCountryChangeRule() && found {
+			return change[0], change[1], true
+		}
+	}
+	return "", "", false
+}
+
 // Check 传入包含当前报警规则下所有type检查结果 返回报警持续时间与是否通过报警检查(通过则返回true)
 func (r *AlertRule) Check(points [][]bool) (int, bool) {
 	var hasPassedRule bool
@@ -80,8 +90,8 @@ func (r *AlertRule) Check(points [][]bool) (int, bool) {
 
 	for ruleIndex, rule := range r.Rules {
 		duration := int(rule.Duration)
-		if rule.IsTransferDurationRule() {
-			// 循环区间流量报警
+		if rule.IsTransferDurationRule() || rule.IsCountryChangeRule() {
+			// 循环区间流量报警与国家变化报警
 			if durations[ruleIndex] < 1 {
 				durations[ruleIndex] = 1
 			}
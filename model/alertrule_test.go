@@ -18,6 +18,13 @@ func TestAlertRules(t *testing.T) {
 	t.Run("OfflineRules", testOfflineRules)
 	t.Run("GeneralRules", testGeneralRules)
 	t.Run("CombinedRules", testCombinedRules)
+	t.Run("CountryChangeRules", testCountryChangeRules)
+	t.Run("GPURules", testGPURules)
+	t.Run("TemperatureRules", testTemperatureRules)
+	t.Run("DiskRules", testDiskRules)
+	t.Run("UnitRules", testUnitRules)
+	t.Run("ContainerRules", testContainerRules)
+	t.Run("CustomMetricRules", testCustomMetricRules)
 }
 
 func testCycleRules(t *testing.T) {
@@ -294,6 +301,123 @@ func testCombinedRules(t *testing.T) {
 	}
 }
 
+func testCountryChangeRules(t *testing.T) {
+	rule := &AlertRule{Rules: []*Rule{{Type: "country_changed"}}}
+	server := &Server{Common: Common{ID: 1}, GeoIP: &GeoIP{}}
+
+	var points [][]bool
+	for _, code := range []string{"", "jp", "jp", "us", "us"} {
+		server.GeoIP.CountryCode = code
+		points = append(points, rule.Snapshot(nil, server, nil))
+	}
+	assertEq(t, "CountryChangePoints", true, slices.Equal(slices.Concat(points...), []bool{true, true, true, false, true}))
+
+	_, passed := rule.Check(points[:4])
+	assertEq(t, "CountryChangeFail", false, passed)
+	_, passed = rule.Check(points)
+	assertEq(t, "CountryChangePass", true, passed)
+
+	from, to, ok := rule.CountryChange(server.ID)
+	assertEq(t, "CountryChange", true, ok && from == "jp" && to == "us")
+}
+
+func testGPURules(t *testing.T) {
+	rule := &Rule{Type: "gpu_max", Max: 90}
+	server := &Server{Common: Common{ID: 1}, State: &HostState{}}
+	assertEq(t, "NoGPU", true, rule.Snapshot(nil, server, nil))
+
+	server.State.GPU = []float64{20, 95}
+	assertEq(t, "GPUOverMax", false, rule.Snapshot(nil, server, nil))
+	server.State.GPU = []float64{20, 50}
+	assertEq(t, "GPUUnderMax", true, rule.Snapshot(nil, server, nil))
+
+	server.State.GPU = nil
+	server.State.GPUs = []GPUState{
+		{Name: "NVIDIA A100", Utilization: 30, MemoryUsed: 38 << 30, MemoryTotal: 40 << 30, Temperature: 85},
+		{Name: "NVIDIA A100", Utilization: 96, MemoryUsed: 1 << 30, MemoryTotal: 40 << 30, Temperature: 60},
+	}
+	assertEq(t, "GPUsOverMax", false, rule.Snapshot(nil, server, nil))
+
+	memory := &Rule{Type: "gpu_memory", Max: 90}
+	assertEq(t, "GPUMemoryOverMax", false, memory.Snapshot(nil, server, nil))
+	temperature := &Rule{Type: "gpu_temperature_max", Max: 90}
+	assertEq(t, "GPUTemperatureUnderMax", true, temperature.Snapshot(nil, server, nil))
+	temperature.Max = 80
+	assertEq(t, "GPUTemperatureOverMax", false, temperature.Snapshot(nil, server, nil))
+
+	server.State.GPUs = nil
+	assertEq(t, "NoGPUMemory", true, memory.Snapshot(nil, server, nil))
+}
+
+func testTemperatureRules(t *testing.T) {
+	server := &Server{Common: Common{ID: 1}, State: &HostState{Temperatures: []SensorTemperature{
+		{Name: "coretemp_package_id_0", Temperature: 70},
+		{Name: "nvme_composite_0", Temperature: 85},
+		{Name: "acpitz", Temperature: 0},
+	}}}
+
+	rule := &Rule{Type: "temperature_max", Max: 80}
+	assertEq(t, "AnySensor", false, rule.Snapshot(nil, server, nil))
+	rule.Sensor = "CoreTemp"
+	assertEq(t, "CPUSensor", true, rule.Snapshot(nil, server, nil))
+	rule.Sensor = "nvme"
+	assertEq(t, "NVMeSensor", false, rule.Snapshot(nil, server, nil))
+	// 没有匹配的传感器时不报警
+	rule.Sensor = "acpitz"
+	assertEq(t, "NoSensor", true, rule.Snapshot(nil, server, nil))
+}
+
+func testDiskRules(t *testing.T) {
+	server := &Server{Common: Common{ID: 1}, State: &HostState{}}
+	failed := &Rule{Type: "disk_failed"}
+	reallocated := &Rule{Type: "disk_reallocated", Max: 10}
+	wear := &Rule{Type: "disk_wear", Max: 80}
+
+	assertEq(t, "NoDiskFailed", true, failed.Snapshot(nil, server, nil))
+	assertEq(t, "NoDiskWear", true, wear.Snapshot(nil, server, nil))
+
+	server.Disks = []*DiskHealth{{Passed: true, ReallocatedSectors: 3, WearPercent: 90}, {Passed: true, ReallocatedSectors: 20}}
+	assertEq(t, "DiskPassed", true, failed.Snapshot(nil, server, nil))
+	assertEq(t, "DiskReallocated", false, reallocated.Snapshot(nil, server, nil))
+	assertEq(t, "DiskWear", false, wear.Snapshot(nil, server, nil))
+
+	server.Disks[1].Passed = false
+	assertEq(t, "DiskFailed", false, failed.Snapshot(nil, server, nil))
+}
+
+func testUnitRules(t *testing.T) {
+	rule := &Rule{Type: "unit_inactive"}
+	server := &Server{Common: Common{ID: 1}, State: &HostState{}}
+	assertEq(t, "NoUnits", true, rule.Snapshot(nil, server, nil))
+
+	server.UnitStatus = []*UnitStatus{{Name: "nginx.service", Active: true}}
+	assertEq(t, "UnitActive", true, rule.Snapshot(nil, server, nil))
+	server.UnitStatus = append(server.UnitStatus, &UnitStatus{Name: "redis.service", State: "failed"})
+	assertEq(t, "UnitFailed", false, rule.Snapshot(nil, server, nil))
+}
+
+func testContainerRules(t *testing.T) {
+	rule := &Rule{Type: "container_exited"}
+	server := &Server{Common: Common{ID: 1}, State: &HostState{}}
+	assertEq(t, "NoContainers", true, rule.Snapshot(nil, server, nil))
+
+	server.ContainerStatus = []*ContainerStatus{{Name: "web", State: "running", Running: true}}
+	assertEq(t, "ContainerRunning", true, rule.Snapshot(nil, server, nil))
+	server.ContainerStatus = append(server.ContainerStatus, &ContainerStatus{Name: "cache", State: "exited"})
+	assertEq(t, "ContainerExited", false, rule.Snapshot(nil, server, nil))
+}
+
+func testCustomMetricRules(t *testing.T) {
+	rule := &Rule{Type: "custom_metric", Metric: "queue.size", Max: 100}
+	server := &Server{Common: Common{ID: 1}, State: &HostState{}}
+	assertEq(t, "NoMetric", true, rule.Snapshot(nil, server, nil))
+
+	server.Metrics = map[string]float64{"queue.size": 50}
+	assertEq(t, "BelowMax", true, rule.Snapshot(nil, server, nil))
+	server.Metrics = map[string]float64{"queue.size": 150, "queue": 1}
+	assertEq(t, "AboveMax", false, rule.Snapshot(nil, server, nil))
+}
+
 func repeat[S ~[]E, E any](x S, count int) []S {
 	var slices []S
 	for range count {
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"strings"
@@ -54,9 +56,10 @@ type Config struct {
 
 	AvgPingCount int `koanf:"avg_ping_count" json:"avg_ping_count,omitempty"`
 
-	Debug          bool   `koanf:"debug" json:"debug,omitempty"`           // debug模式开关
-	Location       string `koanf:"location" json:"location,omitempty"`     // 时区，默认为 Asia/Shanghai
-	ForceAuth      bool   `koanf:"force_auth" json:"force_auth,omitempty"` // 强制要求认证
+	Debug          bool   `koanf:"debug" json:"debug,omitempty"`                   // debug模式开关
+	Location       string `koanf:"location" json:"location,omitempty"`             // 时区，默认为 Asia/Shanghai
+	ForceAuth      bool   `koanf:"force_auth" json:"force_auth,omitempty"`         // 强制要求认证
+	EnableMetrics  bool   `koanf:"enable_metrics" json:"enable_metrics,omitempty"` // 开启 /metrics，供 Prometheus 采集
 	AgentSecretKey string `koanf:"agent_secret_key" json:"agent_secret_key,omitempty"`
 	JWTTimeout     int    `koanf:"jwt_timeout" json:"jwt_timeout,omitempty"` // JWT token过期时间（小时）
 
@@ -64,6 +67,19 @@ type Config struct {
 	ListenPort   uint16 `koanf:"listen_port" json:"listen_port,omitempty"`
 	ListenHost   string `koanf:"listen_host" json:"listen_host,omitempty"`
 
+	// 受信任的反向代理，IP 或 CIDR，配置后前端和 Agent 的真实IP都从 X-Forwarded-For 中跳过这些代理获取，优先于 web_real_ip_header 与 agent_real_ip_header
+	TrustedProxies []string `koanf:"trusted_proxies" json:"trusted_proxies,omitempty"`
+
+	// 按 IP 定位自动把服务器加入分组，continent 按洲、country 按国家，留空不自动分组
+	GeoAutoGroup string `koanf:"geo_auto_group" json:"geo_auto_group,omitempty"`
+
+	// 只允许从这些国家登录后台，2 位国家码，留空不限制；内网地址与 AdminAllowIPs 中的地址不受限制
+	// 按真实 IP 判断，面板在反向代理后面时需要配置 TrustedProxies 或 WebRealIPHeader，否则连接地址是代理的内网地址，总是被允许
+	AdminAllowCountries []string `koanf:"admin_allow_countries" json:"admin_allow_countries,omitempty"`
+	AdminAllowIPs       []string `koanf:"admin_allow_ips" json:"admin_allow_ips,omitempty"`
+	// 开启后限制整个后台，包括后台页面与需要登录的接口，否则只限制登录
+	AdminGeoBlockAll bool `koanf:"admin_geo_block_all" json:"admin_geo_block_all,omitempty"`
+
 	// oauth2 配置
 	Oauth2 map[string]*Oauth2Config `koanf:"oauth2" json:"oauth2,omitempty"`
 
@@ -72,6 +88,9 @@ type Config struct {
 
 	k        *koanf.Koanf `json:"-"`
 	filePath string       `json:"-"`
+
+	trustedProxies []netip.Prefix
+	adminAllowIPs  []netip.Prefix
 }
 
 type HTTPSConf struct {
@@ -148,6 +167,25 @@ func (c *Config) Read(path string, frontendTemplates []FrontendTemplate) error {
 		}
 	}
 
+	c.trustedProxies, err = utils.ParsePrefixes(c.TrustedProxies...)
+	if err != nil {
+		return fmt.Errorf("invalid trusted_proxies: %w", err)
+	}
+
+	c.adminAllowIPs, err = utils.ParsePrefixes(c.AdminAllowIPs...)
+	if err != nil {
+		return fmt.Errorf("invalid admin_allow_ips: %w", err)
+	}
+	for i, code := range c.AdminAllowCountries {
+		c.AdminAllowCountries[i] = strings.ToLower(strings.TrimSpace(code))
+	}
+
+	switch c.GeoAutoGroup {
+	case "", GeoGroupContinent, GeoGroupCountry:
+	default:
+		return fmt.Errorf("invalid geo_auto_group %q, must be %s or %s", c.GeoAutoGroup, GeoGroupContinent, GeoGroupCountry)
+	}
+
 	// Add JWTTimeout default check
 	if c.JWTTimeout == 0 {
 		c.JWTTimeout = 1
@@ -166,6 +204,16 @@ func (c *Config) Read(path string, frontendTemplates []FrontendTemplate) error {
 	return nil
 }
 
+// TrustedProxyPrefixes 返回解析后的 TrustedProxies
+func (c *Config) TrustedProxyPrefixes() []netip.Prefix {
+	return c.trustedProxies
+}
+
+// AdminAllowIPPrefixes 返回解析后的 AdminAllowIPs
+func (c *Config) AdminAllowIPPrefixes() []netip.Prefix {
+	return c.adminAllowIPs
+}
+
 // Save 保存配置文件
 func (c *Config) Save() error {
 	return c.save()
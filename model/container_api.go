@@ -0,0 +1,149 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// ContainerCommand 是获取容器列表时让 Agent 执行的命令，docker stats 只包含运行中的容器
+const ContainerCommand = `docker ps -a --format '{{json .}}' && echo --- && docker stats --no-stream --format '{{json .}}'`
+
+// ContainerStatusCommand 是定时查询监控中的容器时让 Agent 执行的命令，不运行耗时的 docker stats
+const ContainerStatusCommand = `docker ps -a --format '{{json .}}'`
+
+var containerNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidContainerName 检查 Docker 容器名称
+func ValidContainerName(name string) bool {
+	return containerNameRe.MatchString(name)
+}
+
+// Container 是服务器上的一个 Docker 容器
+type Container struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Image      string  `json:"image"`
+	State      string  `json:"state"`  // running、exited 等
+	Status     string  `json:"status"` // 如 Up 2 hours、Exited (0) 3 days ago
+	CPUPercent float64 `json:"cpu_percent"`
+	MemUsed    uint64  `json:"mem_used"`
+	MemPercent float64 `json:"mem_percent"`
+}
+
+type dockerPs struct {
+	ID     string
+	Names  string
+	Image  string
+	State  string
+	Status string
+}
+
+type dockerStats struct {
+	Name     string
+	CPUPerc  string
+	MemPerc  string
+	MemUsage string
+}
+
+// ParseContainers 解析 ContainerCommand 的输出，运行中的容器排在前面，再按名称排列
+func ParseContainers(output string) ([]*Container, error) {
+	ps, stats, _ := strings.Cut(output, "\n---\n")
+
+	containers := []*Container{}
+	index := make(map[string]*Container)
+	scanner := bufio.NewScanner(strings.NewReader(ps))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var p dockerPs
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			return nil, fmt.Errorf("invalid docker ps output %q: %w", line, err)
+		}
+		c := &Container{ID: p.ID, Name: p.Names, Image: p.Image, State: p.State, Status: p.Status}
+		containers = append(containers, c)
+		index[c.Name] = c
+	}
+
+	scanner = bufio.NewScanner(strings.NewReader(stats))
+	for scanner.Scan() {
+		var s dockerStats
+		if err := json.Unmarshal([]byte(scanner.Text()), &s); err != nil {
+			continue
+		}
+		c, ok := index[s.Name]
+		if !ok {
+			continue
+		}
+		c.CPUPercent = parsePercent(s.CPUPerc)
+		c.MemPercent = parsePercent(s.MemPerc)
+		used, _, _ := strings.Cut(s.MemUsage, "/")
+		c.MemUsed = parseDockerSize(used)
+	}
+
+	slices.SortFunc(containers, func(a, b *Container) int {
+		if (a.State == "running") != (b.State == "running") {
+			if a.State == "running" {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return containers, nil
+}
+
+// ContainerStatus 是一个监控中的容器的状态
+type ContainerStatus struct {
+	Name    string `json:"name"`
+	State   string `json:"state"` // running、exited 等，容器不存在时为 missing
+	Running bool   `json:"running"`
+}
+
+// ParseContainerStatus 按 names 的顺序从 ContainerStatusCommand 的输出中取出容器的状态，不存在的容器视为已退出
+func ParseContainerStatus(names []string, output string) ([]*ContainerStatus, error) {
+	containers, err := ParseContainers(output)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]*ContainerStatus, len(names))
+	for i, name := range names {
+		status[i] = &ContainerStatus{Name: name, State: "missing"}
+		if j := slices.IndexFunc(containers, func(c *Container) bool { return c.Name == name }); j >= 0 {
+			status[i].State = containers[j].State
+			status[i].Running = containers[j].State == "running"
+		}
+	}
+	return status, nil
+}
+
+func parsePercent(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	return v
+}
+
+// parseDockerSize 解析 docker stats 的 12.5MiB、1.2GB 等格式
+func parseDockerSize(s string) uint64 {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+	units := map[string]float64{
+		"": 1, "b": 1,
+		"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+		"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	}
+	return uint64(v * units[strings.ToLower(s[i:])])
+}
@@ -0,0 +1,66 @@
+package model
+
+import "testing"
+
+func TestParseContainers(t *testing.T) {
+	output := `{"ID":"a1","Image":"nginx:latest","Names":"web","State":"running","Status":"Up 2 hours"}
+{"ID":"b2","Image":"redis","Names":"cache","State":"exited","Status":"Exited (137) 3 days ago"}
+{"ID":"c3","Image":"postgres:16","Names":"db","State":"running","Status":"Up 5 days"}
+---
+{"CPUPerc":"1.50%","MemPerc":"0.80%","MemUsage":"12.5MiB / 1.9GiB","Name":"web"}
+{"CPUPerc":"20.00%","MemPerc":"10.00%","MemUsage":"1.5GB / 15GB","Name":"db"}
+`
+	containers, err := ParseContainers(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 3 {
+		t.Fatalf("containers = %+v", containers)
+	}
+	if db := containers[0]; db.Name != "db" || db.CPUPercent != 20 || db.MemUsed != 1.5e9 {
+		t.Fatalf("db = %+v", db)
+	}
+	if web := containers[1]; web.Name != "web" || web.Image != "nginx:latest" || web.MemPercent != 0.8 || web.MemUsed != 12.5*(1<<20) {
+		t.Fatalf("web = %+v", web)
+	}
+	if cache := containers[2]; cache.Name != "cache" || cache.State != "exited" || cache.CPUPercent != 0 {
+		t.Fatalf("cache = %+v", cache)
+	}
+
+	if _, err := ParseContainers("permission denied\n"); err == nil {
+		t.Fatal("expected error for invalid output")
+	}
+}
+
+func TestParseContainerStatus(t *testing.T) {
+	for name, valid := range map[string]bool{"web": true, "my_app.1": true, "web-2": true, "": false, "-web": false, "a b": false, "web;ls": false} {
+		if ValidContainerName(name) != valid {
+			t.Fatalf("ValidContainerName(%q) = %v", name, !valid)
+		}
+	}
+
+	output := `{"ID":"a1","Image":"nginx:latest","Names":"web","State":"running","Status":"Up 2 hours"}
+{"ID":"b2","Image":"redis","Names":"cache","State":"exited","Status":"Exited (137) 3 days ago"}
+`
+	status, err := ParseContainerStatus([]string{"cache", "web", "db"}, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 3 {
+		t.Fatalf("status = %+v", status)
+	}
+	if cache := status[0]; cache.Name != "cache" || cache.State != "exited" || cache.Running {
+		t.Fatalf("cache = %+v", cache)
+	}
+	if web := status[1]; web.Name != "web" || !web.Running {
+		t.Fatalf("web = %+v", web)
+	}
+	// 被删除的容器视为已退出
+	if db := status[2]; db.Name != "db" || db.State != "missing" || db.Running {
+		t.Fatalf("db = %+v", db)
+	}
+
+	if _, err := ParseContainerStatus([]string{"web"}, "sh: docker: not found\n"); err == nil {
+		t.Fatal("expected error when docker is missing")
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// DiskHealthCommand 是读取 SMART 信息时让 Agent 执行的命令，需要安装 smartmontools，每块硬盘输出一个 JSON
+const DiskHealthCommand = `smartctl --scan | while read -r dev _ type _; do smartctl -j -i -H -A -d "$type" "$dev"; echo ---; done`
+
+// DiskHealth 是一块硬盘的 SMART 信息
+type DiskHealth struct {
+	Device             string  `json:"device"`
+	Model              string  `json:"model,omitempty"`
+	Passed             bool    `json:"passed"` // SMART 整体健康状态，硬盘不支持时视为通过
+	Temperature        float64 `json:"temperature,omitempty"`
+	PowerOnHours       uint64  `json:"power_on_hours,omitempty"`
+	ReallocatedSectors uint64  `json:"reallocated_sectors"` // 重映射与等待重映射的扇区数
+	MediaErrors        uint64  `json:"media_errors,omitempty"`
+	WearPercent        uint64  `json:"wear_percent"` // SSD 已用寿命的百分比，机械硬盘为 0
+}
+
+type smartctlOutput struct {
+	Device struct {
+		Name string `json:"name"`
+	} `json:"device"`
+	ModelName   string `json:"model_name"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	ATASmartAttributes struct {
+		Table []struct {
+			ID    int    `json:"id"`
+			Value uint64 `json:"value"`
+			Raw   struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		PercentageUsed uint64 `json:"percentage_used"`
+		MediaErrors    uint64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// ParseDiskHealth 解析 DiskHealthCommand 的输出，没有硬盘时返回空列表，smartctl 不可用时返回错误
+func ParseDiskHealth(output string) ([]*DiskHealth, error) {
+	disks := []*DiskHealth{}
+	for part := range strings.SplitSeq(output, "\n---") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var out smartctlOutput
+		if err := json.Unmarshal([]byte(part), &out); err != nil {
+			return nil, fmt.Errorf("invalid smartctl output %q: %w", part, err)
+		}
+		if out.Device.Name == "" {
+			continue
+		}
+
+		d := &DiskHealth{
+			Device:       out.Device.Name,
+			Model:        out.ModelName,
+			Passed:       out.SmartStatus == nil || out.SmartStatus.Passed,
+			Temperature:  out.Temperature.Current,
+			PowerOnHours: out.PowerOnTime.Hours,
+		}
+		for _, attr := range out.ATASmartAttributes.Table {
+			switch attr.ID {
+			case 5, 197: // Reallocated_Sector_Ct、Current_Pending_Sector
+				d.ReallocatedSectors += attr.Raw.Value
+			case 177, 231, 233: // 各厂商的 SSD 寿命，归一化值从 100 开始递减
+				if attr.Value <= 100 {
+					d.WearPercent = max(d.WearPercent, 100-attr.Value)
+				}
+			}
+		}
+		if out.NVMeHealth != nil {
+			d.WearPercent = out.NVMeHealth.PercentageUsed
+			d.MediaErrors = out.NVMeHealth.MediaErrors
+		}
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestParseDiskHealth(t *testing.T) {
+	output := `{"device":{"name":"/dev/sda"},"model_name":"WDC WD40EFRX","smart_status":{"passed":false},"temperature":{"current":41},"power_on_time":{"hours":30000},
+"ata_smart_attributes":{"table":[{"id":5,"value":100,"raw":{"value":8}},{"id":9,"value":66,"raw":{"value":30000}},{"id":197,"value":200,"raw":{"value":2}}]}}
+---
+{"device":{"name":"/dev/nvme0"},"model_name":"Samsung 980","smart_status":{"passed":true},"nvme_smart_health_information_log":{"percentage_used":12,"media_errors":1}}
+---
+{"device":{"name":"/dev/sdb"},"ata_smart_attributes":{"table":[{"id":177,"value":93,"raw":{"value":120}}]}}
+---
+`
+	disks, err := ParseDiskHealth(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disks) != 3 {
+		t.Fatalf("disks = %+v", disks)
+	}
+	if sda := disks[0]; sda.Passed || sda.ReallocatedSectors != 10 || sda.Temperature != 41 || sda.PowerOnHours != 30000 || sda.WearPercent != 0 {
+		t.Fatalf("sda = %+v", sda)
+	}
+	if nvme := disks[1]; !nvme.Passed || nvme.WearPercent != 12 || nvme.MediaErrors != 1 {
+		t.Fatalf("nvme = %+v", nvme)
+	}
+	// 不支持 SMART 整体状态时视为通过
+	if sdb := disks[2]; !sdb.Passed || sdb.WearPercent != 7 {
+		t.Fatalf("sdb = %+v", sdb)
+	}
+
+	if disks, err := ParseDiskHealth(""); err != nil || len(disks) != 0 {
+		t.Fatalf("empty output: %v, %v", disks, err)
+	}
+	if _, err := ParseDiskHealth("sh: smartctl: not found\n"); err == nil {
+		t.Fatal("expected error when smartctl is missing")
+	}
+}
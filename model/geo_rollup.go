@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// GeoRollup 是按天、按国家汇总的在线率与延迟，ServiceID 为 0 时是服务器在线率的采样，
+// 否则是位于这个国家的服务器执行服务监控的结果
+type GeoRollup struct {
+	ID         uint64    `gorm:"primaryKey" json:"id,omitempty"`
+	Day        time.Time `gorm:"uniqueIndex:idx_geo_rollup_day_country_service" json:"day,omitempty"`
+	Country    string    `gorm:"uniqueIndex:idx_geo_rollup_day_country_service" json:"country,omitempty"` // 2 位小写国家码，只查到洲时为洲代码
+	ServiceID  uint64    `gorm:"uniqueIndex:idx_geo_rollup_day_country_service" json:"service_id,omitempty"`
+	Up         uint64    `json:"up,omitempty"`   // 在线的采样数或成功的检查数
+	Down       uint64    `json:"down,omitempty"` // 离线的采样数或失败的检查数
+	DelaySum   float64   `json:"delay_sum,omitempty"`
+	DelayCount uint64    `json:"delay_count,omitempty"`
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServerOnlineTimeout 是判断服务器在线的时间，超过这个时间没有上报状态视为离线
+const ServerOnlineTimeout = 30 * time.Second
+
+// WorldMapRegion 是世界地图上一个国家或洲的统计
+type WorldMapRegion struct {
+	Code        string  `json:"code"` // 2 位小写国家码或洲代码
+	Name        string  `json:"name,omitempty"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Servers     int     `json:"servers"`
+	Online      int     `json:"online"`
+	Offline     int     `json:"offline"`
+	TransferIn  uint64  `json:"transfer_in"`
+	TransferOut uint64  `json:"transfer_out"`
+}
+
+// WorldMap 是按国家与洲聚合的服务器分布，按服务器数量从多到少排列
+type WorldMap struct {
+	Countries  []*WorldMapRegion `json:"countries"`
+	Continents []*WorldMapRegion `json:"continents"`
+	Unknown    int               `json:"unknown"` // 没有定位结果的服务器数
+}
+
+// StatusPageServer 是公开状态页上的一台服务器
+type StatusPageServer struct {
+	ID     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Online bool   `json:"online"`
+}
+
+// StatusPageRegion 是状态页上的一个国家或洲的分组
+type StatusPageRegion struct {
+	Code      string              `json:"code"` // 2 位小写国家码或洲代码，没有定位结果的服务器为空
+	Name      string              `json:"name,omitempty"`
+	FlagEmoji string              `json:"flag_emoji,omitempty"`
+	Online    int                 `json:"online"`
+	Uptime    float32             `json:"uptime"` // 在线服务器的百分比
+	Servers   []*StatusPageServer `json:"servers"`
+}
+
+// StatusPage 是按国家或洲分组的公开状态页，分组按服务器数量从多到少排列，没有定位结果的分组排在最后
+type StatusPage struct {
+	GroupBy string              `json:"group_by"`
+	Regions []*StatusPageRegion `json:"regions"`
+}
+
+// GeoRollupItem 是一段时间内一个国家或洲的在线率与平均延迟
+type GeoRollupItem struct {
+	Code        string  `json:"code"`
+	Name        string  `json:"name,omitempty"`
+	ServiceID   uint64  `json:"service_id,omitempty"`
+	ServiceName string  `json:"service_name,omitempty"`
+	Up          uint64  `json:"up"`
+	Down        uint64  `json:"down"`
+	Uptime      float32 `json:"uptime"`              // 百分比
+	AvgDelay    float32 `json:"avg_delay,omitempty"` // 毫秒，只统计成功的检查
+}
+
+// GeoRollupSummary 是按国家或洲汇总的服务器在线率与服务监控结果
+type GeoRollupSummary struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	GroupBy  string           `json:"group_by"`
+	Servers  []*GeoRollupItem `json:"servers"`
+	Services []*GeoRollupItem `json:"services"`
+}
+
+// GeoFilter 是查询接口的 country 与 continent 参数，多个值用逗号分隔，不区分大小写
+type GeoFilter struct {
+	Countries  []string
+	Continents []string
+}
+
+// GeoFilterCtx 从请求的 country 与 continent 参数读取 GeoFilter
+func GeoFilterCtx(c *gin.Context) GeoFilter {
+	return GeoFilter{
+		Countries:  splitGeoCodes(c.Query("country")),
+		Continents: splitGeoCodes(c.Query("continent")),
+	}
+}
+
+func splitGeoCodes(s string) []string {
+	var codes []string
+	for code := range strings.SplitSeq(s, ",") {
+		if code = strings.ToLower(strings.TrimSpace(code)); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Empty 返回是否没有指定任何条件
+func (f GeoFilter) Empty() bool {
+	return len(f.Countries) == 0 && len(f.Continents) == 0
+}
+
+// Match 返回服务器是否满足条件，同时指定国家与洲时两者都要满足，没有定位结果的服务器不满足任何条件
+func (f GeoFilter) Match(s *Server) bool {
+	if f.Empty() {
+		return true
+	}
+	country, continent := s.GeoIP.Region()
+	if len(f.Countries) > 0 && !slices.Contains(f.Countries, country) {
+		return false
+	}
+	return len(f.Continents) == 0 || slices.Contains(f.Continents, continent)
+}
+
+// FilterServers 返回满足条件的服务器，不修改 servers
+func (f GeoFilter) FilterServers(servers []*Server) []*Server {
+	if f.Empty() {
+		return servers
+	}
+	ret := make([]*Server, 0, len(servers))
+	for _, s := range servers {
+		if f.Match(s) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGeoFilter(t *testing.T) {
+	servers := []*Server{
+		{Common: Common{ID: 1}, GeoIP: &GeoIP{CountryCode: "HK", ContinentCode: "as"}},
+		{Common: Common{ID: 2}, GeoIP: &GeoIP{CountryCode: "jp", ContinentCode: "as"}},
+		{Common: Common{ID: 3}, GeoIP: &GeoIP{CountryCode: "us", ContinentCode: "na"}},
+		{Common: Common{ID: 4}, GeoIP: &GeoIP{CountryCode: "eu", ContinentCode: "eu"}},
+		{Common: Common{ID: 5}},
+	}
+	ids := func(f GeoFilter) []uint64 {
+		var ret []uint64
+		for _, s := range f.FilterServers(servers) {
+			ret = append(ret, s.ID)
+		}
+		return ret
+	}
+
+	cases := []struct {
+		filter GeoFilter
+		want   []uint64
+	}{
+		{GeoFilter{}, []uint64{1, 2, 3, 4, 5}},
+		{GeoFilter{Countries: []string{"hk"}}, []uint64{1}},
+		{GeoFilter{Continents: []string{"as"}}, []uint64{1, 2}},
+		{GeoFilter{Countries: []string{"hk", "us"}, Continents: []string{"as"}}, []uint64{1}},
+		{GeoFilter{Continents: []string{"eu"}}, []uint64{4}},
+		{GeoFilter{Countries: []string{"de"}}, nil},
+	}
+	for _, c := range cases {
+		if got := ids(c.filter); !slices.Equal(got, c.want) {
+			t.Fatalf("%+v: got %v, want %v", c.filter, got, c.want)
+		}
+	}
+}
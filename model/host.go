@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 
 	pb "github.com/nezhahq/nezha/proto"
 )
@@ -17,6 +18,15 @@ type SensorTemperature struct {
 	Temperature float64
 }
 
+// GPUState 是一块 GPU 的状态，显存单位为字节
+type GPUState struct {
+	Name        string  `json:"name,omitempty"`
+	Utilization float64 `json:"utilization,omitempty"` // 使用率 (百分比)
+	MemoryUsed  uint64  `json:"memory_used,omitempty"`
+	MemoryTotal uint64  `json:"memory_total,omitempty"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
 type HostState struct {
 	CPU            float64             `json:"cpu,omitempty"`
 	MemUsed        uint64              `json:"mem_used,omitempty"`
@@ -35,6 +45,7 @@ type HostState struct {
 	ProcessCount   uint64              `json:"process_count,omitempty"`
 	Temperatures   []SensorTemperature `json:"temperatures,omitempty"`
 	GPU            []float64           `json:"gpu,omitempty"`
+	GPUs           []GPUState          `json:"gpus,omitempty"` // 每块 GPU 的使用率、显存与温度，旧版 Agent 只上报 GPU
 }
 
 func (s *HostState) PB() *pb.State {
@@ -46,6 +57,17 @@ func (s *HostState) PB() *pb.State {
 		})
 	}
 
+	var gpus []*pb.State_GPU
+	for _, g := range s.GPUs {
+		gpus = append(gpus, &pb.State_GPU{
+			Name:        g.Name,
+			Utilization: g.Utilization,
+			MemoryUsed:  g.MemoryUsed,
+			MemoryTotal: g.MemoryTotal,
+			Temperature: g.Temperature,
+		})
+	}
+
 	return &pb.State{
 		Cpu:            s.CPU,
 		MemUsed:        s.MemUsed,
@@ -64,6 +86,7 @@ func (s *HostState) PB() *pb.State {
 		ProcessCount:   s.ProcessCount,
 		Temperatures:   ts,
 		Gpu:            s.GPU,
+		Gpus:           gpus,
 	}
 }
 
@@ -76,6 +99,17 @@ func PB2State(s *pb.State) HostState {
 		})
 	}
 
+	var gpus []GPUState
+	for _, g := range s.GetGpus() {
+		gpus = append(gpus, GPUState{
+			Name:        g.GetName(),
+			Utilization: g.GetUtilization(),
+			MemoryUsed:  g.GetMemoryUsed(),
+			MemoryTotal: g.GetMemoryTotal(),
+			Temperature: g.GetTemperature(),
+		})
+	}
+
 	return HostState{
 		CPU:            s.GetCpu(),
 		MemUsed:        s.GetMemUsed(),
@@ -94,6 +128,7 @@ func PB2State(s *pb.State) HostState {
 		ProcessCount:   s.GetProcessCount(),
 		Temperatures:   ts,
 		GPU:            s.GetGpu(),
+		GPUs:           gpus,
 	}
 }
 
@@ -173,8 +208,23 @@ func (p *IP) Join() string {
 }
 
 type GeoIP struct {
-	IP          IP     `json:"ip,omitempty"`
-	CountryCode string `json:"country_code,omitempty"`
+	IP            IP     `json:"ip,omitempty"`
+	CountryCode   string `json:"country_code,omitempty"`
+	CountryName   string `json:"country_name,omitempty"` // 按面板语言显示的国家名称
+	FlagEmoji     string `json:"flag_emoji,omitempty"`
+	ContinentCode string `json:"continent_code,omitempty"`
+}
+
+// Region 返回 2 位小写国家码与洲代码，只查到洲时国家码为空，没有定位结果时都为空
+func (g *GeoIP) Region() (country, continent string) {
+	if g == nil || g.ContinentCode == "" {
+		return "", ""
+	}
+	continent = strings.ToLower(g.ContinentCode)
+	if country = strings.ToLower(g.CountryCode); country == continent {
+		return "", continent
+	}
+	return country, continent
 }
 
 func PB2GeoIP(p *pb.GeoIP) GeoIP {
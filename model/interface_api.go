@@ -0,0 +1,73 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// NetInterfaceCommand 是获取网卡统计时让 Agent 执行的命令，只支持 Linux
+const NetInterfaceCommand = `cat /proc/net/dev`
+
+// NetInterface 是一个网卡从开机以来的收发统计
+type NetInterface struct {
+	Name      string `json:"name"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDrops   uint64 `json:"rx_drops"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDrops   uint64 `json:"tx_drops"`
+}
+
+// ParseNetInterfaces 解析 /proc/net/dev，跳过名称匹配 exclude 中任一通配符（如 docker*、veth*、lo）的网卡
+func ParseNetInterfaces(output string, exclude []string) ([]*NetInterface, error) {
+	interfaces := []*NetInterface{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		name, stats, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.Contains(name, "|") {
+			// 表头
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if excludedInterface(name, exclude) {
+			continue
+		}
+
+		fields := strings.Fields(stats)
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("invalid /proc/net/dev line %q", scanner.Text())
+		}
+		var v [16]uint64
+		for i := range v {
+			n, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid /proc/net/dev line %q", scanner.Text())
+			}
+			v[i] = n
+		}
+		interfaces = append(interfaces, &NetInterface{
+			Name:    name,
+			RxBytes: v[0], RxPackets: v[1], RxErrors: v[2], RxDrops: v[3],
+			TxBytes: v[8], TxPackets: v[9], TxErrors: v[10], TxDrops: v[11],
+		})
+	}
+	if len(interfaces) == 0 && strings.TrimSpace(output) != "" && !strings.Contains(output, "|") {
+		return nil, fmt.Errorf("invalid /proc/net/dev output %q", output)
+	}
+	return interfaces, nil
+}
+
+func excludedInterface(name string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
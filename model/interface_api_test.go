@@ -0,0 +1,30 @@
+package model
+
+import "testing"
+
+func TestParseNetInterfaces(t *testing.T) {
+	output := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 5000000 4000    2    7    0     0          0         0  9000000    6000    1    3    0     0       0          0
+docker0: 300     3    0    0    0     0          0         0      400       4    0    0    0     0       0          0
+`
+	interfaces, err := ParseNetInterfaces(output, []string{"lo", "docker*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interfaces) != 1 {
+		t.Fatalf("interfaces = %+v", interfaces)
+	}
+	eth0 := interfaces[0]
+	if eth0.Name != "eth0" || eth0.RxBytes != 5000000 || eth0.RxErrors != 2 || eth0.RxDrops != 7 || eth0.TxBytes != 9000000 || eth0.TxPackets != 6000 || eth0.TxErrors != 1 || eth0.TxDrops != 3 {
+		t.Fatalf("eth0 = %+v", eth0)
+	}
+
+	if interfaces, _ := ParseNetInterfaces(output, nil); len(interfaces) != 3 {
+		t.Fatalf("without exclude = %+v", interfaces)
+	}
+	if _, err := ParseNetInterfaces("cat: /proc/net/dev: No such file or directory\n", nil); err == nil {
+		t.Fatal("expected error for invalid output")
+	}
+}
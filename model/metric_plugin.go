@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+// MetricPlugin 是自定义指标插件，面板按 Duration 让服务器执行 Command，把输出的数值记录为名为 Name 的指标
+type MetricPlugin struct {
+	Common
+	Name     string   `json:"name" gorm:"unique"`
+	Command  string   `json:"command"`
+	Duration uint64   `json:"duration"` // 执行间隔 (秒)
+	Servers  []uint64 `gorm:"-" json:"servers"`
+	Cover    uint8    `json:"cover"` // 覆盖范围 (0:仅覆盖特定服务器 1:仅忽略特定服务器)
+
+	CronJobID  cron.EntryID `gorm:"-" json:"-"`
+	ServersRaw string       `json:"-"`
+}
+
+func (p *MetricPlugin) BeforeSave(tx *gorm.DB) error {
+	if data, err := json.Marshal(p.Servers); err != nil {
+		return err
+	} else {
+		p.ServersRaw = string(data)
+	}
+	return nil
+}
+
+func (p *MetricPlugin) AfterFind(tx *gorm.DB) error {
+	return json.Unmarshal([]byte(p.ServersRaw), &p.Servers)
+}
+
+// MetricHistory 是自定义指标的历史记录，用于画图
+type MetricHistory struct {
+	ID        uint64    `gorm:"primaryKey" json:"-"`
+	CreatedAt time.Time `gorm:"index:idx_server_id_name_created_at" json:"created_at"`
+	ServerID  uint64    `gorm:"index:idx_server_id_name_created_at" json:"-"`
+	Name      string    `gorm:"index:idx_server_id_name_created_at" json:"-"`
+	Value     float64   `json:"value"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type MetricPluginForm struct {
+	Name     string   `json:"name,omitempty" minLength:"1"`
+	Command  string   `json:"command,omitempty"`
+	Duration uint64   `json:"duration,omitempty" minimum:"10"`
+	Servers  []uint64 `json:"servers,omitempty"`
+	Cover    uint8    `json:"cover,omitempty" default:"0"`
+}
+
+var metricNameRe = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// ValidMetricName 检查自定义指标插件的名称，只允许字母、数字、下划线与减号
+func ValidMetricName(name string) bool {
+	return metricNameRe.MatchString(name)
+}
+
+// ParseMetricOutput 解析插件 name 的输出，只有一个数值的行记为 name，"key value" 形式的行记为 name.key
+func ParseMetricOutput(name, output string) (map[string]float64, error) {
+	metrics := make(map[string]float64)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		var key, value string
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			key, value = name, fields[0]
+		case 2:
+			if !ValidMetricName(fields[0]) {
+				return nil, fmt.Errorf("invalid metric name %q", fields[0])
+			}
+			key, value = name+"."+fields[0], fields[1]
+		default:
+			return nil, fmt.Errorf("invalid metric line %q", scanner.Text())
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric value %q", value)
+		}
+		metrics[key] = v
+	}
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("no metric in output %q", output)
+	}
+	return metrics, nil
+}
@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestParseMetricOutput(t *testing.T) {
+	metrics, err := ParseMetricOutput("nginx", "42\n\nactive 12\nwaiting 3.5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 3 || metrics["nginx"] != 42 || metrics["nginx.active"] != 12 || metrics["nginx.waiting"] != 3.5 {
+		t.Fatalf("metrics = %v", metrics)
+	}
+
+	for _, output := range []string{"", "not-a-number", "a b c", "bad.name 1", "sh: curl: not found"} {
+		if _, err := ParseMetricOutput("nginx", output); err == nil {
+			t.Fatalf("expected error for %q", output)
+		}
+	}
+
+	if ValidMetricName("nginx.active") || ValidMetricName("") || !ValidMetricName("queue_size-1") {
+		t.Fatal("unexpected ValidMetricName result")
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProcessCommand 是获取进程列表时让 Agent 执行的命令，Linux 与 macOS 通用，排序在面板进行
+const ProcessCommand = `ps -eo pid=,user=,pcpu=,pmem=,rss=,comm=`
+
+// Process 是服务器上的一个进程
+type Process struct {
+	PID        uint64  `json:"pid"`
+	User       string  `json:"user"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemPercent float64 `json:"mem_percent"`
+	RSS        uint64  `json:"rss"` // 字节
+	Command    string  `json:"command"`
+}
+
+// ParseProcesses 解析 ProcessCommand 的输出，sortBy 为 memory 时按内存排序，否则按 CPU，只返回前 limit 个
+func ParseProcesses(output, sortBy string, limit int) ([]*Process, error) {
+	processes := []*Process{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid ps output %q", scanner.Text())
+		}
+		pid, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ps output %q", scanner.Text())
+		}
+		p := &Process{PID: pid, User: fields[1], Command: strings.Join(fields[5:], " ")}
+		p.CPUPercent, _ = strconv.ParseFloat(fields[2], 64)
+		p.MemPercent, _ = strconv.ParseFloat(fields[3], 64)
+		rss, _ := strconv.ParseUint(fields[4], 10, 64)
+		p.RSS = rss * 1024
+		processes = append(processes, p)
+	}
+
+	slices.SortFunc(processes, func(a, b *Process) int {
+		if sortBy == "memory" {
+			return cmp.Or(cmp.Compare(b.RSS, a.RSS), cmp.Compare(a.PID, b.PID))
+		}
+		return cmp.Or(cmp.Compare(b.CPUPercent, a.CPUPercent), cmp.Compare(a.PID, b.PID))
+	})
+	if limit > 0 && len(processes) > limit {
+		processes = processes[:limit]
+	}
+	return processes, nil
+}
@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestParseProcesses(t *testing.T) {
+	output := `    1 root      0.0  0.1  11852 systemd
+  812 mysql    35.2 12.5 2048000 mysqld
+ 1024 www-data 60.1  1.0  81920 php-fpm8.2
+ 2048 alice     5.0 20.0 4096000 /Applications/Google Chrome.app/Contents/MacOS/Google Chrome
+`
+	processes, err := ParseProcesses(output, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 2 || processes[0].PID != 1024 || processes[1].PID != 812 {
+		t.Fatalf("by cpu = %+v", processes)
+	}
+	if p := processes[1]; p.User != "mysql" || p.CPUPercent != 35.2 || p.MemPercent != 12.5 || p.RSS != 2048000*1024 || p.Command != "mysqld" {
+		t.Fatalf("mysqld = %+v", p)
+	}
+
+	processes, err = ParseProcesses(output, "memory", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 4 || processes[0].Command != "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome" {
+		t.Fatalf("by memory = %+v", processes)
+	}
+
+	if _, err := ParseProcesses("command execution is disabled\n", "", 10); err == nil {
+		t.Fatal("expected error for invalid output")
+	}
+}
@@ -23,6 +23,12 @@ type Rule struct {
 	// 指标类型，cpu、memory、swap、disk、net_in_speed、net_out_speed
 	// net_all_speed、transfer_in、transfer_out、transfer_all、offline
 	// transfer_in_cycle、transfer_out_cycle、transfer_all_cycle
+	// country_changed（公网 IP 定位到的国家与上次不同）
+	// disk_failed（SMART 整体健康状态不通过）、disk_reallocated（重映射扇区数）、disk_wear（SSD 已用寿命百分比）
+	// gpu_max（GPU 使用率最大值）、gpu_memory（GPU 显存使用率最大值）、gpu_temperature_max（GPU 温度最大值）
+	// unit_inactive（服务器监控的 systemd unit 不是 active）
+	// container_exited（服务器监控的 Docker 容器不在运行）
+	// custom_metric（自定义指标插件上报的 Metric 指标）
 	Type          string          `json:"type"`
 	Min           float64         `json:"min,omitempty" validate:"optional"`                                                        // 最小阈值 (百分比、字节 kb ÷ 1024)
 	Max           float64         `json:"max,omitempty" validate:"optional"`                                                        // 最大阈值 (百分比、字节 kb ÷ 1024)
@@ -33,9 +39,19 @@ type Rule struct {
 	Cover         uint64          `json:"cover"`                                                                                    // 覆盖范围 RuleCoverAll/IgnoreAll
 	Ignore        map[uint64]bool `json:"ignore,omitempty" validate:"optional"`                                                     // 覆盖范围的排除
 
+	// temperature_max 只统计名称包含 Sensor 的传感器，不区分大小写，如 coretemp、nvme、acpitz，留空统计所有传感器
+	Sensor string `json:"sensor,omitempty" validate:"optional"`
+
+	// custom_metric 检查的指标名称，如 nginx 或 nginx.active
+	Metric string `json:"metric,omitempty" validate:"optional"`
+
 	// 只作为缓存使用，记录下次该检测的时间
 	NextTransferAt  map[uint64]time.Time `json:"-"`
 	LastCycleStatus map[uint64]bool      `json:"-"`
+
+	// 只作为缓存使用，记录每台服务器上次的国家码与最近一次的变化
+	LastCountry    map[uint64]string    `json:"-"`
+	CountryChanges map[uint64][2]string `json:"-"`
 }
 
 func percentage(used, total uint64) float64 {
@@ -61,13 +77,34 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 		return u.LastCycleStatus[server.ID]
 	}
 
+	if u.IsCountryChangeRule() {
+		return u.snapshotCountry(server)
+	}
+
 	var src float64
 
 	switch u.Type {
 	case "cpu":
 		src = float64(server.State.CPU)
 	case "gpu_max":
-		src = slices.Max(server.State.GPU)
+		// 没有 GPU 的服务器不上报 GPU 使用率，新版 Agent 可能只上报 GPUs
+		usage := server.State.GPU
+		if len(usage) == 0 {
+			for _, g := range server.State.GPUs {
+				usage = append(usage, g.Utilization)
+			}
+		}
+		if len(usage) > 0 {
+			src = slices.Max(usage)
+		}
+	case "gpu_memory":
+		for _, g := range server.State.GPUs {
+			src = max(src, percentage(g.MemoryUsed, g.MemoryTotal))
+		}
+	case "gpu_temperature_max":
+		for _, g := range server.State.GPUs {
+			src = max(src, g.Temperature)
+		}
 	case "memory":
 		src = percentage(server.State.MemUsed, server.Host.MemTotal)
 	case "swap":
@@ -125,14 +162,48 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 		src = float64(server.State.UdpConnCount)
 	case "process_count":
 		src = float64(server.State.ProcessCount)
+	case "disk_failed":
+		for _, d := range server.Disks {
+			if !d.Passed {
+				src++
+			}
+		}
+	case "disk_reallocated":
+		for _, d := range server.Disks {
+			src = max(src, float64(d.ReallocatedSectors))
+		}
+	case "disk_wear":
+		for _, d := range server.Disks {
+			src = max(src, float64(d.WearPercent))
+		}
+	case "unit_inactive":
+		for _, unit := range server.UnitStatus {
+			if !unit.Active {
+				src++
+			}
+		}
+	case "container_exited":
+		for _, c := range server.ContainerStatus {
+			if !c.Running {
+				src++
+			}
+		}
+	case "custom_metric":
+		v, ok := server.Metrics[u.Metric]
+		if !ok {
+			// 还没有上报过该指标
+			return true
+		}
+		src = v
 	case "temperature_max":
 		var temp []float64
-		if server.State.Temperatures != nil {
-			for _, tempStat := range server.State.Temperatures {
-				if tempStat.Temperature != 0 {
-					temp = append(temp, tempStat.Temperature)
-				}
+		sensor := strings.ToLower(u.Sensor)
+		for _, tempStat := range server.State.Temperatures {
+			if tempStat.Temperature != 0 && strings.Contains(strings.ToLower(tempStat.Name), sensor) {
+				temp = append(temp, tempStat.Temperature)
 			}
+		}
+		if len(temp) > 0 {
 			src = slices.Max(temp)
 		}
 	}
@@ -164,6 +235,8 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 
 	if u.Type == "offline" && float64(time.Now().Unix())-src > 6 {
 		return false
+	} else if (u.Type == "disk_failed" || u.Type == "unit_inactive" || u.Type == "container_exited") && src > 0 {
+		return false
 	} else if (u.Max > 0 && src > u.Max) || (u.Min > 0 && src < u.Min) {
 		return false
 	}
@@ -180,6 +253,39 @@ func (u *Rule) IsOfflineRule() bool {
 	return u.Type == "offline"
 }
 
+// IsDiskRule 判断是否是依赖 SMART 信息的规则
+func (u *Rule) IsDiskRule() bool {
+	return strings.HasPrefix(u.Type, "disk_")
+}
+
+func (u *Rule) IsCountryChangeRule() bool {
+	return u.Type == "country_changed"
+}
+
+// snapshotCountry 国家码与上次不同时返回 false，第一次检查或还没有定位结果时通过
+func (u *Rule) snapshotCountry(server *Server) bool {
+	var code string
+	if server.GeoIP != nil {
+		code = server.GeoIP.CountryCode
+	}
+	if code == "" {
+		return true
+	}
+	if u.LastCountry == nil {
+		u.LastCountry = make(map[uint64]string)
+	}
+	if u.CountryChanges == nil {
+		u.CountryChanges = make(map[uint64][2]string)
+	}
+	last, ok := u.LastCountry[server.ID]
+	u.LastCountry[server.ID] = code
+	if !ok || last == code {
+		return true
+	}
+	u.CountryChanges[server.ID] = [2]string{last, code}
+	return false
+}
+
 // GetTransferDurationStart 获取周期流量的起始时间
 func (u *Rule) GetTransferDurationStart() time.Time {
 	// Accept uppercase and lowercase
@@ -37,6 +37,20 @@ type Server struct {
 
 	PrevTransferInSnapshot  uint64 `gorm:"-" json:"-"` // 上次数据点时的入站使用量
 	PrevTransferOutSnapshot uint64 `gorm:"-" json:"-"` // 上次数据点时的出站使用量
+
+	PeerIP string `gorm:"-" json:"-"` // 上次根据 gRPC 连接地址定位时使用的 IP
+
+	Disks []*DiskHealth `gorm:"-" json:"-"` // 最近一次读取的 SMART 信息，只有配置了硬盘报警规则时才定时读取
+
+	WatchedUnitsRaw string        `gorm:"default:'[]';column:watched_units_raw" json:"-"`
+	WatchedUnits    []string      `gorm:"-" json:"watched_units,omitempty"` // 需要监控状态的 systemd unit
+	UnitStatus      []*UnitStatus `gorm:"-" json:"-"`                       // 最近一次读取的 WatchedUnits 的状态
+
+	WatchedContainersRaw string             `gorm:"default:'[]';column:watched_containers_raw" json:"-"`
+	WatchedContainers    []string           `gorm:"-" json:"watched_containers,omitempty"` // 需要监控状态的 Docker 容器名称
+	ContainerStatus      []*ContainerStatus `gorm:"-" json:"-"`                            // 最近一次读取的 WatchedContainers 的状态
+
+	Metrics map[string]float64 `gorm:"-" json:"-"` // 自定义指标插件最近一次上报的数值
 }
 
 func InitServer(s *Server) {
@@ -55,6 +69,11 @@ func (s *Server) CopyFromRunningServer(old *Server) {
 	s.ConfigCache = old.ConfigCache
 	s.PrevTransferInSnapshot = old.PrevTransferInSnapshot
 	s.PrevTransferOutSnapshot = old.PrevTransferOutSnapshot
+	s.PeerIP = old.PeerIP
+	s.Disks = old.Disks
+	s.UnitStatus = old.UnitStatus
+	s.ContainerStatus = old.ContainerStatus
+	s.Metrics = old.Metrics
 }
 
 func (s *Server) AfterFind(tx *gorm.DB) error {
@@ -70,6 +89,18 @@ func (s *Server) AfterFind(tx *gorm.DB) error {
 			return nil
 		}
 	}
+	if s.WatchedUnitsRaw != "" {
+		if err := json.Unmarshal([]byte(s.WatchedUnitsRaw), &s.WatchedUnits); err != nil {
+			log.Println("NEZHA>> Server.AfterFind:", err)
+			return nil
+		}
+	}
+	if s.WatchedContainersRaw != "" {
+		if err := json.Unmarshal([]byte(s.WatchedContainersRaw), &s.WatchedContainers); err != nil {
+			log.Println("NEZHA>> Server.AfterFind:", err)
+			return nil
+		}
+	}
 	return nil
 }
 
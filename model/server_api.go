@@ -11,6 +11,8 @@ type StreamServer struct {
 	Host        *Host      `json:"host,omitempty"`
 	State       *HostState `json:"state,omitempty"`
 	CountryCode string     `json:"country_code,omitempty"`
+	CountryName string     `json:"country_name,omitempty"`
+	FlagEmoji   string     `json:"flag_emoji,omitempty"`
 	LastActive  time.Time  `json:"last_active,omitempty"`
 }
 
@@ -32,8 +34,9 @@ type ServerForm struct {
 }
 
 type ServerConfigForm struct {
-	Servers []uint64 `json:"servers,omitempty"`
-	Config  string   `json:"config,omitempty"`
+	Servers      []uint64 `json:"servers,omitempty"`
+	ServerGroups []uint64 `json:"server_groups,omitempty" validate:"optional"` // 同时推送给这些分组中的服务器
+	Config       string   `json:"config,omitempty"`                            // JSON 对象，如 {"report_delay":3}；面板只负责转发，哪些配置无需重启即可生效由 Agent 决定
 }
 
 type ServerTaskResponse struct {
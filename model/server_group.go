@@ -4,4 +4,12 @@ type ServerGroup struct {
 	Common
 
 	Name string `json:"name"`
+
+	GeoCode string `json:"geo_code,omitempty"` // 自动分组的洲或国家代码，如 continent:as、country:jp，为空时是手动维护的分组
 }
+
+// 自动分组的方式，见 Config.GeoAutoGroup
+const (
+	GeoGroupContinent = "continent"
+	GeoGroupCountry   = "country"
+)
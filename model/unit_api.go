@@ -0,0 +1,47 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var unitNameRe = regexp.MustCompile(`^[A-Za-z0-9@._:\\-]+$`)
+
+// ValidUnitName 检查 systemd unit 名称，名称会拼接到在 Agent 上执行的命令中，只允许 unit 名称中合法的字符
+func ValidUnitName(name string) bool {
+	return unitNameRe.MatchString(name)
+}
+
+// UnitStatusCommand 返回查询 units 状态的命令，每个 unit 输出一行，有 unit 不是 active 时 systemctl 的退出码不为 0，这里忽略
+func UnitStatusCommand(units []string) string {
+	return "systemctl is-active -- '" + strings.Join(units, "' '") + "'; true"
+}
+
+// UnitStatus 是一个 systemd unit 的状态
+type UnitStatus struct {
+	Name   string `json:"name"`
+	State  string `json:"state"` // active、inactive、failed、activating 等
+	Active bool   `json:"active"`
+}
+
+// ParseUnitStatus 按 units 的顺序解析 UnitStatusCommand 的输出
+func ParseUnitStatus(units []string, output string) ([]*UnitStatus, error) {
+	var states []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			states = append(states, line)
+		}
+	}
+	if len(states) != len(units) {
+		return nil, fmt.Errorf("invalid systemctl output %q", output)
+	}
+
+	status := make([]*UnitStatus, len(units))
+	for i, unit := range units {
+		status[i] = &UnitStatus{Name: unit, State: states[i], Active: states[i] == "active"}
+	}
+	return status, nil
+}
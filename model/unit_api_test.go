@@ -0,0 +1,36 @@
+package model
+
+import "testing"
+
+func TestUnitStatus(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"nginx.service":            true,
+		"getty@tty1.service":       true,
+		"systemd-fsck-root":        true,
+		`dev-disk-by\x2duuid.swap`: true,
+		"nginx; rm -rf /":          false,
+		"$(reboot)":                false,
+		"":                         false,
+		"docker.socket\nshutdown":  false,
+	} {
+		if ValidUnitName(name) != valid {
+			t.Fatalf("ValidUnitName(%q) != %v", name, valid)
+		}
+	}
+
+	units := []string{"nginx.service", "redis.service"}
+	if cmd := UnitStatusCommand(units); cmd != "systemctl is-active -- 'nginx.service' 'redis.service'; true" {
+		t.Fatalf("command = %q", cmd)
+	}
+
+	status, err := ParseUnitStatus(units, "active\nfailed\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status[0].Active || status[1].Active || status[1].State != "failed" || status[1].Name != "redis.service" {
+		t.Fatalf("status = %+v, %+v", status[0], status[1])
+	}
+	if _, err := ParseUnitStatus(units, "sh: systemctl: not found\n"); err == nil {
+		t.Fatal("expected error for invalid output")
+	}
+}
@@ -0,0 +1,68 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+// 默认的 ASN 数据库路径，支持 ipinfo 的 asn.mmdb 与 MaxMind 的 GeoLite2-ASN.mmdb
+var defaultASNDBPaths = []string{
+	"/dashboard/data/asn.mmdb",
+	"/dashboard/data/GeoLite2-ASN.mmdb",
+}
+
+func newASNStore(paths []string) *mmdbStore {
+	s := newMMDBStore(paths)
+	s.noEmbedded = true
+	return s
+}
+
+// asnRecord 兼容两种 ASN 数据库的记录：
+// - ipinfo asn.mmdb：asn 为 "AS13335" 形式，name、domain 为组织名称与域名
+// - GeoLite2-ASN：autonomous_system_number、autonomous_system_organization
+type asnRecord struct {
+	ASN    string `maxminddb:"asn"`
+	Name   string `maxminddb:"name"`
+	Domain string `maxminddb:"domain"`
+
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+func (r *asnRecord) toASN() *ASN {
+	if r.Number > 0 {
+		return &ASN{Number: r.Number, Organization: r.Organization}
+	}
+	asn, err := parseASOrg(r.ASN)
+	if err != nil {
+		return nil
+	}
+	asn.Organization, asn.Domain = r.Name, r.Domain
+	return asn
+}
+
+// lookupASN 从 ASN 数据库查询，数据库中没有该 IP 时返回 ErrNotFound
+func (s *mmdbStore) lookupASN(ip net.IP) (*ASN, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	var asn *ASN
+	err := s.with(func(db *maxminddb.Reader, source string) error {
+		var record asnRecord
+		if err := db.Lookup(ip, &record); err != nil {
+			return fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+		}
+		asn = record.toASN()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if asn == nil {
+		return nil, ErrNotFound
+	}
+	return asn, nil
+}
@@ -0,0 +1,31 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestASNRecord(t *testing.T) {
+	cases := []struct {
+		record asnRecord
+		want   *ASN
+	}{
+		{asnRecord{ASN: "AS13335", Name: "Cloudflare, Inc.", Domain: "cloudflare.com"}, &ASN{Number: 13335, Organization: "Cloudflare, Inc.", Domain: "cloudflare.com"}},
+		{asnRecord{Number: 15169, Organization: "GOOGLE"}, &ASN{Number: 15169, Organization: "GOOGLE"}},
+		{asnRecord{}, nil},
+	}
+	for _, c := range cases {
+		got := c.record.toASN()
+		if (got == nil) != (c.want == nil) || got != nil && *got != *c.want {
+			t.Errorf("toASN(%+v) = %+v, want %+v", c.record, got, c.want)
+		}
+	}
+
+	// ASN 数据库不存在时返回 ErrDBUnavailable，不影响国家查询
+	s := newASNStore([]string{filepath.Join(t.TempDir(), "asn.mmdb")})
+	if _, err := s.lookupASN(net.ParseIP("1.1.1.1")); !errors.Is(err, ErrDBUnavailable) {
+		t.Fatalf("lookupASN error = %v", err)
+	}
+}
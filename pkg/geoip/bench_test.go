@@ -0,0 +1,82 @@
+package geoip_test
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// newBenchResolver 使用生成的数据库，只查询 mmdb，不访问网络
+func newBenchResolver(b *testing.B, opts ...geoip.Option) *geoip.Resolver {
+	b.Helper()
+	countries := []string{"us", "jp", "de", "cn", "hk", "sg", "gb", "fr"}
+	table := make(map[string]string)
+	for i := 1; i < 224; i++ {
+		for j := 0; j < 256; j++ {
+			table[fmt.Sprintf("%d.%d.0.0/16", i, j)] = countries[(i+j)%len(countries)]
+		}
+	}
+	table["2001:4860::/32"] = "us"
+	table["2400:cb00::/32"] = "us"
+	path := filepath.Join(b.TempDir(), "bench.mmdb")
+	writeCountryDB(b, path, table)
+	return newDBResolver(b, path, opts...)
+}
+
+var benchIPs = []net.IP{
+	net.ParseIP("8.8.8.8"),
+	net.ParseIP("1.1.1.1"),
+	net.ParseIP("112.4.1.1"),
+	net.ParseIP("185.200.108.153"),
+	net.ParseIP("2001:4860:4860::8888"),
+	net.ParseIP("2400:cb00::1"),
+}
+
+func BenchmarkLookup(b *testing.B) {
+	r := newBenchResolver(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Lookup(benchIPs[i%len(benchIPs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLookupDetail(b *testing.B) {
+	r := newBenchResolver(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.LookupDetail(benchIPs[i%len(benchIPs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLookupParallel(b *testing.B) {
+	r := newBenchResolver(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if _, err := r.Lookup(benchIPs[i%len(benchIPs)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkLookupCached(b *testing.B) {
+	r := newBenchResolver(b, geoip.WithCache(geoip.NewLRUCache(1024, 0)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Lookup(benchIPs[i%len(benchIPs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
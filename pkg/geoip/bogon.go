@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"net"
+	"net/netip"
+)
+
+// SourceBogon 表示 IP 属于内网或保留地址，未查询任何数据源
+const SourceBogon = "bogon"
+
+// netip 未覆盖到的保留地址段
+var bogonPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // 本网络
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF 协议分配
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // 基准测试
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // 保留，含广播地址
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // 本地 NAT64
+	netip.MustParsePrefix("100::/64"),        // 丢弃前缀
+	netip.MustParsePrefix("2001:db8::/32"),   // 文档
+	netip.MustParsePrefix("2001::/23"),       // IETF 协议分配
+	netip.MustParsePrefix("3fff::/20"),       // 文档
+	netip.MustParsePrefix("5f00::/16"),       // SRv6 SID
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // SIIT
+	netip.MustParsePrefix("fec0::/10"),       // 已废弃的站点本地地址
+}
+
+// IsBogon 判断 IP 是否为内网（RFC1918、ULA）、回环、链路本地、CGNAT 或其他不会出现在公网上的地址
+func IsBogon(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	return isBogonAddr(addr.Unmap())
+}
+
+func isBogonAddr(addr netip.Addr) bool {
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return true
+	}
+	for _, p := range bogonPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsBogon(t *testing.T) {
+	cases := map[string]bool{
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"127.0.0.1":       true,
+		"169.254.1.1":     true,
+		"100.64.0.1":      true,
+		"::ffff:10.0.0.1": true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"2001:db8::1":     true,
+		"1.1.1.1":         false,
+		"100.128.0.1":     false,
+		"2606:4700::1111": false,
+	}
+	for ip, expected := range cases {
+		if got := IsBogon(net.ParseIP(ip)); got != expected {
+			t.Fatalf("IsBogon(%s) = %v, expected %v", ip, got, expected)
+		}
+	}
+
+	if _, err := Lookup(net.ParseIP("192.168.1.1")); !errors.Is(err, ErrPrivateIP) {
+		t.Fatalf("Lookup(192.168.1.1) error = %v, expected ErrPrivateIP", err)
+	}
+}
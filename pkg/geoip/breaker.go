@@ -0,0 +1,89 @@
+package geoip
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断的默认参数：连续失败 5 次后 5 分钟内不再请求
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 5 * time.Minute
+)
+
+// circuitBreaker 在在线数据源连续失败或返回 429 后暂停请求一段时间，让查询直接落到 mmdb，而不是每次都等待超时
+// 冷却结束后放行一次试探请求，成功则恢复，失败则重新计时
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow 判断当前是否可以发起请求
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	// 冷却结束，只放行一个试探请求
+	b.probing = true
+	return true
+}
+
+// success 记录一次成功的请求
+func (b *circuitBreaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.probing = false
+}
+
+// failure 记录一次失败的请求，rateLimited 为 true 时立即熔断
+func (b *circuitBreaker) failure(rateLimited bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if rateLimited || b.probing || b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		b.probing = false
+	}
+}
+
+// open 返回熔断是否生效中
+func (b *circuitBreaker) open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.openUntil.IsZero() && time.Now().Before(b.openUntil)
+}
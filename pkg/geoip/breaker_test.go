@@ -0,0 +1,48 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f := &httpFetcher{name: "test", client: srv.Client(), timeout: time.Second, breaker: newCircuitBreaker(2, 20*time.Millisecond)}
+	for range 2 {
+		f.get(context.Background(), srv.URL)
+	}
+	if _, err := f.get(context.Background(), srv.URL); !errors.Is(err, ErrProviderUnavailable) || calls != 2 {
+		t.Fatalf("get with open breaker = %v after %d calls, expected no request", err, calls)
+	}
+
+	// 冷却结束后放行试探请求，成功即恢复
+	time.Sleep(30 * time.Millisecond)
+	status = http.StatusOK
+	if body, err := f.get(context.Background(), srv.URL); err != nil || string(body) != "ok" || f.breaker.open() {
+		t.Fatalf("probe = %q, %v, open %v", body, err, f.breaker.open())
+	}
+
+	// 429 立即熔断，不再重试
+	calls = 0
+	status = http.StatusTooManyRequests
+	f.retries = 3
+	f.get(context.Background(), srv.URL)
+	if !f.breaker.open() || calls != 1 {
+		t.Fatalf("breaker open %v after %d calls, expected open after one 429", f.breaker.open(), calls)
+	}
+}
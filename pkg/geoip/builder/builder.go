@@ -0,0 +1,205 @@
+// Package builder 将 CIDR→国家 的列表编译成 mmdb 数据库
+// 生成的数据库与 ipinfo_lite.mmdb 的字段相同，可以直接作为 GEOIP_DB_PATH 或 GEOIP_DB_LAYERS 使用
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// DefaultDatabaseType 是生成的数据库的 database_type
+const DefaultDatabaseType = "nezha-geoip-country"
+
+// Entry 是一条 CIDR→国家 的数据
+type Entry struct {
+	Network netip.Prefix
+	Country string // 2 位国家码，小写
+}
+
+func newEntry(cidr, country string) (Entry, error) {
+	cidr = strings.TrimSpace(cidr)
+	country = strings.ToLower(strings.TrimSpace(country))
+	if len(country) != 2 {
+		return Entry{}, fmt.Errorf("invalid country code %q for %s", country, cidr)
+	}
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(cidr)
+		if addrErr != nil {
+			return Entry{}, err
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return Entry{Network: prefix.Masked(), Country: country}, nil
+}
+
+// ParseCSV 读取每行 "cidr,country" 的 CSV，忽略空行、# 开头的注释与无法解析的表头，多余的列会被忽略
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected cidr,country", line)
+		}
+		entry, err := newEntry(fields[0], fields[1])
+		if err != nil {
+			if len(entries) == 0 && line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ParseJSON 读取 {"cidr": "country"} 形式的对象，或 [{"network": "cidr", "country": "xx"}] 形式的数组
+func ParseJSON(data []byte) ([]Entry, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var list []struct {
+			Network string `json:"network"`
+			Country string `json:"country"`
+		}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		entries := make([]Entry, 0, len(list))
+		for i, item := range list {
+			entry, err := newEntry(item.Network, item.Country)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+	return parseTable(data, json.Unmarshal)
+}
+
+func parseTable(data []byte, unmarshal func([]byte, any) error) ([]Entry, error) {
+	var table map[string]string
+	if err := unmarshal(data, &table); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(table))
+	for cidr, country := range table {
+		entry, err := newEntry(cidr, country)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Load 按扩展名读取 .csv、.json 文件，以及与 GEOIP_OVERRIDES 格式相同的 .yaml/.yml 文件
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = ParseCSV(bytes.NewReader(data))
+	case ".json":
+		entries, err = ParseJSON(data)
+	case ".yaml", ".yml":
+		entries, err = parseTable(data, func(b []byte, v any) error { return yaml.Unmarshal(b, v) })
+	default:
+		return nil, fmt.Errorf("geoip: unsupported file %s, expected .csv, .json or .yaml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("geoip: parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Options 是生成数据库的参数
+type Options struct {
+	DatabaseType string // 默认 DefaultDatabaseType
+	Description  string
+	BuildTime    time.Time // 为空时使用当前时间，指定后相同的输入生成相同的文件
+}
+
+// Build 将 base 与 overrides 写成 mmdb
+// 同一组数据中更小的网段优先，overrides 在 base 之后写入，覆盖 base 中重叠的部分
+func Build(out io.Writer, opts Options, base, overrides []Entry) error {
+	if opts.DatabaseType == "" {
+		opts.DatabaseType = DefaultDatabaseType
+	}
+	w := NewWriter(opts.DatabaseType)
+	w.Description = opts.Description
+	w.BuildTime = opts.BuildTime
+
+	for _, entries := range [][]Entry{base, overrides} {
+		entries = slices.Clone(entries)
+		slices.SortStableFunc(entries, func(a, b Entry) int {
+			return prefixBits(a.Network) - prefixBits(b.Network)
+		})
+		for _, entry := range entries {
+			if err := w.Insert(entry.Network, countryRecord(entry.Country)); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := w.WriteTo(out)
+	return err
+}
+
+// BuildFile 生成数据库并原子替换 path
+func BuildFile(path string, opts Options, base, overrides []Entry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".geoip-*.mmdb")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = Build(tmp, opts, base, overrides)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// prefixBits 返回网段在 IPv6 树中的长度，IPv4 网段位于 ::/96 下
+func prefixBits(p netip.Prefix) int {
+	if p.Addr().Is4() {
+		return p.Bits() + 96
+	}
+	return p.Bits()
+}
+
+// countryRecord 生成与 ipinfo_lite.mmdb 相同字段的记录
+func countryRecord(code string) Record {
+	record := Record{"country_code": strings.ToUpper(code)}
+	if name := geoip.CountryName(code, "en"); name != "" {
+		record["country"] = name
+	}
+	return record
+}
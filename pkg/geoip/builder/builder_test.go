@@ -0,0 +1,171 @@
+package builder
+
+import (
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+func TestBuild(t *testing.T) {
+	base, err := ParseCSV(strings.NewReader("network,country\n1.0.0.0/8,au\n1.2.3.0/24,cn\n2a00::/16,de\n"))
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	overrides, err := ParseJSON([]byte(`{"1.2.3.128/25": "jp", "1.0.0.0/16": "us"}`))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "custom.mmdb")
+	if err := BuildFile(path, Options{Description: "test"}, base, overrides); err != nil {
+		t.Fatalf("BuildFile: %v", err)
+	}
+
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer reader.Close()
+	if err := reader.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if reader.Metadata.DatabaseType != DefaultDatabaseType {
+		t.Fatalf("database type = %q", reader.Metadata.DatabaseType)
+	}
+
+	r := geoip.New(geoip.WithDBPaths(path), geoip.WithChain(geoip.ProviderMMDB), geoip.WithOffline(true), geoip.WithWatch(false))
+	defer r.Close()
+	cases := []struct {
+		ip   string
+		want string
+	}{
+		{"1.9.9.9", "au"},
+		{"1.0.1.1", "us"},   // 覆盖
+		{"1.2.3.4", "cn"},   // 更小的网段优先
+		{"1.2.3.200", "jp"}, // 覆盖中的更小网段
+		{"2a00:1450::1", "de"},
+	}
+	for _, c := range cases {
+		got, err := r.Lookup(net.ParseIP(c.ip))
+		if err != nil || got != c.want {
+			t.Fatalf("Lookup(%s) = %q, %v, want %q", c.ip, got, err, c.want)
+		}
+	}
+	res, err := r.LookupDetail(net.ParseIP("1.2.3.4"))
+	if err != nil || res.CountryName != "China" {
+		t.Fatalf("LookupDetail = %+v, %v", res, err)
+	}
+	if _, err := r.Lookup(net.ParseIP("9.9.9.9")); err == nil {
+		t.Fatalf("expected 9.9.9.9 to be missing")
+	}
+
+	mem := geoip.New(geoip.WithDBPaths(path), geoip.WithDBInMemory(true), geoip.WithChain(geoip.ProviderMMDB), geoip.WithOffline(true), geoip.WithWatch(false))
+	defer mem.Close()
+	if got, err := mem.Lookup(net.ParseIP("1.2.3.200")); err != nil || got != "jp" {
+		t.Fatalf("in-memory Lookup = %q, %v", got, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fix.yaml")
+	os.WriteFile(path, []byte("10.0.0.0/8: hk\n192.0.2.1: sg\n"), 0o644)
+	entries, err := Load(path)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Load = %v, %v", entries, err)
+	}
+	for _, e := range entries {
+		if e.Network == netip.MustParsePrefix("192.0.2.1/32") && e.Country != "sg" {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "fix.txt")); err == nil {
+		t.Fatalf("expected error for unknown extension")
+	}
+	if _, err := ParseCSV(strings.NewReader("1.0.0.0/8,au\nbad,line\n")); err == nil {
+		t.Fatalf("expected error for invalid line")
+	}
+}
+
+func TestDiffDBFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.mmdb"), filepath.Join(dir, "new.mmdb")
+	base := []Entry{
+		{netip.MustParsePrefix("1.0.0.0/8"), "au"},
+		{netip.MustParsePrefix("2.0.0.0/8"), "fr"},
+		{netip.MustParsePrefix("3.0.0.0/8"), "us"},
+	}
+	if err := BuildFile(oldPath, Options{}, base, nil); err != nil {
+		t.Fatalf("BuildFile: %v", err)
+	}
+	fixes := []Entry{
+		{netip.MustParsePrefix("1.2.0.0/16"), "cn"},
+		{netip.MustParsePrefix("2.0.0.0/8"), "de"},
+		{netip.MustParsePrefix("4.0.0.0/8"), "gb"},
+	}
+	if err := BuildFile(newPath, Options{}, base, fixes); err != nil {
+		t.Fatalf("BuildFile: %v", err)
+	}
+
+	diff, err := geoip.DiffDBFiles(oldPath, newPath, 10)
+	if err != nil {
+		t.Fatalf("DiffDBFiles: %v", err)
+	}
+	if diff.Changed != 2 || diff.Added != 1 || diff.Removed != 0 {
+		t.Fatalf("changed=%d added=%d removed=%d", diff.Changed, diff.Added, diff.Removed)
+	}
+	if diff.Transitions["au>cn"] != 1 || diff.Transitions["fr>de"] != 1 || diff.Transitions[">gb"] != 1 {
+		t.Fatalf("transitions = %v", diff.Transitions)
+	}
+	if len(diff.Samples) != 3 {
+		t.Fatalf("samples = %+v", diff.Samples)
+	}
+}
+
+func TestCorruptDatabaseRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.mmdb")
+	if err := BuildFile(path, Options{}, []Entry{{netip.MustParsePrefix("8.0.0.0/8"), "us"}}, nil); err != nil {
+		t.Fatalf("BuildFile: %v", err)
+	}
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	dataStart := reader.Metadata.NodeCount*reader.Metadata.RecordSize/4 + 16
+	reader.Close()
+
+	// 把记录的控制字节改为非法的扩展类型，文件仍然可以打开，但查询时无法解码
+	data, _ := os.ReadFile(path)
+	data[dataStart], data[dataStart+1] = 0, 12
+	os.WriteFile(path, data, 0o644)
+
+	r := geoip.New(geoip.WithDBPaths(path), geoip.WithChain(geoip.ProviderMMDB), geoip.WithOffline(true), geoip.WithWatch(false))
+	defer r.Close()
+	if md, err := r.Metadata(); err == nil && md.Path == path {
+		t.Fatalf("corrupt database should not be used: %+v", md)
+	}
+}
+
+func TestStaleDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.mmdb")
+	opts := Options{BuildTime: time.Now().AddDate(0, 0, -200)}
+	if err := BuildFile(path, opts, []Entry{{netip.MustParsePrefix("8.0.0.0/8"), "us"}}, nil); err != nil {
+		t.Fatalf("BuildFile: %v", err)
+	}
+	for maxAge, want := range map[time.Duration]bool{0: false, 90 * 24 * time.Hour: true, 365 * 24 * time.Hour: false} {
+		r := geoip.New(geoip.WithDBPaths(path), geoip.WithMaxDBAge(maxAge), geoip.WithOffline(true), geoip.WithWatch(false))
+		md, err := r.Metadata()
+		r.Close()
+		if err != nil || md.Stale != want {
+			t.Fatalf("max age %v: Metadata = %+v, %v, want stale %v", maxAge, md, err, want)
+		}
+	}
+}
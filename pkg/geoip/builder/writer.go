@@ -0,0 +1,309 @@
+package builder
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// MaxMind DB 格式的常量，见 https://maxmind.github.io/MaxMind-DB/
+const (
+	recordSize           = 32 // 每个节点两个 32 位记录
+	dataSectionSeparator = 16 // 搜索树与数据区之间的 16 个 0 字节
+)
+
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// Record 是写入数据库的一条记录，值支持 string、bool、整数、float64、[]any 与 map[string]any
+type Record map[string]any
+
+// node 是内存中的搜索树节点，data >= 0 的节点为叶子
+type node struct {
+	children [2]*node
+	data     int
+}
+
+func newLeaf(data int) *node {
+	return &node{data: data}
+}
+
+func (n *node) isLeaf() bool {
+	return n.data >= 0
+}
+
+// Writer 在内存中构建搜索树，最后一次性写出 mmdb
+// 后插入的网段覆盖先插入的重叠部分，因此纠错数据应在基础数据之后插入
+type Writer struct {
+	DatabaseType string
+	Description  string
+	Languages    []string
+	BuildTime    time.Time // 为空时使用当前时间
+
+	root    *node
+	records [][]byte       // 已编码的记录
+	index   map[string]int // 相同的记录只保存一份
+}
+
+// NewWriter 创建一个 IPv6 的数据库，IPv4 地址写入 ::/96 下
+func NewWriter(databaseType string) *Writer {
+	return &Writer{
+		DatabaseType: databaseType,
+		Languages:    []string{"en"},
+		root:         &node{data: -1},
+		index:        make(map[string]int),
+	}
+}
+
+// Insert 写入一个网段，覆盖已有的重叠网段
+func (w *Writer) Insert(prefix netip.Prefix, record Record) error {
+	if !prefix.IsValid() {
+		return errors.New("geoip: invalid network")
+	}
+	prefix = prefix.Masked()
+	addr, bits := prefix.Addr(), prefix.Bits()
+	if addr.Is4() {
+		bits += 96
+	}
+	if bits == 0 {
+		return fmt.Errorf("geoip: network %s is too large", prefix)
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, map[string]any(record)); err != nil {
+		return err
+	}
+	data, ok := w.index[buf.String()]
+	if !ok {
+		data = len(w.records)
+		w.records = append(w.records, buf.Bytes())
+		w.index[buf.String()] = data
+	}
+
+	ip := addr.As16()
+	if addr.Is4() {
+		// Is4 地址的 As16 是 ::ffff:a.b.c.d，这里需要 ::a.b.c.d
+		ip = [16]byte{}
+		v4 := addr.As4()
+		copy(ip[12:], v4[:])
+	}
+	cur := w.root
+	for i := 0; i < bits-1; i++ {
+		bit := ip[i/8] >> (7 - i%8) & 1
+		child := cur.children[bit]
+		switch {
+		case child == nil:
+			child = &node{data: -1}
+		case child.isLeaf():
+			// 拆分已有的大网段，两侧先继承原来的数据
+			child = &node{children: [2]*node{newLeaf(child.data), newLeaf(child.data)}, data: -1}
+		}
+		cur.children[bit] = child
+		cur = child
+	}
+	last := bits - 1
+	cur.children[ip[last/8]>>(7-last%8)&1] = newLeaf(data)
+	return nil
+}
+
+// WriteTo 按 MaxMind DB 格式写出数据库
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	// 按广度优先为内部节点编号，根节点为 0
+	var nodes []*node
+	ids := make(map[*node]uint32)
+	queue := []*node{w.root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		ids[n] = uint32(len(nodes))
+		nodes = append(nodes, n)
+		for _, child := range n.children {
+			if child != nil && !child.isLeaf() {
+				queue = append(queue, child)
+			}
+		}
+	}
+	nodeCount := uint32(len(nodes))
+
+	// 计算每条记录在数据区中的偏移
+	offsets := make([]uint32, len(w.records))
+	var dataSize uint32
+	for i, rec := range w.records {
+		offsets[i] = dataSize
+		dataSize += uint32(len(rec))
+	}
+	if uint64(nodeCount)+dataSectionSeparator+uint64(dataSize) > math.MaxUint32 {
+		return 0, errors.New("geoip: database is too large")
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(int(nodeCount)*recordSize/4 + dataSectionSeparator + int(dataSize))
+	record := make([]byte, 4)
+	for _, n := range nodes {
+		for _, child := range n.children {
+			var value uint32
+			switch {
+			case child == nil:
+				value = nodeCount
+			case child.isLeaf():
+				value = nodeCount + dataSectionSeparator + offsets[child.data]
+			default:
+				value = ids[child]
+			}
+			binary.BigEndian.PutUint32(record, value)
+			buf.Write(record)
+		}
+	}
+	buf.Write(make([]byte, dataSectionSeparator))
+	for _, rec := range w.records {
+		buf.Write(rec)
+	}
+
+	buf.Write(metadataMarker)
+	description := map[string]any{}
+	if w.Description != "" {
+		description["en"] = w.Description
+	}
+	languages := make([]any, len(w.Languages))
+	for i, lang := range w.Languages {
+		languages[i] = lang
+	}
+	metadata := map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(cmp.Or(w.BuildTime, time.Now()).Unix()),
+		"database_type":               w.DatabaseType,
+		"description":                 description,
+		"ip_version":                  uint16(6),
+		"languages":                   languages,
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(recordSize),
+	}
+	if err := encode(&buf, metadata); err != nil {
+		return 0, err
+	}
+	return buf.WriteTo(out)
+}
+
+// MaxMind DB 数据区的类型编号
+const (
+	typeString  = 2
+	typeDouble  = 3
+	typeUint16  = 5
+	typeUint32  = 6
+	typeMap     = 7
+	typeInt32   = 8
+	typeUint64  = 9
+	typeArray   = 11
+	typeBoolean = 14
+)
+
+// writeControl 写入控制字节，类型编号大于 7 的使用扩展类型
+func writeControl(buf *bytes.Buffer, typ int, size int) {
+	var ctrl byte
+	if typ <= 7 {
+		ctrl = byte(typ) << 5
+	}
+	var ext []byte
+	switch {
+	case size < 29:
+		ctrl |= byte(size)
+	case size < 29+256:
+		ctrl |= 29
+		ext = []byte{byte(size - 29)}
+	case size < 285+65536:
+		ctrl |= 30
+		ext = binary.BigEndian.AppendUint16(nil, uint16(size-285))
+	default:
+		ctrl |= 31
+		s := uint32(size - 65821)
+		ext = []byte{byte(s >> 16), byte(s >> 8), byte(s)}
+	}
+	buf.WriteByte(ctrl)
+	if typ > 7 {
+		buf.WriteByte(byte(typ - 7))
+	}
+	buf.Write(ext)
+}
+
+// writeUint 以最少的字节数写入无符号整数
+func writeUint(buf *bytes.Buffer, typ int, v uint64) {
+	b := binary.BigEndian.AppendUint64(nil, v)
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	writeControl(buf, typ, len(b))
+	buf.Write(b)
+}
+
+func encode(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case string:
+		writeControl(buf, typeString, len(v))
+		buf.WriteString(v)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		writeControl(buf, typeBoolean, size)
+	case float64:
+		writeControl(buf, typeDouble, 8)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v)))
+	case uint16:
+		writeUint(buf, typeUint16, uint64(v))
+	case uint32:
+		writeUint(buf, typeUint32, uint64(v))
+	case uint64:
+		writeUint(buf, typeUint64, v)
+	case uint:
+		writeUint(buf, typeUint32, uint64(v))
+	case int:
+		if v < 0 || v > math.MaxUint32 {
+			if v < math.MinInt32 || v > math.MaxInt32 {
+				return fmt.Errorf("geoip: integer %d out of range", v)
+			}
+			writeControl(buf, typeInt32, 4)
+			buf.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(v))))
+			return nil
+		}
+		writeUint(buf, typeUint32, uint64(v))
+	case []any:
+		writeControl(buf, typeArray, len(v))
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+	case []string:
+		writeControl(buf, typeArray, len(v))
+		for _, item := range v {
+			encode(buf, item)
+		}
+	case Record:
+		return encode(buf, map[string]any(v))
+	case map[string]any:
+		// 键排序后写入，相同的记录编码结果相同
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		writeControl(buf, typeMap, len(v))
+		for _, key := range keys {
+			encode(buf, key)
+			if err := encode(buf, v[key]); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	default:
+		return fmt.Errorf("geoip: unsupported value type %T", v)
+	}
+	return nil
+}
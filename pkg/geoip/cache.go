@@ -0,0 +1,210 @@
+package geoip
+
+import (
+	"container/list"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache 缓存查询结果，key 为 IP 的字符串形式
+// 实现需要支持并发调用；Get 返回的 Result 会被复制后再交给调用方
+type Cache interface {
+	Get(key string) (*Result, bool)
+	Set(key string, res *Result)
+}
+
+// 默认实例使用的 LRU 缓存参数，可以通过 GEOIP_CACHE_SIZE、GEOIP_CACHE_TTL 修改
+const (
+	defaultCacheSize = 4096
+	defaultCacheTTL  = 24 * time.Hour
+)
+
+// CacheStats 是缓存的命中统计
+type CacheStats struct {
+	Enabled  bool           `json:"enabled"`
+	Size     int            `json:"size"`
+	Capacity int            `json:"capacity"`
+	Hits     uint64         `json:"hits"`
+	Misses   uint64         `json:"misses"`
+	HitRate  float64        `json:"hit_rate"`          // 命中次数占查询次数的比例，没有查询时为 0
+	Sources  map[string]int `json:"sources,omitempty"` // 按数据来源统计缓存中的结果数，见 Source* 常量
+	Negative int            `json:"negative"`          // WithNegativeCache 缓存的失败结果数
+}
+
+// statsCache 是可以报告命中统计的缓存
+type statsCache interface {
+	Stats() CacheStats
+}
+
+// purgeCache 是可以删除结果的缓存，Resolver.PurgeCache 与 Resolver.Invalidate 需要缓存实现这个接口
+type purgeCache interface {
+	Delete(key string)
+	Purge()
+}
+
+// LRUCache 是有容量上限的内存缓存，超过容量时淘汰最久未使用的结果，超过 ttl 的结果视为未命中
+// 同一台服务器每次重连都会查询相同的 IP，缓存后不再消耗在线接口的额度
+type LRUCache struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List // 队首为最近使用的结果
+	items map[string]*list.Element
+
+	hits, misses atomic.Uint64
+}
+
+type lruEntry struct {
+	key     string
+	res     *Result
+	expires time.Time // 为零值时不过期
+}
+
+// NewLRUCache 创建最多保存 size 个结果的缓存，ttl 为 0 时结果不过期
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:  max(size, 1),
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	c.hits.Add(1)
+	return entry.res, true
+}
+
+func (c *LRUCache) Set(key string, res *Result) {
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value = &lruEntry{key: key, res: res, expires: expires}
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, res: res, expires: expires})
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete 删除一个结果
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// Purge 清空缓存，命中统计不会清零
+func (c *LRUCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.items)
+}
+
+// Len 返回缓存中的结果数，包括已过期但尚未淘汰的结果
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Stats 返回缓存的大小、命中次数以及各数据来源的结果数
+func (c *LRUCache) Stats() CacheStats {
+	sources := make(map[string]int)
+	c.mu.Lock()
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		sources[el.Value.(*lruEntry).res.Source]++
+	}
+	size := c.ll.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Size:     size,
+		Capacity: c.size,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Sources:  sources,
+	}
+}
+
+// Stats 返回缓存的统计，缓存不支持统计时只有 Enabled 与 Negative
+func (r *Resolver) Stats() CacheStats {
+	var stats CacheStats
+	if c, ok := r.cache.(statsCache); ok {
+		stats = c.Stats()
+	}
+	stats.Enabled = r.cache != nil
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	stats.Negative = r.negative.len()
+	return stats
+}
+
+// PurgeCache 清空查询结果与失败结果的缓存
+func (r *Resolver) PurgeCache() {
+	if c, ok := r.cache.(purgeCache); ok {
+		c.Purge()
+	}
+	r.negative.purge()
+}
+
+// Invalidate 删除一个 IP 的缓存结果，服务器更换 IP 所属的服务商后，下一次查询会重新获取国家
+func (r *Resolver) Invalidate(ip net.IP) {
+	if ip == nil {
+		return
+	}
+	key := ip.String()
+	if c, ok := r.cache.(purgeCache); ok {
+		c.Delete(key)
+	}
+	r.negative.delete(key)
+}
+
+// Stats 返回默认实例的缓存统计，见 Resolver.Stats
+func Stats() CacheStats {
+	return Default().Stats()
+}
+
+// PurgeCache 清空默认实例的缓存
+func PurgeCache() {
+	Default().PurgeCache()
+}
+
+// Invalidate 删除默认实例中一个 IP 的缓存结果
+func Invalidate(ip net.IP) {
+	Default().Invalidate(ip)
+}
@@ -0,0 +1,117 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestResolverCache(t *testing.T) {
+	p := &countingProvider{staticProvider: staticProvider{name: "counting", res: &Result{CountryCode: "fr"}}}
+	r := New(WithProviders(p), WithCache(&mapCache{m: make(map[string]*Result)}))
+
+	for range 3 {
+		res, err := r.LookupDetail(net.ParseIP("1.1.1.1"))
+		if err != nil || res.CountryCode != "fr" || !res.IsEU {
+			t.Fatalf("LookupDetail = %+v, %v", res, err)
+		}
+		// 修改返回值不应影响缓存中的结果
+		res.CountryCode = "xx"
+	}
+	if p.calls != 1 {
+		t.Fatalf("provider called %d times, expected 1", p.calls)
+	}
+
+	// 不同的 Resolver 互不影响
+	other := New(WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "it"}}))
+	if code, _ := other.Lookup(net.ParseIP("1.1.1.1")); code != "it" {
+		t.Fatalf("other.Lookup = %q, expected it", code)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2, 0)
+	c.Set("a", &Result{CountryCode: "us"})
+	c.Set("b", &Result{CountryCode: "de"})
+	c.Get("a") // a 变为最近使用
+	c.Set("c", &Result{CountryCode: "jp"})
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("b should have been evicted")
+	}
+	if res, ok := c.Get("a"); !ok || res.CountryCode != "us" {
+		t.Fatalf("Get(a) = %+v, %v", res, ok)
+	}
+	if stats := c.Stats(); stats.Size != 2 || stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+
+	expiring := NewLRUCache(10, time.Millisecond)
+	expiring.Set("a", &Result{CountryCode: "us"})
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.Get("a"); ok || expiring.Len() != 0 {
+		t.Fatalf("expired entry should be removed")
+	}
+
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{CountryCode: "fr"}}}
+	r := New(WithProviders(p), WithCache(NewLRUCache(10, time.Hour)))
+	for range 3 {
+		r.Lookup(net.ParseIP("1.1.1.1"))
+	}
+	if stats := r.Stats(); p.calls != 1 || !stats.Enabled || stats.Hits != 2 || stats.Size != 1 {
+		t.Fatalf("calls = %d, stats = %+v", p.calls, stats)
+	}
+}
+
+func TestReloadPurgesCache(t *testing.T) {
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{CountryCode: "fr"}}}
+	r := newTestResolver(t, WithProviders(p), WithCache(NewLRUCache(10, time.Hour)))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	if p.calls != 1 {
+		t.Fatalf("provider called %d times, expected 1", p.calls)
+	}
+
+	// 替换数据库后同一个 IP 应重新查询，新加载失败时也一样
+	r.Reload()
+	if res, err := r.LookupDetail(net.ParseIP("1.1.1.1")); err != nil || res.CountryCode != "fr" {
+		t.Fatalf("LookupDetail after reload = %+v, %v", res, err)
+	}
+	if p.calls != 2 {
+		t.Fatalf("provider called %d times after reload, expected 2", p.calls)
+	}
+
+	// 本地数据库的结果不缓存
+	local := &countingProvider{staticProvider: staticProvider{name: ProviderMMDB, res: &Result{CountryCode: "de"}}}
+	r = New(WithProviders(local), WithCache(NewLRUCache(10, time.Hour)))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	if stats := r.Stats(); local.calls != 2 || stats.Size != 0 {
+		t.Fatalf("calls = %d, stats = %+v", local.calls, stats)
+	}
+}
+
+func TestPurgeCache(t *testing.T) {
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{CountryCode: "fr"}}}
+	r := New(WithProviders(p), WithCache(NewLRUCache(10, time.Hour)))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("2.2.2.2"))
+
+	r.Invalidate(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("2.2.2.2"))
+	if p.calls != 3 {
+		t.Fatalf("provider called %d times after Invalidate, expected 3", p.calls)
+	}
+
+	r.PurgeCache()
+	if stats := r.Stats(); stats.Size != 0 {
+		t.Fatalf("Stats after PurgeCache = %+v", stats)
+	}
+	r.Lookup(net.ParseIP("2.2.2.2"))
+	if p.calls != 4 {
+		t.Fatalf("provider called %d times after PurgeCache, expected 4", p.calls)
+	}
+	if stats := r.Stats(); stats.HitRate != 0.2 {
+		t.Fatalf("HitRate = %v", stats.HitRate)
+	}
+}
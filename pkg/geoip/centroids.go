@@ -0,0 +1,287 @@
+package geoip
+
+import "strings"
+
+// countryCentroids 是各国家/地区的近似地理中心，纬度在前，只用于没有城市坐标时在地图上标注国家
+// 精确到 0.1 度左右，对于国土跨度很大或由分散岛屿组成的国家只是一个大致的位置
+var countryCentroids = map[string][2]float64{
+	"ad": {42.5, 1.6},
+	"ae": {23.4, 53.8},
+	"af": {33.9, 67.7},
+	"ag": {17.1, -61.8},
+	"ai": {18.2, -63.1},
+	"al": {41.2, 20.2},
+	"am": {40.1, 45.0},
+	"ao": {-11.2, 17.9},
+	"aq": {-75.3, -0.1},
+	"ar": {-38.4, -63.6},
+	"as": {-14.3, -170.1},
+	"at": {47.5, 14.6},
+	"au": {-25.3, 133.8},
+	"aw": {12.5, -70.0},
+	"ax": {60.2, 20.0},
+	"az": {40.1, 47.6},
+	"ba": {43.9, 17.7},
+	"bb": {13.2, -59.5},
+	"bd": {23.7, 90.4},
+	"be": {50.5, 4.5},
+	"bf": {12.2, -1.6},
+	"bg": {42.7, 25.5},
+	"bh": {26.0, 50.6},
+	"bi": {-3.4, 29.9},
+	"bj": {9.3, 2.3},
+	"bl": {17.9, -62.8},
+	"bm": {32.3, -64.8},
+	"bn": {4.5, 114.7},
+	"bo": {-16.3, -63.6},
+	"bq": {12.2, -68.3},
+	"br": {-14.2, -51.9},
+	"bs": {25.0, -77.4},
+	"bt": {27.5, 90.4},
+	"bv": {-54.4, 3.4},
+	"bw": {-22.3, 24.7},
+	"by": {53.7, 27.9},
+	"bz": {17.2, -88.5},
+	"ca": {56.1, -106.3},
+	"cc": {-12.2, 96.9},
+	"cd": {-4.0, 21.8},
+	"cf": {6.6, 20.9},
+	"cg": {-0.2, 15.8},
+	"ch": {46.8, 8.2},
+	"ci": {7.5, -5.5},
+	"ck": {-21.2, -159.8},
+	"cl": {-35.7, -71.5},
+	"cm": {7.4, 12.4},
+	"cn": {35.9, 104.2},
+	"co": {4.6, -74.3},
+	"cr": {9.7, -83.8},
+	"cu": {21.5, -77.8},
+	"cv": {16.0, -24.0},
+	"cw": {12.2, -69.0},
+	"cx": {-10.4, 105.7},
+	"cy": {35.1, 33.4},
+	"cz": {49.8, 15.5},
+	"de": {51.2, 10.5},
+	"dj": {11.8, 42.6},
+	"dk": {56.3, 9.5},
+	"dm": {15.4, -61.4},
+	"do": {18.7, -70.2},
+	"dz": {28.0, 1.7},
+	"ec": {-1.8, -78.2},
+	"ee": {58.6, 25.0},
+	"eg": {26.8, 30.8},
+	"eh": {24.2, -12.9},
+	"er": {15.2, 39.8},
+	"es": {40.5, -3.7},
+	"et": {9.1, 40.5},
+	"fi": {61.9, 25.7},
+	"fj": {-16.6, 179.4},
+	"fk": {-51.8, -59.5},
+	"fm": {7.4, 150.6},
+	"fo": {61.9, -6.9},
+	"fr": {46.2, 2.2},
+	"ga": {-0.8, 11.6},
+	"gb": {55.4, -3.4},
+	"gd": {12.3, -61.6},
+	"ge": {42.3, 43.4},
+	"gf": {3.9, -53.1},
+	"gg": {49.5, -2.6},
+	"gh": {7.9, -1.0},
+	"gi": {36.1, -5.3},
+	"gl": {71.7, -42.6},
+	"gm": {13.4, -15.3},
+	"gn": {9.9, -9.7},
+	"gp": {16.3, -61.6},
+	"gq": {1.7, 10.3},
+	"gr": {39.1, 21.8},
+	"gs": {-54.4, -36.6},
+	"gt": {15.8, -90.2},
+	"gu": {13.4, 144.8},
+	"gw": {11.8, -15.2},
+	"gy": {4.9, -58.9},
+	"hk": {22.3, 114.2},
+	"hm": {-53.1, 73.5},
+	"hn": {15.2, -86.2},
+	"hr": {45.1, 15.2},
+	"ht": {19.0, -72.3},
+	"hu": {47.2, 19.5},
+	"id": {-0.8, 113.9},
+	"ie": {53.4, -8.2},
+	"il": {31.0, 34.9},
+	"im": {54.2, -4.5},
+	"in": {20.6, 79.0},
+	"io": {-6.3, 71.9},
+	"iq": {33.2, 43.7},
+	"ir": {32.4, 53.7},
+	"is": {65.0, -19.0},
+	"it": {41.9, 12.6},
+	"je": {49.2, -2.1},
+	"jm": {18.1, -77.3},
+	"jo": {30.6, 36.2},
+	"jp": {36.2, 138.3},
+	"ke": {0.0, 37.9},
+	"kg": {41.2, 74.8},
+	"kh": {12.6, 105.0},
+	"ki": {-3.4, -168.7},
+	"km": {-11.9, 43.9},
+	"kn": {17.4, -62.8},
+	"kp": {40.3, 127.5},
+	"kr": {35.9, 127.8},
+	"kw": {29.3, 47.5},
+	"ky": {19.5, -80.6},
+	"kz": {48.0, 66.9},
+	"la": {19.9, 102.5},
+	"lb": {33.9, 35.9},
+	"lc": {13.9, -61.0},
+	"li": {47.2, 9.6},
+	"lk": {7.9, 80.8},
+	"lr": {6.4, -9.4},
+	"ls": {-29.6, 28.2},
+	"lt": {55.2, 23.9},
+	"lu": {49.8, 6.1},
+	"lv": {56.9, 24.6},
+	"ly": {26.3, 17.2},
+	"ma": {31.8, -7.1},
+	"mc": {43.7, 7.4},
+	"md": {47.4, 28.4},
+	"me": {42.7, 19.4},
+	"mf": {18.1, -63.1},
+	"mg": {-18.8, 46.9},
+	"mh": {7.1, 171.2},
+	"mk": {41.6, 21.7},
+	"ml": {17.6, -4.0},
+	"mm": {21.9, 96.0},
+	"mn": {46.9, 103.8},
+	"mo": {22.2, 113.5},
+	"mp": {17.3, 145.4},
+	"mq": {14.6, -61.0},
+	"mr": {21.0, -10.9},
+	"ms": {16.7, -62.2},
+	"mt": {35.9, 14.4},
+	"mu": {-20.3, 57.6},
+	"mv": {3.2, 73.2},
+	"mw": {-13.3, 34.3},
+	"mx": {23.6, -102.6},
+	"my": {4.2, 102.0},
+	"mz": {-18.7, 35.5},
+	"na": {-23.0, 18.5},
+	"nc": {-20.9, 165.6},
+	"ne": {17.6, 8.1},
+	"nf": {-29.0, 168.0},
+	"ng": {9.1, 8.7},
+	"ni": {12.9, -85.2},
+	"nl": {52.1, 5.3},
+	"no": {60.5, 8.5},
+	"np": {28.4, 84.1},
+	"nr": {-0.5, 166.9},
+	"nu": {-19.1, -169.9},
+	"nz": {-40.9, 174.9},
+	"om": {21.5, 55.9},
+	"pa": {8.5, -80.8},
+	"pe": {-9.2, -75.0},
+	"pf": {-17.7, -149.4},
+	"pg": {-6.3, 143.9},
+	"ph": {12.9, 121.8},
+	"pk": {30.4, 69.3},
+	"pl": {51.9, 19.1},
+	"pm": {46.9, -56.3},
+	"pn": {-24.7, -127.4},
+	"pr": {18.2, -66.6},
+	"ps": {31.9, 35.2},
+	"pt": {39.4, -8.2},
+	"pw": {7.5, 134.6},
+	"py": {-23.4, -58.4},
+	"qa": {25.4, 51.2},
+	"re": {-21.1, 55.5},
+	"ro": {45.9, 25.0},
+	"rs": {44.0, 21.0},
+	"ru": {61.5, 105.3},
+	"rw": {-1.9, 29.9},
+	"sa": {23.9, 45.1},
+	"sb": {-9.6, 160.2},
+	"sc": {-4.7, 55.5},
+	"sd": {12.9, 30.2},
+	"se": {60.1, 18.6},
+	"sg": {1.4, 103.8},
+	"sh": {-24.1, -10.0},
+	"si": {46.2, 15.0},
+	"sj": {77.6, 23.7},
+	"sk": {48.7, 19.7},
+	"sl": {8.5, -11.8},
+	"sm": {43.9, 12.5},
+	"sn": {14.5, -14.5},
+	"so": {5.2, 46.2},
+	"sr": {3.9, -56.0},
+	"ss": {7.9, 30.0},
+	"st": {0.2, 6.6},
+	"sv": {13.8, -88.9},
+	"sx": {18.0, -63.1},
+	"sy": {34.8, 39.0},
+	"sz": {-26.5, 31.5},
+	"tc": {21.7, -71.8},
+	"td": {15.5, 18.7},
+	"tf": {-49.3, 69.3},
+	"tg": {8.6, 0.8},
+	"th": {15.9, 101.0},
+	"tj": {38.9, 71.3},
+	"tk": {-8.9, -171.9},
+	"tl": {-8.9, 125.7},
+	"tm": {39.0, 59.6},
+	"tn": {33.9, 9.5},
+	"to": {-21.2, -175.2},
+	"tr": {39.0, 35.2},
+	"tt": {10.7, -61.2},
+	"tv": {-7.1, 177.6},
+	"tw": {23.7, 121.0},
+	"tz": {-6.4, 34.9},
+	"ua": {48.4, 31.2},
+	"ug": {1.4, 32.3},
+	"um": {19.3, 166.6},
+	"us": {37.1, -95.7},
+	"uy": {-32.5, -55.8},
+	"uz": {41.4, 64.6},
+	"va": {41.9, 12.5},
+	"vc": {13.0, -61.3},
+	"ve": {6.4, -66.6},
+	"vg": {18.4, -64.6},
+	"vi": {18.3, -64.9},
+	"vn": {14.1, 108.3},
+	"vu": {-15.4, 166.9},
+	"wf": {-13.8, -177.2},
+	"ws": {-13.8, -172.1},
+	"xk": {42.6, 20.9},
+	"ye": {15.6, 48.5},
+	"yt": {-12.8, 45.2},
+	"za": {-30.6, 22.9},
+	"zm": {-13.1, 27.8},
+	"zw": {-19.0, 29.2},
+}
+
+// CountryCentroid 返回国家/地区的近似地理中心，code 不区分大小写
+func CountryCentroid(code string) (Location, bool) {
+	c, ok := countryCentroids[strings.ToLower(code)]
+	if !ok {
+		return Location{}, false
+	}
+	return Location{Latitude: c[0], Longitude: c[1]}, true
+}
+
+// continentCentroids 是各洲的大致中心，用于在世界地图上按洲聚合
+var continentCentroids = map[string][2]float64{
+	"af": {1.6, 17.3},
+	"an": {-82.9, 0.0},
+	"as": {34.0, 100.6},
+	"eu": {54.5, 15.3},
+	"na": {40.0, -100.0},
+	"oc": {-22.7, 140.0},
+	"sa": {-15.6, -56.1},
+}
+
+// ContinentCentroid 返回洲的大致中心，code 不区分大小写
+func ContinentCentroid(code string) (Location, bool) {
+	c, ok := continentCentroids[strings.ToLower(code)]
+	if !ok {
+		return Location{}, false
+	}
+	return Location{Latitude: c[0], Longitude: c[1]}, true
+}
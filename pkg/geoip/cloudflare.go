@@ -0,0 +1,104 @@
+package geoip
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SourceCloudflare 表示结果来自 Cloudflare 的 /cdn-cgi/trace
+const SourceCloudflare = "cloudflare-trace"
+
+// 按顺序尝试，1.1.1.1 用于 DNS 不可用或被污染的环境
+var cloudflareTraceEndpoints = []string{
+	"https://www.cloudflare.com/cdn-cgi/trace",
+	"https://1.1.1.1/cdn-cgi/trace",
+}
+
+// cloudflareProvider 通过 Cloudflare 的 trace 接口获取调用方自身的公网 IP 与国家码，无需 token
+// 它只能回答调用方自己的 IP，适合 agent 检测自身位置；查询其他 IP 时返回 ErrNotFound
+type cloudflareProvider struct {
+	httpFetcher
+	endpoints []string
+}
+
+func (p *cloudflareProvider) Name() string {
+	return ProviderCloudflare
+}
+
+func (p *cloudflareProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+	self, res, err := p.trace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !self.Equal(ip) {
+		return nil, fmt.Errorf("%w: cloudflare trace only knows the caller's own ip", ErrNotFound)
+	}
+	return res, nil
+}
+
+// trace 依次请求各个 trace 地址，返回调用方的公网 IP 与国家码
+func (p *cloudflareProvider) trace(ctx context.Context) (net.IP, *Result, error) {
+	var errs []error
+	for _, endpoint := range p.endpoints {
+		body, err := p.get(ctx, endpoint)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ip, res, err := parseCloudflareTrace(body)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return ip, res, nil
+	}
+	if len(errs) == 0 {
+		return nil, nil, ErrProviderUnavailable
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+// parseCloudflareTrace 解析 trace 的 key=value 格式响应，如
+//
+//	ip=203.0.113.7
+//	colo=HKG
+//	loc=HK
+func parseCloudflareTrace(body []byte) (net.IP, *Result, error) {
+	var ip net.IP
+	var loc string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "ip":
+			ip = net.ParseIP(value)
+		case "loc":
+			loc = value
+		}
+	}
+	if ip == nil {
+		return nil, nil, fmt.Errorf("%w: no ip in cloudflare trace", ErrProviderUnavailable)
+	}
+	// 无法定位时 loc 为 XX
+	if len(loc) != 2 || strings.EqualFold(loc, "XX") {
+		return ip, nil, fmt.Errorf("%w: no country in cloudflare trace", ErrNotFound)
+	}
+
+	code := strings.ToLower(loc)
+	return ip, &Result{
+		CountryCode: code,
+		CountryName: CountryName(code, "en"),
+		Source:      SourceCloudflare,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloudflareTrace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fl=123f1\nh=www.cloudflare.com\nip=203.0.113.7\nts=1700000000.1\ncolo=HKG\nloc=HK\ntls=TLSv1.3\n"))
+	}))
+	defer srv.Close()
+
+	p := &cloudflareProvider{
+		httpFetcher: newTestFetcher(ProviderCloudflare, srv),
+		endpoints:   []string{"http://127.0.0.1:1/unreachable", srv.URL},
+	}
+	ip, res, err := p.trace(context.Background())
+	if err != nil {
+		t.Fatalf("trace: %v", err)
+	}
+	if ip.String() != "203.0.113.7" || res.CountryCode != "hk" || res.Source != SourceCloudflare {
+		t.Fatalf("unexpected trace result: %s %+v", ip, res)
+	}
+	if _, err := p.Lookup(context.Background(), net.ParseIP("8.8.8.8")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected ErrNotFound", err)
+	}
+
+	if _, _, err := parseCloudflareTrace([]byte("ip=203.0.113.7\nloc=XX\n")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("parseCloudflareTrace error = %v, expected ErrNotFound", err)
+	}
+}
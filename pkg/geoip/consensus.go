@@ -0,0 +1,98 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+)
+
+// consensusProviders 按名称返回 WithConsensus 指定的数据源，名称可以是内置 Provider 或已注册的 Provider
+func (r *Resolver) consensusProviders() []Provider {
+	if len(r.consensus) < 2 {
+		return nil
+	}
+	r.providersMu.RLock()
+	defer r.providersMu.RUnlock()
+
+	var ps []Provider
+	for _, name := range r.consensus {
+		if i := r.indexProvider(name); i >= 0 {
+			ps = append(ps, r.providers[i])
+		} else if p, ok := r.builtins[name]; ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// resolveConsensus 同时请求 WithConsensus 指定的数据源，返回多数数据源一致的国家对应的结果
+// 票数相同时按 WithConsensus 中的顺序选择；Confidence 为一致的数据源占返回了结果的数据源的比例
+// 所有数据源都查不到时 ok 为 false，继续按查询链查询
+func (r *Resolver) resolveConsensus(ctx context.Context, ip net.IP, key string) (res *Result, provider string, ok bool) {
+	ps := r.consensusProviders()
+	if len(ps) == 0 {
+		return nil, "", false
+	}
+
+	results := make([]*Result, len(ps))
+	var wg sync.WaitGroup
+	for i, p := range ps {
+		wg.Go(func() {
+			if res, err := r.query(ctx, p, ip); err == nil {
+				results[i] = res
+			} else {
+				r.log.verbose(levelForError(err), "consensus provider failed", "provider", p.Name(), "ip", key, "error", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	votes := make(map[string]int)
+	var order []string // 按 WithConsensus 的顺序出现的国家，用于票数相同时的选择
+	answered := 0
+	for _, res := range results {
+		if res == nil {
+			continue
+		}
+		answered++
+		code := res.Code()
+		if votes[code] == 0 {
+			order = append(order, code)
+		}
+		votes[code]++
+	}
+	if answered == 0 {
+		return nil, "", false
+	}
+	winner := order[0]
+	for _, code := range order[1:] {
+		if votes[code] > votes[winner] {
+			winner = code
+		}
+	}
+
+	if len(votes) > 1 {
+		var detail []string
+		for i, res := range results {
+			if res != nil {
+				detail = append(detail, ps[i].Name()+"="+res.Code())
+			}
+		}
+		r.log.warn("providers disagree on country", "ip", key, "chosen", winner, "results", strings.Join(detail, ","))
+	}
+
+	for i, candidate := range results {
+		if candidate == nil || candidate.Code() != winner {
+			continue
+		}
+		if res == nil {
+			res, provider = candidate, ps[i].Name()
+			continue
+		}
+		// 一致的数据源之间互相补全城市、坐标等字段
+		res.fill(candidate)
+	}
+	res.Confidence = float64(votes[winner]) / float64(answered)
+	return res, provider, true
+}
@@ -0,0 +1,40 @@
+package geoip
+
+import (
+	"bytes"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestConsensus(t *testing.T) {
+	var buf bytes.Buffer
+	r := newTestResolver(t,
+		WithProviders(
+			&staticProvider{name: "a", res: &Result{CountryCode: "de"}},
+			&staticProvider{name: "b", res: &Result{CountryCode: "fr", City: &City{Name: "Paris"}}},
+			&staticProvider{name: "c", res: &Result{CountryCode: "fr"}},
+			&staticProvider{name: "d", err: ErrProviderUnavailable},
+		),
+		WithConsensus("a", "b", "c", "d"),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	res, err := r.LookupDetail(net.ParseIP("1.1.1.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CountryCode != "fr" || res.City == nil || res.Confidence < 0.66 || res.Confidence > 0.67 {
+		t.Fatalf("unexpected consensus result: %+v", res)
+	}
+	if !strings.Contains(buf.String(), "providers disagree") {
+		t.Fatalf("disagreement not logged: %s", buf.String())
+	}
+
+	// 票数相同时使用 WithConsensus 中靠前的数据源
+	r.consensus = []string{"a", "b"}
+	r.PurgeCache()
+	if res, _ := r.LookupDetail(net.ParseIP("1.1.1.1")); res.CountryCode != "de" || res.Confidence != 0.5 {
+		t.Fatalf("unexpected tie-break result: %+v", res)
+	}
+}
@@ -0,0 +1,109 @@
+package geoip
+
+import (
+	"slices"
+	"strings"
+)
+
+//go:generate go run gen_countries.go
+
+// countryNames 中名称的语言顺序
+const (
+	langEN = iota
+	langZhCN
+	langZhTW
+	langJA
+	langCount
+)
+
+// CountryName 返回国家码对应的本地化名称
+// lang 支持 en、zh-CN、zh_TW、zh-Hant、ja 等写法，不支持的语言回退到英文；未知国家码返回空字符串
+func CountryName(code, lang string) string {
+	names, ok := countryNames[strings.ToLower(code)]
+	if !ok {
+		return ""
+	}
+	return names[langIndex(lang)]
+}
+
+func langIndex(lang string) int {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	switch {
+	case lang == "zh-tw" || lang == "zh-hk" || lang == "zh-mo" || strings.HasPrefix(lang, "zh-hant"):
+		return langZhTW
+	case lang == "zh" || strings.HasPrefix(lang, "zh-"):
+		return langZhCN
+	case lang == "ja" || strings.HasPrefix(lang, "ja-"):
+		return langJA
+	}
+	return langEN
+}
+
+// FlagEmoji 将 2 位国家码转换为国旗 emoji，如 "hk" → 🇭🇰；非法输入返回空字符串
+func FlagEmoji(code string) string {
+	if len(code) != 2 {
+		return ""
+	}
+	a, b := code[0]|0x20, code[1]|0x20 // 转为小写
+	if a < 'a' || a > 'z' || b < 'a' || b > 'z' {
+		return ""
+	}
+	return flagEmojis[a-'a'][b-'a']
+}
+
+// flagEmojis 是预先生成的所有两位字母组合的国旗，查询时不需要分配内存
+var flagEmojis = func() (flags [26][26]string) {
+	for a := range 26 {
+		for b := range 26 {
+			// Regional Indicator Symbol Letter A 为 U+1F1E6
+			flags[a][b] = string([]rune{0x1F1E6 + rune(a), 0x1F1E6 + rune(b)})
+		}
+	}
+	return flags
+}()
+
+// 欧盟成员国（2020 年英国脱欧后共 27 国）
+var euCountries = map[string]struct{}{
+	"at": {}, "be": {}, "bg": {}, "cy": {}, "cz": {}, "de": {}, "dk": {},
+	"ee": {}, "es": {}, "fi": {}, "fr": {}, "gr": {}, "hr": {}, "hu": {},
+	"ie": {}, "it": {}, "lt": {}, "lu": {}, "lv": {}, "mt": {}, "nl": {},
+	"pl": {}, "pt": {}, "ro": {}, "se": {}, "si": {}, "sk": {},
+}
+
+// continentCodes 是所有洲代码，与 MaxMind/GeoNames 相同
+var continentCodes = []string{"af", "an", "as", "eu", "na", "oc", "sa"}
+
+var continentNames = map[string][langCount]string{
+	"af": {"Africa", "非洲", "非洲", "アフリカ"},
+	"an": {"Antarctica", "南极洲", "南極洲", "南極大陸"},
+	"as": {"Asia", "亚洲", "亞洲", "アジア"},
+	"eu": {"Europe", "欧洲", "歐洲", "ヨーロッパ"},
+	"na": {"North America", "北美洲", "北美洲", "北アメリカ"},
+	"oc": {"Oceania", "大洋洲", "大洋洲", "オセアニア"},
+	"sa": {"South America", "南美洲", "南美洲", "南アメリカ"},
+}
+
+// ContinentOf 返回国家码所属的 2 位小写洲代码，如 ContinentOf("hk") == "as"，中美洲与加勒比地区属于 na；未知国家码返回空字符串
+func ContinentOf(code string) string {
+	return countryContinents[strings.ToLower(code)]
+}
+
+// ContinentName 返回洲代码对应的本地化名称，lang 的写法同 CountryName；未知洲代码返回空字符串
+func ContinentName(code, lang string) string {
+	names, ok := continentNames[strings.ToLower(code)]
+	if !ok {
+		return ""
+	}
+	return names[langIndex(lang)]
+}
+
+// Continents 返回所有洲代码，按字母顺序排列
+func Continents() []string {
+	return slices.Clone(continentCodes)
+}
+
+// IsEU 判断国家码是否属于欧盟成员国
+func IsEU(code string) bool {
+	_, ok := euCountries[strings.ToLower(code)]
+	return ok
+}
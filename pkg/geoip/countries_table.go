@@ -0,0 +1,509 @@
+// Code generated by gen_countries.go; DO NOT EDIT.
+
+package geoip
+
+var countryNames = map[string][langCount]string{
+	"ad": {"Andorra", "安道尔", "安道爾", "アンドラ"},
+	"ae": {"United Arab Emirates", "阿拉伯联合酋长国", "阿拉伯聯合大公國", "アラブ首長国連邦"},
+	"af": {"Afghanistan", "阿富汗", "阿富汗", "アフガニスタン"},
+	"ag": {"Antigua & Barbuda", "安提瓜和巴布达", "安地卡及巴布達", "アンティグア・バーブーダ"},
+	"ai": {"Anguilla", "安圭拉", "安奎拉", "アンギラ"},
+	"al": {"Albania", "阿尔巴尼亚", "阿爾巴尼亞", "アルバニア"},
+	"am": {"Armenia", "亚美尼亚", "亞美尼亞", "アルメニア"},
+	"ao": {"Angola", "安哥拉", "安哥拉", "アンゴラ"},
+	"aq": {"Antarctica", "南极洲", "南極洲", "南極"},
+	"ar": {"Argentina", "阿根廷", "阿根廷", "アルゼンチン"},
+	"as": {"American Samoa", "美属萨摩亚", "美屬薩摩亞", "米領サモア"},
+	"at": {"Austria", "奥地利", "奧地利", "オーストリア"},
+	"au": {"Australia", "澳大利亚", "澳洲", "オーストラリア"},
+	"aw": {"Aruba", "阿鲁巴", "荷屬阿魯巴", "アルバ"},
+	"ax": {"Åland Islands", "奥兰群岛", "奧蘭群島", "オーランド諸島"},
+	"az": {"Azerbaijan", "阿塞拜疆", "亞塞拜然", "アゼルバイジャン"},
+	"ba": {"Bosnia & Herzegovina", "波斯尼亚和黑塞哥维那", "波士尼亞與赫塞哥維納", "ボスニア・ヘルツェゴビナ"},
+	"bb": {"Barbados", "巴巴多斯", "巴貝多", "バルバドス"},
+	"bd": {"Bangladesh", "孟加拉国", "孟加拉", "バングラデシュ"},
+	"be": {"Belgium", "比利时", "比利時", "ベルギー"},
+	"bf": {"Burkina Faso", "布基纳法索", "布吉納法索", "ブルキナファソ"},
+	"bg": {"Bulgaria", "保加利亚", "保加利亞", "ブルガリア"},
+	"bh": {"Bahrain", "巴林", "巴林", "バーレーン"},
+	"bi": {"Burundi", "布隆迪", "蒲隆地", "ブルンジ"},
+	"bj": {"Benin", "贝宁", "貝南", "ベナン"},
+	"bl": {"St. Barthélemy", "圣巴泰勒米", "聖巴瑟米", "サン・バルテルミー"},
+	"bm": {"Bermuda", "百慕大", "百慕達", "バミューダ"},
+	"bn": {"Brunei", "文莱", "汶萊", "ブルネイ"},
+	"bo": {"Bolivia", "玻利维亚", "玻利維亞", "ボリビア"},
+	"bq": {"Caribbean Netherlands", "荷属加勒比区", "荷蘭加勒比區", "オランダ領カリブ"},
+	"br": {"Brazil", "巴西", "巴西", "ブラジル"},
+	"bs": {"Bahamas", "巴哈马", "巴哈馬", "バハマ"},
+	"bt": {"Bhutan", "不丹", "不丹", "ブータン"},
+	"bv": {"Bouvet Island", "布韦岛", "布威島", "ブーベ島"},
+	"bw": {"Botswana", "博茨瓦纳", "波札那", "ボツワナ"},
+	"by": {"Belarus", "白俄罗斯", "白俄羅斯", "ベラルーシ"},
+	"bz": {"Belize", "伯利兹", "貝里斯", "ベリーズ"},
+	"ca": {"Canada", "加拿大", "加拿大", "カナダ"},
+	"cc": {"Cocos (Keeling) Islands", "科科斯（基林）群岛", "科克斯（基靈）群島", "ココス(キーリング)諸島"},
+	"cd": {"Congo - Kinshasa", "刚果（金）", "剛果（金夏沙）", "コンゴ民主共和国(キンシャサ)"},
+	"cf": {"Central African Republic", "中非共和国", "中非共和國", "中央アフリカ共和国"},
+	"cg": {"Congo - Brazzaville", "刚果（布）", "剛果（布拉薩）", "コンゴ共和国(ブラザビル)"},
+	"ch": {"Switzerland", "瑞士", "瑞士", "スイス"},
+	"ci": {"Côte d’Ivoire", "科特迪瓦", "象牙海岸", "コートジボワール"},
+	"ck": {"Cook Islands", "库克群岛", "庫克群島", "クック諸島"},
+	"cl": {"Chile", "智利", "智利", "チリ"},
+	"cm": {"Cameroon", "喀麦隆", "喀麥隆", "カメルーン"},
+	"cn": {"China", "中国", "中國", "中国"},
+	"co": {"Colombia", "哥伦比亚", "哥倫比亞", "コロンビア"},
+	"cr": {"Costa Rica", "哥斯达黎加", "哥斯大黎加", "コスタリカ"},
+	"cu": {"Cuba", "古巴", "古巴", "キューバ"},
+	"cv": {"Cape Verde", "佛得角", "維德角", "カーボベルデ"},
+	"cw": {"Curaçao", "库拉索", "庫拉索", "キュラソー"},
+	"cx": {"Christmas Island", "圣诞岛", "聖誕島", "クリスマス島"},
+	"cy": {"Cyprus", "塞浦路斯", "賽普勒斯", "キプロス"},
+	"cz": {"Czechia", "捷克", "捷克", "チェコ"},
+	"de": {"Germany", "德国", "德國", "ドイツ"},
+	"dj": {"Djibouti", "吉布提", "吉布地", "ジブチ"},
+	"dk": {"Denmark", "丹麦", "丹麥", "デンマーク"},
+	"dm": {"Dominica", "多米尼克", "多米尼克", "ドミニカ国"},
+	"do": {"Dominican Republic", "多米尼加共和国", "多明尼加共和國", "ドミニカ共和国"},
+	"dz": {"Algeria", "阿尔及利亚", "阿爾及利亞", "アルジェリア"},
+	"ec": {"Ecuador", "厄瓜多尔", "厄瓜多", "エクアドル"},
+	"ee": {"Estonia", "爱沙尼亚", "愛沙尼亞", "エストニア"},
+	"eg": {"Egypt", "埃及", "埃及", "エジプト"},
+	"eh": {"Western Sahara", "西撒哈拉", "西撒哈拉", "西サハラ"},
+	"er": {"Eritrea", "厄立特里亚", "厄利垂亞", "エリトリア"},
+	"es": {"Spain", "西班牙", "西班牙", "スペイン"},
+	"et": {"Ethiopia", "埃塞俄比亚", "衣索比亞", "エチオピア"},
+	"fi": {"Finland", "芬兰", "芬蘭", "フィンランド"},
+	"fj": {"Fiji", "斐济", "斐濟", "フィジー"},
+	"fk": {"Falkland Islands", "福克兰群岛", "福克蘭群島", "フォークランド諸島"},
+	"fm": {"Micronesia", "密克罗尼西亚", "密克羅尼西亞", "ミクロネシア連邦"},
+	"fo": {"Faroe Islands", "法罗群岛", "法羅群島", "フェロー諸島"},
+	"fr": {"France", "法国", "法國", "フランス"},
+	"ga": {"Gabon", "加蓬", "加彭", "ガボン"},
+	"gb": {"United Kingdom", "英国", "英國", "イギリス"},
+	"gd": {"Grenada", "格林纳达", "格瑞那達", "グレナダ"},
+	"ge": {"Georgia", "格鲁吉亚", "喬治亞", "ジョージア"},
+	"gf": {"French Guiana", "法属圭亚那", "法屬圭亞那", "仏領ギアナ"},
+	"gg": {"Guernsey", "根西岛", "根息", "ガーンジー"},
+	"gh": {"Ghana", "加纳", "迦納", "ガーナ"},
+	"gi": {"Gibraltar", "直布罗陀", "直布羅陀", "ジブラルタル"},
+	"gl": {"Greenland", "格陵兰", "格陵蘭", "グリーンランド"},
+	"gm": {"Gambia", "冈比亚", "甘比亞", "ガンビア"},
+	"gn": {"Guinea", "几内亚", "幾內亞", "ギニア"},
+	"gp": {"Guadeloupe", "瓜德罗普", "瓜地洛普", "グアドループ"},
+	"gq": {"Equatorial Guinea", "赤道几内亚", "赤道幾內亞", "赤道ギニア"},
+	"gr": {"Greece", "希腊", "希臘", "ギリシャ"},
+	"gs": {"South Georgia & South Sandwich Islands", "南乔治亚和南桑威奇群岛", "南喬治亞與南三明治群島", "サウスジョージア・サウスサンドウィッチ諸島"},
+	"gt": {"Guatemala", "危地马拉", "瓜地馬拉", "グアテマラ"},
+	"gu": {"Guam", "关岛", "關島", "グアム"},
+	"gw": {"Guinea-Bissau", "几内亚比绍", "幾內亞比索", "ギニアビサウ"},
+	"gy": {"Guyana", "圭亚那", "蓋亞那", "ガイアナ"},
+	"hk": {"Hong Kong", "香港", "香港", "香港"},
+	"hm": {"Heard & McDonald Islands", "赫德岛和麦克唐纳群岛", "赫德島及麥唐納群島", "ハード島・マクドナルド諸島"},
+	"hn": {"Honduras", "洪都拉斯", "宏都拉斯", "ホンジュラス"},
+	"hr": {"Croatia", "克罗地亚", "克羅埃西亞", "クロアチア"},
+	"ht": {"Haiti", "海地", "海地", "ハイチ"},
+	"hu": {"Hungary", "匈牙利", "匈牙利", "ハンガリー"},
+	"id": {"Indonesia", "印度尼西亚", "印尼", "インドネシア"},
+	"ie": {"Ireland", "爱尔兰", "愛爾蘭", "アイルランド"},
+	"il": {"Israel", "以色列", "以色列", "イスラエル"},
+	"im": {"Isle of Man", "马恩岛", "曼島", "マン島"},
+	"in": {"India", "印度", "印度", "インド"},
+	"io": {"British Indian Ocean Territory", "英属印度洋领地", "英屬印度洋領地", "英領インド洋地域"},
+	"iq": {"Iraq", "伊拉克", "伊拉克", "イラク"},
+	"ir": {"Iran", "伊朗", "伊朗", "イラン"},
+	"is": {"Iceland", "冰岛", "冰島", "アイスランド"},
+	"it": {"Italy", "意大利", "義大利", "イタリア"},
+	"je": {"Jersey", "泽西岛", "澤西島", "ジャージー"},
+	"jm": {"Jamaica", "牙买加", "牙買加", "ジャマイカ"},
+	"jo": {"Jordan", "约旦", "約旦", "ヨルダン"},
+	"jp": {"Japan", "日本", "日本", "日本"},
+	"ke": {"Kenya", "肯尼亚", "肯亞", "ケニア"},
+	"kg": {"Kyrgyzstan", "吉尔吉斯斯坦", "吉爾吉斯", "キルギス"},
+	"kh": {"Cambodia", "柬埔寨", "柬埔寨", "カンボジア"},
+	"ki": {"Kiribati", "基里巴斯", "吉里巴斯", "キリバス"},
+	"km": {"Comoros", "科摩罗", "葛摩", "コモロ"},
+	"kn": {"St. Kitts & Nevis", "圣基茨和尼维斯", "聖克里斯多福及尼維斯", "セントクリストファー・ネーヴィス"},
+	"kp": {"North Korea", "朝鲜", "北韓", "北朝鮮"},
+	"kr": {"South Korea", "韩国", "南韓", "韓国"},
+	"kw": {"Kuwait", "科威特", "科威特", "クウェート"},
+	"ky": {"Cayman Islands", "开曼群岛", "開曼群島", "ケイマン諸島"},
+	"kz": {"Kazakhstan", "哈萨克斯坦", "哈薩克", "カザフスタン"},
+	"la": {"Laos", "老挝", "寮國", "ラオス"},
+	"lb": {"Lebanon", "黎巴嫩", "黎巴嫩", "レバノン"},
+	"lc": {"St. Lucia", "圣卢西亚", "聖露西亞", "セントルシア"},
+	"li": {"Liechtenstein", "列支敦士登", "列支敦斯登", "リヒテンシュタイン"},
+	"lk": {"Sri Lanka", "斯里兰卡", "斯里蘭卡", "スリランカ"},
+	"lr": {"Liberia", "利比里亚", "賴比瑞亞", "リベリア"},
+	"ls": {"Lesotho", "莱索托", "賴索托", "レソト"},
+	"lt": {"Lithuania", "立陶宛", "立陶宛", "リトアニア"},
+	"lu": {"Luxembourg", "卢森堡", "盧森堡", "ルクセンブルク"},
+	"lv": {"Latvia", "拉脱维亚", "拉脫維亞", "ラトビア"},
+	"ly": {"Libya", "利比亚", "利比亞", "リビア"},
+	"ma": {"Morocco", "摩洛哥", "摩洛哥", "モロッコ"},
+	"mc": {"Monaco", "摩纳哥", "摩納哥", "モナコ"},
+	"md": {"Moldova", "摩尔多瓦", "摩爾多瓦", "モルドバ"},
+	"me": {"Montenegro", "黑山", "蒙特內哥羅", "モンテネグロ"},
+	"mf": {"St. Martin", "法属圣马丁", "法屬聖馬丁", "サン・マルタン"},
+	"mg": {"Madagascar", "马达加斯加", "馬達加斯加", "マダガスカル"},
+	"mh": {"Marshall Islands", "马绍尔群岛", "馬紹爾群島", "マーシャル諸島"},
+	"mk": {"Macedonia", "马其顿", "馬其頓", "マケドニア"},
+	"ml": {"Mali", "马里", "馬利", "マリ"},
+	"mm": {"Myanmar (Burma)", "缅甸", "緬甸", "ミャンマー (ビルマ)"},
+	"mn": {"Mongolia", "蒙古", "蒙古", "モンゴル"},
+	"mo": {"Macao", "澳门", "澳門", "マカオ"},
+	"mp": {"Northern Mariana Islands", "北马里亚纳群岛", "北馬利安納群島", "北マリアナ諸島"},
+	"mq": {"Martinique", "马提尼克", "馬丁尼克", "マルティニーク"},
+	"mr": {"Mauritania", "毛里塔尼亚", "茅利塔尼亞", "モーリタニア"},
+	"ms": {"Montserrat", "蒙特塞拉特", "蒙哲臘", "モントセラト"},
+	"mt": {"Malta", "马耳他", "馬爾他", "マルタ"},
+	"mu": {"Mauritius", "毛里求斯", "模里西斯", "モーリシャス"},
+	"mv": {"Maldives", "马尔代夫", "馬爾地夫", "モルディブ"},
+	"mw": {"Malawi", "马拉维", "馬拉威", "マラウイ"},
+	"mx": {"Mexico", "墨西哥", "墨西哥", "メキシコ"},
+	"my": {"Malaysia", "马来西亚", "馬來西亞", "マレーシア"},
+	"mz": {"Mozambique", "莫桑比克", "莫三比克", "モザンビーク"},
+	"na": {"Namibia", "纳米比亚", "納米比亞", "ナミビア"},
+	"nc": {"New Caledonia", "新喀里多尼亚", "新喀里多尼亞", "ニューカレドニア"},
+	"ne": {"Niger", "尼日尔", "尼日", "ニジェール"},
+	"nf": {"Norfolk Island", "诺福克岛", "諾福克島", "ノーフォーク島"},
+	"ng": {"Nigeria", "尼日利亚", "奈及利亞", "ナイジェリア"},
+	"ni": {"Nicaragua", "尼加拉瓜", "尼加拉瓜", "ニカラグア"},
+	"nl": {"Netherlands", "荷兰", "荷蘭", "オランダ"},
+	"no": {"Norway", "挪威", "挪威", "ノルウェー"},
+	"np": {"Nepal", "尼泊尔", "尼泊爾", "ネパール"},
+	"nr": {"Nauru", "瑙鲁", "諾魯", "ナウル"},
+	"nu": {"Niue", "纽埃", "紐埃島", "ニウエ"},
+	"nz": {"New Zealand", "新西兰", "紐西蘭", "ニュージーランド"},
+	"om": {"Oman", "阿曼", "阿曼", "オマーン"},
+	"pa": {"Panama", "巴拿马", "巴拿馬", "パナマ"},
+	"pe": {"Peru", "秘鲁", "秘魯", "ペルー"},
+	"pf": {"French Polynesia", "法属波利尼西亚", "法屬玻里尼西亞", "仏領ポリネシア"},
+	"pg": {"Papua New Guinea", "巴布亚新几内亚", "巴布亞紐幾內亞", "パプアニューギニア"},
+	"ph": {"Philippines", "菲律宾", "菲律賓", "フィリピン"},
+	"pk": {"Pakistan", "巴基斯坦", "巴基斯坦", "パキスタン"},
+	"pl": {"Poland", "波兰", "波蘭", "ポーランド"},
+	"pm": {"St. Pierre & Miquelon", "圣皮埃尔和密克隆群岛", "聖皮埃與密克隆群島", "サンピエール島・ミクロン島"},
+	"pn": {"Pitcairn Islands", "皮特凯恩群岛", "皮特肯群島", "ピトケアン諸島"},
+	"pr": {"Puerto Rico", "波多黎各", "波多黎各", "プエルトリコ"},
+	"ps": {"Palestine", "巴勒斯坦", "巴勒斯坦", "パレスチナ"},
+	"pt": {"Portugal", "葡萄牙", "葡萄牙", "ポルトガル"},
+	"pw": {"Palau", "帕劳", "帛琉", "パラオ"},
+	"py": {"Paraguay", "巴拉圭", "巴拉圭", "パラグアイ"},
+	"qa": {"Qatar", "卡塔尔", "卡達", "カタール"},
+	"re": {"Réunion", "留尼汪", "留尼旺", "レユニオン"},
+	"ro": {"Romania", "罗马尼亚", "羅馬尼亞", "ルーマニア"},
+	"rs": {"Serbia", "塞尔维亚", "塞爾維亞", "セルビア"},
+	"ru": {"Russia", "俄罗斯", "俄羅斯", "ロシア"},
+	"rw": {"Rwanda", "卢旺达", "盧安達", "ルワンダ"},
+	"sa": {"Saudi Arabia", "沙特阿拉伯", "沙烏地阿拉伯", "サウジアラビア"},
+	"sb": {"Solomon Islands", "所罗门群岛", "索羅門群島", "ソロモン諸島"},
+	"sc": {"Seychelles", "塞舌尔", "塞席爾", "セーシェル"},
+	"sd": {"Sudan", "苏丹", "蘇丹", "スーダン"},
+	"se": {"Sweden", "瑞典", "瑞典", "スウェーデン"},
+	"sg": {"Singapore", "新加坡", "新加坡", "シンガポール"},
+	"sh": {"St. Helena", "圣赫勒拿", "聖赫勒拿島", "セントヘレナ"},
+	"si": {"Slovenia", "斯洛文尼亚", "斯洛維尼亞", "スロベニア"},
+	"sj": {"Svalbard & Jan Mayen", "斯瓦尔巴和扬马延", "挪威屬斯瓦巴及尖棉", "スバールバル諸島・ヤンマイエン島"},
+	"sk": {"Slovakia", "斯洛伐克", "斯洛伐克", "スロバキア"},
+	"sl": {"Sierra Leone", "塞拉利昂", "獅子山", "シエラレオネ"},
+	"sm": {"San Marino", "圣马力诺", "聖馬利諾", "サンマリノ"},
+	"sn": {"Senegal", "塞内加尔", "塞內加爾", "セネガル"},
+	"so": {"Somalia", "索马里", "索馬利亞", "ソマリア"},
+	"sr": {"Suriname", "苏里南", "蘇利南", "スリナム"},
+	"ss": {"South Sudan", "南苏丹", "南蘇丹", "南スーダン"},
+	"st": {"São Tomé & Príncipe", "圣多美和普林西比", "聖多美普林西比", "サントメ・プリンシペ"},
+	"sv": {"El Salvador", "萨尔瓦多", "薩爾瓦多", "エルサルバドル"},
+	"sx": {"Sint Maarten", "荷属圣马丁", "荷屬聖馬丁", "シント・マールテン"},
+	"sy": {"Syria", "叙利亚", "敘利亞", "シリア"},
+	"sz": {"Swaziland", "斯威士兰", "史瓦濟蘭", "スワジランド"},
+	"tc": {"Turks & Caicos Islands", "特克斯和凯科斯群岛", "土克斯及開科斯群島", "タークス・カイコス諸島"},
+	"td": {"Chad", "乍得", "查德", "チャド"},
+	"tf": {"French Southern Territories", "法属南部领地", "法屬南部屬地", "仏領極南諸島"},
+	"tg": {"Togo", "多哥", "多哥", "トーゴ"},
+	"th": {"Thailand", "泰国", "泰國", "タイ"},
+	"tj": {"Tajikistan", "塔吉克斯坦", "塔吉克", "タジキスタン"},
+	"tk": {"Tokelau", "托克劳", "托克勞群島", "トケラウ"},
+	"tl": {"Timor-Leste", "东帝汶", "東帝汶", "東ティモール"},
+	"tm": {"Turkmenistan", "土库曼斯坦", "土庫曼", "トルクメニスタン"},
+	"tn": {"Tunisia", "突尼斯", "突尼西亞", "チュニジア"},
+	"to": {"Tonga", "汤加", "東加", "トンガ"},
+	"tr": {"Turkey", "土耳其", "土耳其", "トルコ"},
+	"tt": {"Trinidad & Tobago", "特立尼达和多巴哥", "千里達及托巴哥", "トリニダード・トバゴ"},
+	"tv": {"Tuvalu", "图瓦卢", "吐瓦魯", "ツバル"},
+	"tw": {"Taiwan", "台湾", "台灣", "台湾"},
+	"tz": {"Tanzania", "坦桑尼亚", "坦尚尼亞", "タンザニア"},
+	"ua": {"Ukraine", "乌克兰", "烏克蘭", "ウクライナ"},
+	"ug": {"Uganda", "乌干达", "烏干達", "ウガンダ"},
+	"um": {"U.S. Outlying Islands", "美国本土外小岛屿", "美國本土外小島嶼", "合衆国領有小離島"},
+	"us": {"United States", "美国", "美國", "アメリカ合衆国"},
+	"uy": {"Uruguay", "乌拉圭", "烏拉圭", "ウルグアイ"},
+	"uz": {"Uzbekistan", "乌兹别克斯坦", "烏茲別克", "ウズベキスタン"},
+	"va": {"Vatican City", "梵蒂冈", "梵蒂岡", "バチカン市国"},
+	"vc": {"St. Vincent & Grenadines", "圣文森特和格林纳丁斯", "聖文森及格瑞那丁", "セントビンセント及びグレナディーン諸島"},
+	"ve": {"Venezuela", "委内瑞拉", "委內瑞拉", "ベネズエラ"},
+	"vg": {"British Virgin Islands", "英属维尔京群岛", "英屬維京群島", "英領ヴァージン諸島"},
+	"vi": {"U.S. Virgin Islands", "美属维尔京群岛", "美屬維京群島", "米領ヴァージン諸島"},
+	"vn": {"Vietnam", "越南", "越南", "ベトナム"},
+	"vu": {"Vanuatu", "瓦努阿图", "萬那杜", "バヌアツ"},
+	"wf": {"Wallis & Futuna", "瓦利斯和富图纳", "瓦利斯群島和富圖那群島", "ウォリス・フツナ"},
+	"ws": {"Samoa", "萨摩亚", "薩摩亞", "サモア"},
+	"xk": {"Kosovo", "科索沃", "科索沃", "コソボ"},
+	"ye": {"Yemen", "也门", "葉門", "イエメン"},
+	"yt": {"Mayotte", "马约特", "馬約特島", "マヨット"},
+	"za": {"South Africa", "南非", "南非", "南アフリカ"},
+	"zm": {"Zambia", "赞比亚", "尚比亞", "ザンビア"},
+	"zw": {"Zimbabwe", "津巴布韦", "辛巴威", "ジンバブエ"},
+}
+
+var countryContinents = map[string]string{
+	"ad": "eu",
+	"ae": "as",
+	"af": "as",
+	"ag": "na",
+	"ai": "na",
+	"al": "eu",
+	"am": "as",
+	"ao": "af",
+	"aq": "an",
+	"ar": "sa",
+	"as": "oc",
+	"at": "eu",
+	"au": "oc",
+	"aw": "na",
+	"ax": "eu",
+	"az": "as",
+	"ba": "eu",
+	"bb": "na",
+	"bd": "as",
+	"be": "eu",
+	"bf": "af",
+	"bg": "eu",
+	"bh": "as",
+	"bi": "af",
+	"bj": "af",
+	"bl": "na",
+	"bm": "na",
+	"bn": "as",
+	"bo": "sa",
+	"bq": "na",
+	"br": "sa",
+	"bs": "na",
+	"bt": "as",
+	"bv": "an",
+	"bw": "af",
+	"by": "eu",
+	"bz": "na",
+	"ca": "na",
+	"cc": "oc",
+	"cd": "af",
+	"cf": "af",
+	"cg": "af",
+	"ch": "eu",
+	"ci": "af",
+	"ck": "oc",
+	"cl": "sa",
+	"cm": "af",
+	"cn": "as",
+	"co": "sa",
+	"cr": "na",
+	"cu": "na",
+	"cv": "af",
+	"cw": "na",
+	"cx": "oc",
+	"cy": "eu",
+	"cz": "eu",
+	"de": "eu",
+	"dj": "af",
+	"dk": "eu",
+	"dm": "na",
+	"do": "na",
+	"dz": "af",
+	"ec": "sa",
+	"ee": "eu",
+	"eg": "af",
+	"eh": "af",
+	"er": "af",
+	"es": "eu",
+	"et": "af",
+	"fi": "eu",
+	"fj": "oc",
+	"fk": "sa",
+	"fm": "oc",
+	"fo": "eu",
+	"fr": "eu",
+	"ga": "af",
+	"gb": "eu",
+	"gd": "na",
+	"ge": "as",
+	"gf": "sa",
+	"gg": "eu",
+	"gh": "af",
+	"gi": "eu",
+	"gl": "na",
+	"gm": "af",
+	"gn": "af",
+	"gp": "na",
+	"gq": "af",
+	"gr": "eu",
+	"gs": "an",
+	"gt": "na",
+	"gu": "oc",
+	"gw": "af",
+	"gy": "sa",
+	"hk": "as",
+	"hm": "an",
+	"hn": "na",
+	"hr": "eu",
+	"ht": "na",
+	"hu": "eu",
+	"id": "as",
+	"ie": "eu",
+	"il": "as",
+	"im": "eu",
+	"in": "as",
+	"io": "oc",
+	"iq": "as",
+	"ir": "as",
+	"is": "eu",
+	"it": "eu",
+	"je": "eu",
+	"jm": "na",
+	"jo": "as",
+	"jp": "as",
+	"ke": "af",
+	"kg": "as",
+	"kh": "as",
+	"ki": "oc",
+	"km": "af",
+	"kn": "na",
+	"kp": "as",
+	"kr": "as",
+	"kw": "as",
+	"ky": "na",
+	"kz": "as",
+	"la": "as",
+	"lb": "as",
+	"lc": "na",
+	"li": "eu",
+	"lk": "as",
+	"lr": "af",
+	"ls": "af",
+	"lt": "eu",
+	"lu": "eu",
+	"lv": "eu",
+	"ly": "af",
+	"ma": "af",
+	"mc": "eu",
+	"md": "eu",
+	"me": "eu",
+	"mf": "na",
+	"mg": "af",
+	"mh": "oc",
+	"mk": "eu",
+	"ml": "af",
+	"mm": "as",
+	"mn": "as",
+	"mo": "as",
+	"mp": "oc",
+	"mq": "na",
+	"mr": "af",
+	"ms": "na",
+	"mt": "eu",
+	"mu": "af",
+	"mv": "as",
+	"mw": "af",
+	"mx": "na",
+	"my": "as",
+	"mz": "af",
+	"na": "af",
+	"nc": "oc",
+	"ne": "af",
+	"nf": "oc",
+	"ng": "af",
+	"ni": "na",
+	"nl": "eu",
+	"no": "eu",
+	"np": "as",
+	"nr": "oc",
+	"nu": "oc",
+	"nz": "oc",
+	"om": "as",
+	"pa": "na",
+	"pe": "sa",
+	"pf": "oc",
+	"pg": "oc",
+	"ph": "as",
+	"pk": "as",
+	"pl": "eu",
+	"pm": "na",
+	"pn": "oc",
+	"pr": "na",
+	"ps": "as",
+	"pt": "eu",
+	"pw": "oc",
+	"py": "sa",
+	"qa": "as",
+	"re": "af",
+	"ro": "eu",
+	"rs": "eu",
+	"ru": "eu",
+	"rw": "af",
+	"sa": "as",
+	"sb": "oc",
+	"sc": "af",
+	"sd": "af",
+	"se": "eu",
+	"sg": "as",
+	"sh": "af",
+	"si": "eu",
+	"sj": "eu",
+	"sk": "eu",
+	"sl": "af",
+	"sm": "eu",
+	"sn": "af",
+	"so": "af",
+	"sr": "sa",
+	"ss": "af",
+	"st": "af",
+	"sv": "na",
+	"sx": "na",
+	"sy": "as",
+	"sz": "af",
+	"tc": "na",
+	"td": "af",
+	"tf": "an",
+	"tg": "af",
+	"th": "as",
+	"tj": "as",
+	"tk": "oc",
+	"tl": "as",
+	"tm": "as",
+	"tn": "af",
+	"to": "oc",
+	"tr": "as",
+	"tt": "na",
+	"tv": "oc",
+	"tw": "as",
+	"tz": "af",
+	"ua": "eu",
+	"ug": "af",
+	"um": "oc",
+	"us": "na",
+	"uy": "sa",
+	"uz": "as",
+	"va": "eu",
+	"vc": "na",
+	"ve": "sa",
+	"vg": "na",
+	"vi": "na",
+	"vn": "as",
+	"vu": "oc",
+	"wf": "oc",
+	"ws": "oc",
+	"xk": "eu",
+	"ye": "as",
+	"yt": "af",
+	"za": "af",
+	"zm": "af",
+	"zw": "af",
+}
@@ -0,0 +1,86 @@
+package geoip
+
+import "testing"
+
+func TestCountryName(t *testing.T) {
+	cases := []struct {
+		code, lang, name string
+	}{
+		{"hk", "zh-CN", "香港"},
+		{"HK", "en_US", "Hong Kong"},
+		{"us", "zh_CN", "美国"},
+		{"us", "zh-TW", "美國"},
+		{"us", "zh-Hant-HK", "美國"},
+		{"jp", "ja", "日本"},
+		{"de", "fr_FR", "Germany"},
+		{"zz", "en", ""},
+	}
+
+	for _, c := range cases {
+		if name := CountryName(c.code, c.lang); name != c.name {
+			t.Fatalf("CountryName(%q, %q) = %q, expected %q", c.code, c.lang, name, c.name)
+		}
+	}
+}
+
+func TestFlagEmoji(t *testing.T) {
+	cases := map[string]string{
+		"hk":  "🇭🇰",
+		"US":  "🇺🇸",
+		"cn":  "🇨🇳",
+		"u":   "",
+		"usa": "",
+		"1a":  "",
+	}
+
+	for code, flag := range cases {
+		if f := FlagEmoji(code); f != flag {
+			t.Fatalf("FlagEmoji(%q) = %q, expected %q", code, f, flag)
+		}
+	}
+}
+
+func TestIsEU(t *testing.T) {
+	cases := map[string]bool{
+		"de": true,
+		"FR": true,
+		"ie": true,
+		"gb": false,
+		"ch": false,
+		"no": false,
+		"":   false,
+	}
+	for code, expected := range cases {
+		if got := IsEU(code); got != expected {
+			t.Fatalf("IsEU(%q) = %v, expected %v", code, got, expected)
+		}
+	}
+}
+
+func TestContinentOf(t *testing.T) {
+	cases := map[string]string{
+		"hk": "as",
+		"DE": "eu",
+		"mx": "na",
+		"pa": "na",
+		"br": "sa",
+		"au": "oc",
+		"eg": "af",
+		"aq": "an",
+		"cy": "eu",
+		"zz": "",
+	}
+	for code, expected := range cases {
+		if got := ContinentOf(code); got != expected {
+			t.Fatalf("ContinentOf(%q) = %q, expected %q", code, got, expected)
+		}
+	}
+	for code := range countryNames {
+		if ContinentOf(code) == "" {
+			t.Fatalf("no continent for %q", code)
+		}
+	}
+	if name := ContinentName("AS", "zh-CN"); name != "亚洲" {
+		t.Fatalf("ContinentName(as, zh-CN) = %q", name)
+	}
+}
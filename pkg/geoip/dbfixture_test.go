@@ -0,0 +1,83 @@
+package geoip_test
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+// 需要真实 mmdb 的测试共用的数据库与 Resolver
+
+// writeCountryDB 将 cidr → 国家码 写成与 ipinfo_lite.mmdb 字段相同的数据库，并原子替换 path
+// 数据库带有描述，能通过下载后的完整校验
+func writeCountryDB(tb testing.TB, path string, countries map[string]string) {
+	tb.Helper()
+	entries := make([]builder.Entry, 0, len(countries))
+	for cidr, country := range countries {
+		entries = append(entries, builder.Entry{Network: netip.MustParsePrefix(cidr), Country: country})
+	}
+	if err := builder.BuildFile(path, builder.Options{Description: "test"}, entries, nil); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// writeRecordDB 将 cidr → 记录 写成 mmdb 并原子替换 path，用于 ASN 库等字段不同的数据库
+func writeRecordDB(tb testing.TB, path, databaseType string, records map[string]builder.Record) {
+	tb.Helper()
+	cidrs := make([]string, 0, len(records))
+	for cidr := range records {
+		cidrs = append(cidrs, cidr)
+	}
+	// 大网段先写入，小网段覆盖其中重叠的部分
+	slices.SortFunc(cidrs, func(a, b string) int {
+		return netip.MustParsePrefix(a).Bits() - netip.MustParsePrefix(b).Bits()
+	})
+
+	w := builder.NewWriter(databaseType)
+	w.Description = "test"
+	for _, cidr := range cidrs {
+		if err := w.Insert(netip.MustParsePrefix(cidr), records[cidr]); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := w.WriteTo(f); err != nil {
+		tb.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// asnRecord 返回与 GeoLite2-ASN 字段相同的记录
+func asnRecord(number uint32, org string) builder.Record {
+	return builder.Record{"autonomous_system_number": number, "autonomous_system_organization": org}
+}
+
+// newDBResolver 创建只查询 path 的离线 Resolver，不读取本机的 ASN 库与覆盖表，opts 可以覆盖这些默认值
+func newDBResolver(tb testing.TB, path string, opts ...geoip.Option) *geoip.Resolver {
+	tb.Helper()
+	opts = append([]geoip.Option{
+		geoip.WithDBPaths(path),
+		geoip.WithASNDBPaths(filepath.Join(filepath.Dir(path), "missing-asn.mmdb")),
+		geoip.WithOverridePaths(),
+		geoip.WithChain(geoip.ProviderMMDB),
+		geoip.WithOffline(true),
+		geoip.WithWatch(false),
+	}, opts...)
+	r := geoip.New(opts...)
+	tb.Cleanup(func() { r.Close() })
+	return r
+}
@@ -0,0 +1,203 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+// DBDiff 是两个 mmdb 之间国家数据的差异，以网段为单位统计
+type DBDiff struct {
+	Old *DBMetadata `json:"old"`
+	New *DBMetadata `json:"new"`
+
+	Networks int `json:"networks"` // 比较过的网段数
+	Changed  int `json:"changed"`  // 国家发生变化的网段数
+	Added    int `json:"added"`    // 旧库中没有数据的网段数
+	Removed  int `json:"removed"`  // 新库中没有数据的网段数
+
+	Transitions map[string]int `json:"transitions"` // 按 "旧国家>新国家" 统计变化的网段数
+	Samples     []DBDiffSample `json:"samples,omitempty"`
+}
+
+// DBDiffSample 是一个发生变化的网段，Old 或 New 为空表示对应的库中没有数据
+type DBDiffSample struct {
+	Network string `json:"network"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// openDBFile 打开 mmdb 文件，path 为空时使用内置数据库
+func openDBFile(path string) (*maxminddb.Reader, *DBMetadata, error) {
+	if path == "" {
+		data, err := loadEmbeddedDB()
+		if err != nil {
+			return nil, nil, err
+		}
+		reader, err := maxminddb.FromBytes(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return reader, readerMetadata(reader, SourceEmbeddedDB, ""), nil
+	}
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, readerMetadata(reader, SourceExternalDB, path), nil
+}
+
+// countryOnly 只解码国家字段，比较整个数据库时比解码完整记录快得多
+type countryOnly struct {
+	// ipinfo_lite.mmdb 与内置 geoip.db
+	CountryCode string `maxminddb:"country_code"`
+	Country     any    `maxminddb:"country"`
+}
+
+type maxmindCountryOnly struct {
+	Country           maxmindCountry `maxminddb:"country"`
+	RegisteredCountry maxmindCountry `maxminddb:"registered_country"`
+}
+
+// recordCountry 使用 decode 解码一条记录并返回小写的国家码
+func recordCountry(maxmind bool, decode func(any) error) (string, error) {
+	if maxmind {
+		var r maxmindCountryOnly
+		if err := decode(&r); err != nil {
+			return "", err
+		}
+		if r.Country.ISOCode == "" {
+			return strings.ToLower(r.RegisteredCountry.ISOCode), nil
+		}
+		return strings.ToLower(r.Country.ISOCode), nil
+	}
+	var r countryOnly
+	if err := decode(&r); err != nil {
+		return "", err
+	}
+	if r.CountryCode != "" {
+		return strings.ToLower(r.CountryCode), nil
+	}
+	if code, ok := r.Country.(string); ok && len(code) == 2 {
+		return strings.ToLower(code), nil
+	}
+	return "", nil
+}
+
+// lookupCountry 查询 ip 所在的网段与国家，没有数据时 country 为空
+func lookupCountry(db *maxminddb.Reader, ip net.IP) (network *net.IPNet, country string, err error) {
+	maxmind := isMaxMindFormat(db.Metadata.DatabaseType)
+	country, err = recordCountry(maxmind, func(v any) error {
+		var lookupErr error
+		network, _, lookupErr = db.LookupNetwork(ip, v)
+		return lookupErr
+	})
+	return network, country, err
+}
+
+// DiffDBFiles 比较两个 mmdb 中每个网段的国家，path 为空时使用内置数据库，最多返回 samples 个变化的网段
+// 两个库的网段划分不同时，按更小的网段比较
+func DiffDBFiles(oldPath, newPath string, samples int) (*DBDiff, error) {
+	oldDB, oldMeta, err := openDBFile(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: open %s: %w", oldPath, err)
+	}
+	defer oldDB.Close()
+	newDB, newMeta, err := openDBFile(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: open %s: %w", newPath, err)
+	}
+	defer newDB.Close()
+
+	diff := &DBDiff{Old: oldMeta, New: newMeta, Transitions: make(map[string]int)}
+	record := func(network *net.IPNet, oldCountry, newCountry string) {
+		diff.Networks++
+		if oldCountry == newCountry {
+			return
+		}
+		switch {
+		case oldCountry == "":
+			diff.Added++
+		case newCountry == "":
+			diff.Removed++
+		default:
+			diff.Changed++
+		}
+		diff.Transitions[oldCountry+">"+newCountry]++
+		if len(diff.Samples) < samples {
+			diff.Samples = append(diff.Samples, DBDiffSample{Network: network.String(), Old: oldCountry, New: newCountry})
+		}
+	}
+
+	// 以旧库的网段为准，新库划分得更细时逐个比较新库的子网段
+	oldMaxMind, newMaxMind := isMaxMindFormat(oldDB.Metadata.DatabaseType), isMaxMindFormat(newDB.Metadata.DatabaseType)
+	networks := oldDB.Networks(maxminddb.SkipAliasedNetworks)
+	for networks.Next() {
+		var oldNet *net.IPNet
+		oldCountry, err := recordCountry(oldMaxMind, func(v any) (err error) {
+			oldNet, err = networks.Network(v)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("geoip: read %s: %w", oldPath, err)
+		}
+		if oldCountry == "" {
+			continue
+		}
+
+		newNet, newCountry, err := lookupCountry(newDB, oldNet.IP)
+		if err != nil {
+			return nil, fmt.Errorf("geoip: read %s: %w", newPath, err)
+		}
+		oldBits, _ := oldNet.Mask.Size()
+		if newBits, _ := newNet.Mask.Size(); newBits <= oldBits {
+			record(oldNet, oldCountry, newCountry)
+			continue
+		}
+		subnets := newDB.NetworksWithin(oldNet, maxminddb.SkipAliasedNetworks)
+		for subnets.Next() {
+			var subnet *net.IPNet
+			subCountry, err := recordCountry(newMaxMind, func(v any) (err error) {
+				subnet, err = subnets.Network(v)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("geoip: read %s: %w", newPath, err)
+			}
+			record(subnet, oldCountry, subCountry)
+		}
+		if err := subnets.Err(); err != nil {
+			return nil, fmt.Errorf("geoip: read %s: %w", newPath, err)
+		}
+	}
+	if err := networks.Err(); err != nil {
+		return nil, fmt.Errorf("geoip: read %s: %w", oldPath, err)
+	}
+
+	// 旧库中没有数据的网段
+	networks = newDB.Networks(maxminddb.SkipAliasedNetworks)
+	for networks.Next() {
+		var newNet *net.IPNet
+		newCountry, err := recordCountry(newMaxMind, func(v any) (err error) {
+			newNet, err = networks.Network(v)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("geoip: read %s: %w", newPath, err)
+		}
+		if newCountry == "" {
+			continue
+		}
+		if _, oldCountry, err := lookupCountry(oldDB, newNet.IP); err != nil {
+			return nil, fmt.Errorf("geoip: read %s: %w", oldPath, err)
+		} else if oldCountry == "" {
+			record(newNet, "", newCountry)
+		}
+	}
+	if err := networks.Err(); err != nil {
+		return nil, fmt.Errorf("geoip: read %s: %w", newPath, err)
+	}
+	return diff, nil
+}
@@ -0,0 +1,57 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+)
+
+// earthRadiusKm 是地球的平均半径
+const earthRadiusKm = 6371.0088
+
+// Haversine 返回两个坐标之间的大圆距离，单位 km
+func Haversine(a, b Location) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Distance 返回两个 IP 定位坐标之间的距离，单位 km，用于估计 Agent 与监控目标的远近
+// 结果只是城市级坐标之间的估算，任何一个 IP 没有坐标时返回 ErrNoLocation，内网地址返回 ErrPrivateIP
+func (r *Resolver) Distance(a, b net.IP) (float64, error) {
+	return r.DistanceContext(context.Background(), a, b)
+}
+
+// DistanceContext 与 Distance 相同，但接受 ctx
+func (r *Resolver) DistanceContext(ctx context.Context, a, b net.IP) (float64, error) {
+	la, err := r.location(ctx, a)
+	if err != nil {
+		return 0, err
+	}
+	lb, err := r.location(ctx, b)
+	if err != nil {
+		return 0, err
+	}
+	return Haversine(*la, *lb), nil
+}
+
+func (r *Resolver) location(ctx context.Context, ip net.IP) (*Location, error) {
+	res, err := r.lookupDetail(ctx, ip)
+	switch {
+	case err != nil:
+		return nil, err
+	case res.Private:
+		return nil, fmt.Errorf("%w: %s", ErrPrivateIP, ip)
+	case res.Location == nil:
+		return nil, fmt.Errorf("%w: %s", ErrNoLocation, ip)
+	}
+	return res.Location, nil
+}
+
+// Distance 使用默认实例计算两个 IP 之间的距离，见 Resolver.Distance
+func Distance(a, b net.IP) (float64, error) {
+	return Default().Distance(a, b)
+}
@@ -0,0 +1,14 @@
+package geoip
+
+import "testing"
+
+func TestHaversine(t *testing.T) {
+	hk := Location{Latitude: 22.3193, Longitude: 114.1694}
+	tokyo := Location{Latitude: 35.6762, Longitude: 139.6503}
+	if d := Haversine(hk, tokyo); d < 2870 || d > 2900 {
+		t.Fatalf("Haversine(hk, tokyo) = %.0f km, expected about 2880", d)
+	}
+	if d := Haversine(hk, hk); d != 0 {
+		t.Fatalf("Haversine(hk, hk) = %f, expected 0", d)
+	}
+}
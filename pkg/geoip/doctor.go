@@ -0,0 +1,239 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+// CheckStatus 是自检中一项检查的结果
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn" // 可以工作，但配置可能不是预期的
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip" // 当前配置下不适用，如离线模式下的在线数据源
+)
+
+// Check 是自检中的一项检查
+type Check struct {
+	Name     string        `json:"name"`
+	Status   CheckStatus   `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// DiagnoseReport 是 Diagnose 的结果，按检查的顺序排列
+type DiagnoseReport struct {
+	Checks []Check `json:"checks"`
+	OK     bool    `json:"ok"` // 没有失败的检查
+}
+
+func (rep *DiagnoseReport) add(c Check) {
+	rep.Checks = append(rep.Checks, c)
+	if c.Status == CheckFail {
+		rep.OK = false
+	}
+}
+
+// DebugInfo 是排查 IP 定位问题时需要的面板内部状态
+type DebugInfo struct {
+	Database      *DBMetadata      `json:"database,omitempty"`
+	DatabaseError string           `json:"database_error,omitempty"`
+	Chain         []string         `json:"chain"`  // 当前的查询顺序
+	Health        []ProviderHealth `json:"health"` // 各数据源的健康与熔断状态
+	RateLimits    []RateLimitStats `json:"rate_limits,omitempty"`
+	Cache         CacheStats       `json:"cache"`
+
+	// 通过 ip 参数指定的测试查询，跳过缓存，与 Agent 上报时使用相同的查询链
+	IP          string  `json:"ip,omitempty"`
+	Lookup      *Result `json:"lookup,omitempty"`
+	LookupError string  `json:"lookup_error,omitempty"`
+}
+
+// Diagnose 检查当前配置能否正常工作：离线库能否打开且足够新、固定地址的查询结果是否正确、
+// 在线数据源的 token 是否配置、查询链中的每个数据源能否访问
+// 每个数据源会直接请求一次 healthProbeIP，不经过缓存，在线数据源会消耗一次额度
+func (r *Resolver) Diagnose(ctx context.Context) *DiagnoseReport {
+	rep := &DiagnoseReport{OK: true}
+	r.diagnoseDatabase(rep)
+	r.diagnoseCredentials(rep)
+	r.diagnoseProviders(ctx, rep)
+	return rep
+}
+
+func (r *Resolver) diagnoseDatabase(rep *DiagnoseReport) {
+	start := time.Now()
+	md, err := r.db.metadata()
+	if err != nil {
+		rep.add(Check{Name: "database", Status: CheckFail, Detail: err.Error(), Duration: time.Since(start)})
+		return
+	}
+	c := Check{Name: "database", Status: CheckPass, Duration: time.Since(start)}
+	switch {
+	case md.Source == SourceEmbeddedDB && existingPath(r.db[0].paths) != "":
+		c.Status = CheckWarn
+		c.Detail = fmt.Sprintf("%s exists but could not be used, falling back to the embedded database", existingPath(r.db[0].paths))
+	case md.Source == SourceEmbeddedDB:
+		c.Detail = fmt.Sprintf("embedded %s, no external database found", md.DatabaseType)
+	default:
+		c.Detail = fmt.Sprintf("%s (%s)", md.Path, md.DatabaseType)
+	}
+	rep.add(c)
+
+	age := time.Since(md.BuildTime)
+	fresh := Check{Name: "database freshness", Status: CheckPass, Detail: fmt.Sprintf("built %s, %d days ago", md.BuildTime.Format(time.DateOnly), int(age.Hours()/24))}
+	if md.Stale {
+		fresh.Status = CheckWarn
+		fresh.Detail += fmt.Sprintf(", older than %s", r.db[0].maxAge)
+	}
+	rep.add(fresh)
+
+	start = time.Now()
+	canary := Check{Name: "database canaries", Status: CheckPass}
+	var (
+		problems []string
+		missing  int // 只包含一部分网段的数据库查不到这些地址是正常的
+	)
+	err = r.db[0].with(func(db *maxminddb.Reader, source string) error {
+		for _, c := range canaries {
+			ip := net.ParseIP(c.ip)
+			if ip.To4() == nil && db.Metadata.IPVersion == 4 {
+				continue
+			}
+			_, country, err := lookupCountry(db, ip)
+			if err != nil {
+				return fmt.Errorf("lookup %s: %w", c.ip, err)
+			}
+			if country == "" {
+				missing++
+				continue
+			}
+			if c.country != "" && country != c.country {
+				problems = append(problems, fmt.Sprintf("%s: got %q, want %q", c.ip, country, c.country))
+			}
+		}
+		return nil
+	})
+	canary.Duration = time.Since(start)
+	switch {
+	case err != nil:
+		canary.Status, canary.Detail = CheckFail, err.Error()
+	case len(problems) > 0:
+		canary.Status, canary.Detail = CheckWarn, strings.Join(problems, "; ")
+	case missing > 0:
+		canary.Detail = fmt.Sprintf("%d addresses not in the database", missing)
+	default:
+		canary.Detail = "all addresses resolved as expected"
+	}
+	rep.add(canary)
+
+	if asn, err := r.asn.metadata(); err == nil {
+		rep.add(Check{Name: "asn database", Status: CheckPass, Detail: fmt.Sprintf("%s (%s)", asn.Path, asn.DatabaseType)})
+	}
+}
+
+// existingPath 返回 paths 中第一个存在的文件
+func existingPath(paths []string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// diagnoseCredentials 检查查询链中需要 token 的在线数据源
+func (r *Resolver) diagnoseCredentials(rep *DiagnoseReport) {
+	for _, p := range r.Providers() {
+		switch p := p.(type) {
+		case *ipinfoProvider:
+			c := Check{Name: "ipinfo token", Status: CheckPass}
+			switch {
+			case p.tokenFile != nil && p.tokenFile.get() == "":
+				c.Status, c.Detail = CheckFail, fmt.Sprintf("token file %s is empty or unreadable", p.tokenFile.path)
+			case p.tokenFile != nil:
+				c.Detail = "read from " + p.tokenFile.path
+			case len(p.tokens) > 0:
+				c.Detail = fmt.Sprintf("%d token(s) configured", len(p.tokens))
+			default:
+				c.Status, c.Detail = CheckWarn, "no token configured, using the free quota"
+			}
+			rep.add(c)
+		case *ipapicoProvider:
+			c := Check{Name: "ipapi.co key", Status: CheckPass, Detail: "configured"}
+			if p.key == "" {
+				c.Status, c.Detail = CheckWarn, "no key configured, using the free quota"
+			}
+			rep.add(c)
+		case *maxmindProvider:
+			c := Check{Name: "maxmind license key", Status: CheckPass, Detail: "account " + p.accountID}
+			if p.licenseKey == "" && p.keyFile.get() == "" {
+				c.Status, c.Detail = CheckFail, fmt.Sprintf("license key file %s is empty or unreadable", p.keyFile.path)
+			}
+			rep.add(c)
+		}
+	}
+}
+
+// diagnoseProviders 并发请求查询链中的每个数据源一次，按查询链的顺序输出结果
+func (r *Resolver) diagnoseProviders(ctx context.Context, rep *DiagnoseReport) {
+	chain := r.Providers()
+	checks := make([]Check, len(chain))
+	var wg sync.WaitGroup
+	for i, p := range chain {
+		wg.Go(func() {
+			c := Check{Name: "provider " + p.Name()}
+			if f, ok := p.(offlineProvider); ok && f.isOffline() {
+				c.Status, c.Detail = CheckSkip, "offline mode"
+				checks[i] = c
+				return
+			}
+			start := time.Now()
+			res, err := p.Lookup(ctx, healthProbeIP)
+			c.Duration = time.Since(start)
+			switch {
+			case errors.Is(err, ErrNotFound) || err == nil && !res.found():
+				// 只包含一部分网段的离线库或覆盖表查不到是正常的
+				c.Status, c.Detail = CheckWarn, fmt.Sprintf("no data for %s", healthProbeIP)
+			case err != nil:
+				c.Status, c.Detail = CheckFail, err.Error()
+			default:
+				c.Status, c.Detail = CheckPass, fmt.Sprintf("%s -> %s", healthProbeIP, res.Code())
+			}
+			if b, ok := p.(breakerProvider); ok && b.circuitOpen() && c.Status == CheckPass {
+				c.Status, c.Detail = CheckWarn, c.Detail+", circuit breaker is open"
+			}
+			checks[i] = c
+		})
+	}
+	wg.Wait()
+	if len(chain) == 0 {
+		rep.add(Check{Name: "provider chain", Status: CheckFail, Detail: "no providers configured"})
+	}
+	for _, c := range checks {
+		rep.add(c)
+	}
+}
+
+// offlineProvider 由内嵌 httpFetcher 的数据源实现，离线模式下自检不请求这些数据源
+type offlineProvider interface {
+	isOffline() bool
+}
+
+func (f *httpFetcher) isOffline() bool {
+	return f.offline
+}
+
+// Diagnose 检查默认实例的配置，见 Resolver.Diagnose
+func Diagnose(ctx context.Context) *DiagnoseReport {
+	return Default().Diagnose(ctx)
+}
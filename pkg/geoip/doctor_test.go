@@ -0,0 +1,23 @@
+package geoip
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	r := newTestResolver(t,
+		WithProviders(&staticProvider{name: "failing", err: ErrProviderUnavailable}, &staticProvider{name: "static", res: &Result{CountryCode: "us"}}),
+	)
+	rep := r.Diagnose(context.Background())
+	if rep.OK {
+		t.Fatalf("report with a failing provider is ok: %+v", rep.Checks)
+	}
+	status := make(map[string]CheckStatus)
+	for _, c := range rep.Checks {
+		status[c.Name] = c.Status
+	}
+	if status["provider failing"] != CheckFail || status["provider static"] != CheckPass {
+		t.Fatalf("unexpected provider checks: %+v", rep.Checks)
+	}
+}
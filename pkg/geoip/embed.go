@@ -0,0 +1,11 @@
+//go:build !nogeoipembed
+
+package geoip
+
+import _ "embed"
+
+// 内置 geoip.db，使用 gzip 压缩以减小二进制体积，首次使用时才解压到内存
+// 使用 -tags nogeoipembed 编译可以完全去掉内置数据库，此时必须提供外部数据库
+//
+//go:embed geoip.db.gz
+var embeddedDBGz []byte
@@ -0,0 +1,6 @@
+//go:build nogeoipembed
+
+package geoip
+
+// 使用 nogeoipembed 编译时不内置数据库，必须通过 GEOIP_DB_PATH 等方式提供外部数据库
+var embeddedDBGz []byte
@@ -0,0 +1,19 @@
+package geoip
+
+import "errors"
+
+// 查询失败时返回的错误，可通过 errors.Is 判断
+var (
+	// ErrNotFound 表示数据源正常工作，但没有该 IP 的数据，重试没有意义
+	ErrNotFound = errors.New("geoip: ip not found")
+	// ErrProviderUnavailable 表示在线数据源请求失败（网络错误、超时、非 200 响应等），稍后可以重试
+	ErrProviderUnavailable = errors.New("geoip: provider unavailable")
+	// ErrDBUnavailable 表示 mmdb 无法加载
+	ErrDBUnavailable = errors.New("geoip: database unavailable")
+	// ErrInvalidIP 表示传入的 IP 为空或无法解析
+	ErrInvalidIP = errors.New("geoip: invalid ip")
+	// ErrPrivateIP 在 IP 属于内网或保留地址时由 Lookup 返回
+	ErrPrivateIP = errors.New("geoip: private or reserved ip")
+	// ErrNoLocation 由 Distance 返回，表示查询结果中没有坐标，需要城市级数据库或 ipinfo 完整 JSON 接口
+	ErrNoLocation = errors.New("geoip: no coordinates for ip")
+)
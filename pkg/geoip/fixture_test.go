@@ -0,0 +1,91 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 各数据源测试共用的 Provider、Cache 与 Resolver
+
+// newTestResolver 创建一个不读取本机数据库、覆盖表，也不监听文件的 Resolver，opts 可以覆盖这些默认值
+func newTestResolver(t *testing.T, opts ...Option) *Resolver {
+	t.Helper()
+	dir := t.TempDir()
+	r := New(append([]Option{
+		WithDBPaths(filepath.Join(dir, "missing.mmdb")),
+		WithASNDBPaths(filepath.Join(dir, "missing-asn.mmdb")),
+		WithOverridePaths(),
+		WithWatch(false),
+	}, opts...)...)
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// newTestFetcher 返回请求 srv 的 httpFetcher，不重试、不限流
+func newTestFetcher(name string, srv *httptest.Server) httpFetcher {
+	return httpFetcher{name: name, client: srv.Client(), timeout: time.Second}
+}
+
+type staticProvider struct {
+	name string
+	res  *Result
+	err  error
+}
+
+func (p *staticProvider) Name() string { return p.name }
+
+func (p *staticProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	return p.res, p.err
+}
+
+type mapCache struct {
+	mu sync.Mutex
+	m  map[string]*Result
+}
+
+func (c *mapCache) Get(key string) (*Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, ok := c.m[key]
+	return res, ok
+}
+
+func (c *mapCache) Set(key string, res *Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[key] = res
+}
+
+type countingProvider struct {
+	staticProvider
+	calls int
+}
+
+func (p *countingProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	p.calls++
+	return &Result{CountryCode: p.res.CountryCode}, nil
+}
+
+// blockingProvider 在 release 关闭前阻塞，用于构造并发中的查询
+type blockingProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *blockingProvider) Name() string { return "blocking" }
+
+func (p *blockingProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	p.calls.Add(1)
+	select {
+	case <-p.release:
+		return &Result{CountryCode: "de"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
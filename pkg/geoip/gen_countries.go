@@ -0,0 +1,124 @@
+//go:build ignore
+
+// gen_countries 根据 CLDR 数据生成 countries_table.go，包括国家名称与所属的洲
+// 用法：在 pkg/geoip 目录下执行 go generate
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// 顺序需与 countries.go 中的 lang* 常量保持一致
+var langs = []language.Tag{
+	language.English,
+	language.SimplifiedChinese,
+	language.TraditionalChinese,
+	language.Japanese,
+}
+
+// CLDR 的长名称如 "Hong Kong SAR China" 不适合展示，这里改用短名称
+var shortNames = map[string][]string{
+	"HK": {"Hong Kong", "香港", "香港", "香港"},
+	"MO": {"Macao", "澳门", "澳門", "マカオ"},
+	"PS": {"Palestine", "巴勒斯坦", "巴勒斯坦", "パレスチナ"},
+}
+
+// 按 M49 区域划分所属的洲，使用 MaxMind/GeoNames 的洲代码，北美洲包括中美洲与加勒比地区
+var continents = []struct {
+	code   string
+	region string
+}{
+	{"an", "AQ"}, // M49 中南极洲属于大洋洲的外围地区，需要最先匹配
+	{"af", "002"},
+	{"as", "142"},
+	{"eu", "150"},
+	{"na", "021"},
+	{"na", "013"},
+	{"na", "029"},
+	{"sa", "005"},
+	{"oc", "009"},
+}
+
+// M49 与 MaxMind/GeoNames 划分不同的国家，以 MaxMind 为准
+var continentOverrides = map[string]string{
+	"BV": "an", "GS": "an", "HM": "an", "TF": "an", // 南极附近的岛屿
+	"CY": "eu",
+	"UM": "oc",
+}
+
+// continentOf 返回国家所属的洲代码
+func continentOf(code string, region language.Region) string {
+	if c, ok := continentOverrides[code]; ok {
+		return c
+	}
+	for _, c := range continents {
+		if r := language.MustParseRegion(c.region); r == region || r.Contains(region) {
+			return c.code
+		}
+	}
+	log.Fatalf("no continent for %s", code)
+	return ""
+}
+
+// 已从 ISO 3166-1 撤销但 CLDR 仍保留的代码
+var withdrawn = map[string]bool{"AN": true, "CS": true, "NT": true, "SU": true, "YU": true}
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_countries.go; DO NOT EDIT.\n\npackage geoip\n\n")
+	buf.WriteString("var countryNames = map[string][langCount]string{\n")
+
+	namers := make([]display.Namer, len(langs))
+	for i, tag := range langs {
+		namers[i] = display.Regions(tag)
+	}
+
+	var continentTable bytes.Buffer
+	continentTable.WriteString("var countryContinents = map[string]string{\n")
+	for a := 'A'; a <= 'Z'; a++ {
+		for b := 'A'; b <= 'Z'; b++ {
+			code := string([]rune{a, b})
+			region, err := language.ParseRegion(code)
+			// M49 为 0 的是 AC、TA 等非 ISO 3166-1 地区
+			if err != nil || !region.IsCountry() || region.Canonicalize().String() != code ||
+				region.M49() == 0 || withdrawn[code] {
+				continue
+			}
+			names, ok := shortNames[code]
+			if !ok {
+				names = make([]string, len(langs))
+				for i, n := range namers {
+					names[i] = n.Name(region)
+				}
+			}
+			fmt.Fprintf(&continentTable, "\t%q: %q,\n", strings.ToLower(code), continentOf(code, region))
+			fmt.Fprintf(&buf, "\t%q: {", strings.ToLower(code))
+			for i, name := range names {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(&buf, "%q", name)
+			}
+			buf.WriteString("},\n")
+		}
+	}
+	buf.WriteString("}\n\n")
+	continentTable.WriteString("}\n")
+	buf.Write(continentTable.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("countries_table.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
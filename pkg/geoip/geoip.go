@@ -1,163 +1,505 @@
 package geoip
 
 import (
-	_ "embed"
+	"context"
 	"errors"
-	"io"
+	"fmt"
 	"net"
-	"net/http"
-	"os"
+	"net/netip"
 	"strings"
 	"sync"
 	"time"
 
-	maxminddb "github.com/oschwald/maxminddb-golang"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //====================
-// 1. 内置 geoip.db
+// 4. 对外暴露的 Lookup
 //====================
 
-//go:embed geoip.db
-var embeddedDB []byte
+// Lookup 返回 2 位小写国家码，查不到国家时以洲码兜底；内网及保留地址返回 ErrPrivateIP
+func (r *Resolver) Lookup(ip net.IP) (string, error) {
+	return r.LookupContext(context.Background(), ip)
+}
 
-//====================
-// 2. 外部 ipinfo_lite.mmdb
-//====================
+// LookupContext 与 Lookup 相同，在线查询会随 ctx 取消；ctx 未设置 deadline 时使用默认的 2 秒超时
+func (r *Resolver) LookupContext(ctx context.Context, ip net.IP) (string, error) {
+	res, err := r.lookupDetail(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	if res.Private {
+		return "", ErrPrivateIP
+	}
+	return res.Code(), nil
+}
 
-// 容器内部的路径：对应宿主机 /opt/nezha/dashboard/data/ipinfo_lite.mmdb
-const externalDBPath = "/dashboard/data/ipinfo_lite.mmdb"
+// 批量查询时的最大并发数
+const batchWorkers = 16
 
-var (
-	dbOnce    sync.Once
-	dbReader  *maxminddb.Reader
-	dbInitErr error
-)
+// batchProvider 是支持批量查询的数据源，如 ip-api.com 的 /batch 接口
+type batchProvider interface {
+	Provider
+	LookupBatch(ctx context.Context, ips []net.IP) (map[string]*Result, error)
+}
+
+// LookupMany 并发查询多个 IP，返回 ip.String() → 国家码；查询失败的 IP 不会出现在结果中
+// 查询链中第一个数据源支持批量查询时，先用批量接口查询缓存中没有的 IP
+func (r *Resolver) LookupMany(ips []net.IP) map[string]string {
+	r.prefetchBatch(ips)
 
-func initDB() {
-	// 优先尝试外部 ipinfo_lite.mmdb
-	if info, err := os.Stat(externalDBPath); err == nil && !info.IsDir() {
-		if reader, err := maxminddb.Open(externalDBPath); err == nil {
-			dbReader = reader
-			return
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, batchWorkers)
+	)
+	result := make(map[string]string, len(ips))
+
+	for _, ip := range ips {
+		if ip == nil {
+			continue
 		}
-		// 如果打开失败，就继续往下，用内置的 embeddedDB
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			code, err := r.Lookup(ip)
+			if err != nil || code == "" {
+				return
+			}
+			mu.Lock()
+			result[ip.String()] = code
+			mu.Unlock()
+		})
 	}
+	wg.Wait()
 
-	// 外部文件不存在或失败 → 回退到内置 geoip.db
-	reader, err := maxminddb.FromBytes(embeddedDB)
-	if err != nil {
-		dbInitErr = err
+	return result
+}
+
+// prefetchBatch 通过批量接口查询 ips 中缓存里没有的 IP，结果写入缓存，之后逐个查询时直接命中
+// 未开启缓存、使用多数源一致或第一个数据源不支持批量查询时什么都不做，批量查询失败的 IP 仍会逐个查询
+func (r *Resolver) prefetchBatch(ips []net.IP) {
+	if r.cache == nil || len(r.consensus) > 0 {
+		return
+	}
+	chain := r.demoteStale(r.sortedByHealth(r.chain()))
+	if len(chain) == 0 {
+		return
+	}
+	bp, ok := chain[0].(batchProvider)
+	if !ok {
 		return
 	}
-	dbReader = reader
-}
 
-func getDB() (*maxminddb.Reader, error) {
-	dbOnce.Do(initDB)
-	return dbReader, dbInitErr
-}
+	r.providersMu.RLock()
+	useOverride := !r.noOverride
+	r.providersMu.RUnlock()
+	var pending []net.IP
+	for _, ip := range ips {
+		if ip == nil {
+			continue
+		}
+		ip = normalizeIP(ip)
+		if IsBogon(ip) {
+			continue
+		}
+		if _, ok := r.overrides.lookupResult(ip); ok && useOverride {
+			continue
+		}
+		if _, ok := r.cache.Get(ip.String()); ok {
+			continue
+		}
+		pending = append(pending, ip)
+	}
+	if len(pending) < 2 {
+		return
+	}
 
-// 支持两种 mmdb 格式：
-// - 内置 geoip.db：country/continent 是代码，country_name/continent_name 是名字
-// - 外部 ipinfo_lite.mmdb：country/country_name 是名字，country_code/continent_code 是代码
-type IPInfo struct {
-	CountryCode   string `maxminddb:"country_code"`
-	Country       string `maxminddb:"country"`
-	CountryName   string `maxminddb:"country_name"`
-	ContinentCode string `maxminddb:"continent_code"`
-	Continent     string `maxminddb:"continent"`
-	ContinentName string `maxminddb:"continent_name"`
+	ctx, cancel := context.WithTimeout(context.Background(), r.flightTimeout)
+	defer cancel()
+	results, err := bp.LookupBatch(ctx, pending)
+	if err != nil {
+		r.log.verbose(levelForError(err), "batch lookup failed, falling back to single lookups", "provider", bp.Name(), "error", err)
+	}
+	for key, res := range results {
+		if ip := net.ParseIP(key); ip != nil && res.found() {
+			ip = normalizeIP(ip)
+			r.complete(ctx, ip, ip.String(), bp.Name(), res)
+		}
+	}
 }
 
-//====================
-// 3. 先用 ipinfo.io 查询
-//====================
-
-var httpClient = &http.Client{
-	Timeout: 2 * time.Second,
+// LookupString 与 Lookup 相同，但接受字符串形式的 IP
+func (r *Resolver) LookupString(ip string) (string, error) {
+	return r.LookupStringContext(context.Background(), ip)
 }
 
-// 通用的请求+解析函数：请求 url，返回 2 位小写国家码
-func fetchIPInfoCountry(url string) (string, error) {
-	resp, err := httpClient.Get(url)
+// LookupStringContext 与 LookupString 相同，但接受 ctx
+func (r *Resolver) LookupStringContext(ctx context.Context, ip string) (string, error) {
+	addr, err := parseAddr(ip)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
+	return r.LookupContext(ctx, net.IP(addr.AsSlice()))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New("ipinfo status not OK")
+// LookupAddr 与 Lookup 相同，但接受 netip.Addr
+func (r *Resolver) LookupAddr(addr netip.Addr) (string, error) {
+	if !addr.IsValid() {
+		return "", ErrInvalidIP
 	}
+	return r.Lookup(net.IP(addr.AsSlice()))
+}
 
-	body, err := io.ReadAll(resp.Body)
+// parseAddr 解析 IP 字符串，兼容首尾空白、[::1] 形式的 IPv6 以及 fe80::1%eth0 形式的 zone，zone 对定位没有意义，直接去掉
+func parseAddr(s string) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
 	if err != nil {
-		return "", err
+		return netip.Addr{}, fmt.Errorf("%w %q: %w", ErrInvalidIP, s, err)
 	}
+	return addr.WithZone("").Unmap(), nil
+}
 
-	code := strings.TrimSpace(string(body))
+// LookupDetail 返回完整的查询结果，包括国家/洲的代码与名称以及数据来源
+// 内网及保留地址返回 Private 为 true 的空结果
+func (r *Resolver) LookupDetail(ip net.IP) (*Result, error) {
+	return r.LookupDetailContext(context.Background(), ip)
+}
+
+// LookupDetailContext 与 LookupDetail 相同，但接受 ctx
+func (r *Resolver) LookupDetailContext(ctx context.Context, ip net.IP) (*Result, error) {
+	res, err := r.lookupDetail(ctx, ip)
+	return r.withHostname(ctx, ip, res, err)
+}
+
+// LookupDetailStringContext 与 LookupDetailContext 相同，但接受字符串形式的 IP
+func (r *Resolver) LookupDetailStringContext(ctx context.Context, ip string) (*Result, error) {
+	addr, err := parseAddr(ip)
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupDetailContext(ctx, net.IP(addr.AsSlice()))
+}
 
-	// 简化判断：只要不是 2 个字符，就认为失败
-	if len(code) != 2 {
-		return "", errors.New("invalid country code from ipinfo")
+// lookupDetail 按顺序遍历查询链，返回第一个查到国家码或洲码的结果
+func (r *Resolver) lookupDetail(ctx context.Context, ip net.IP) (*Result, error) {
+	ctx, span := r.startSpan(ctx, "geoip.Lookup")
+	if span.IsRecording() {
+		span.SetAttributes(attribute.String("geoip.ip", ip.String()))
+	}
+	res, err := r.lookupResult(ctx, ip)
+	if err == nil {
+		r.metrics.lookups.WithLabelValues(res.Source).Inc()
+	}
+	r.lookupStats.record(res, err)
+	endSpan(span, res, err)
+	if err != nil {
+		return nil, err
 	}
+	return r.normalize(res)
+}
 
-	// 统一转成小写返回
-	return strings.ToLower(code), nil
+// normalize 按 WithUppercaseCodes 与 WithContinentFallback 调整返回给调用方的结果，res 是缓存中结果的副本，可以直接修改
+func (r *Resolver) normalize(res *Result) (*Result, error) {
+	if r.noContinentFallback && res.CountryCode == "" && !res.Private {
+		return nil, fmt.Errorf("%w: only continent %s is known", ErrNotFound, res.ContinentCode)
+	}
+	if r.uppercaseCodes {
+		res.CountryCode = strings.ToUpper(res.CountryCode)
+		res.ContinentCode = strings.ToUpper(res.ContinentCode)
+	}
+	return res, nil
 }
 
-// 尝试从 ipinfo 获取国家代码；如果没有 TOKEN，直接视为失败跳过 ipinfo
-// token 从环境变量 IPINFO_TOKEN 读取；为空则跳过 ipinfo 调用
-// token 位置 dashboard/docker-compose.yaml
-func lookupFromIPInfo(ip net.IP) (string, error) {
+func (r *Resolver) lookupResult(ctx context.Context, ip net.IP) (*Result, error) {
 	if ip == nil {
-		return "", errors.New("nil ip")
+		return nil, ErrInvalidIP
 	}
+	ip = normalizeIP(ip)
 
-	//url := "https://ipinfo.io/" + ip.String() + "/country?token=xxxxxxxx"
-	url := "https://ipinfo.io/" + ip.String() + "/country"
-	return fetchIPInfoCountry(url)
+	// 用户提供的覆盖表优先于所有数据源
+	r.providersMu.RLock()
+	useOverride := !r.noOverride
+	r.providersMu.RUnlock()
+	if useOverride {
+		if res, ok := r.overrides.lookupResult(ip); ok {
+			return decorate(res), nil
+		}
+	}
+
+	// 内网及保留地址不可能查到结果，直接返回，避免白白等待在线查询超时
+	if IsBogon(ip) {
+		return &Result{Private: true, Source: SourceBogon}, nil
+	}
+
+	key := ip.String()
+	if r.cache != nil {
+		res, ok := r.cache.Get(key)
+		r.metrics.cacheLookup(ok)
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetAttributes(attribute.Bool("geoip.cache_hit", ok))
+		}
+		if ok {
+			cp := *res
+			return &cp, nil
+		}
+	}
+	if err, ok := r.negative.get(key); ok {
+		return nil, err
+	}
+
+	// 调用方已经放弃时不再发起查询
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 大量 Agent 同时重连时会并发查询同一个 IP，合并为一次查询，各调用方拿到结果的副本
+	// 合并的查询不随发起者的 ctx 取消，使用 Resolver 自己的超时，每个调用方只按自己的 ctx 放弃等待
+	ch := r.inflight.DoChan(key, func() (any, error) {
+		fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.flightTimeout)
+		defer cancel()
+		return r.resolve(fctx, ip, key)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		cp := *res.Val.(*Result)
+		return &cp, nil
+	}
 }
 
-//====================
-// 4. 对外暴露的 Lookup
-//====================
+// resolve 按顺序遍历查询链，并将结果写入缓存；开启了 WithConsensus 时先按多数一致的结果返回
+func (r *Resolver) resolve(ctx context.Context, ip net.IP, key string) (*Result, error) {
+	if res, provider, ok := r.resolveConsensus(ctx, ip, key); ok {
+		return r.complete(ctx, ip, key, provider, res), nil
+	}
 
-func Lookup(ip net.IP) (string, error) {
-	// 1) 优先用 ipinfo.io（仅在配置了 TOKEN 时生效）
-	if code, err := lookupFromIPInfo(ip); err == nil && code != "" {
-		// code 已经是 2 位小写，如 hk、us、cn
-		return code, nil
+	var errs []error
+	for _, p := range r.demoteStale(r.sortedByHealth(r.chain())) {
+		res, err := r.query(ctx, p, ip)
+		if err != nil {
+			r.log.verbose(levelForError(err), "provider failed, trying the next one", "provider", p.Name(), "ip", key, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		return r.complete(ctx, ip, key, p.Name(), res), nil
 	}
 
-	// 2) 外部调用失败 → 回退到 mmdb 逻辑
-	db, err := getDB()
-	if err != nil {
-		return "", err
+	// 各数据源的错误合并返回，errors.Is 可判断其中是否有 ErrNotFound、ErrDBUnavailable 等
+	err := ErrNotFound
+	if len(errs) > 0 {
+		err = errors.Join(errs...)
+	}
+	// 取消或超时不代表这个 IP 查不到，不缓存
+	if ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		r.negative.set(key, err)
 	}
+	return nil, err
+}
 
-	var record IPInfo
-	if err := db.Lookup(ip, &record); err != nil {
-		return "", err
+// query 请求一个数据源，记录耗时、健康状况与链路追踪；没有国家码或洲码的结果视为 ErrNotFound
+func (r *Resolver) query(ctx context.Context, p Provider, ip net.IP) (*Result, error) {
+	start := time.Now()
+	pctx, span := r.startSpan(ctx, "geoip.provider")
+	if span.IsRecording() {
+		span.SetAttributes(attribute.String("geoip.provider", p.Name()))
+	}
+	res, err := p.Lookup(pctx, ip)
+	if err == nil && !res.found() {
+		err = ErrNotFound
+	}
+	endSpan(span, res, err)
+	elapsed := time.Since(start)
+	r.health.record(p.Name(), elapsed, err)
+	r.metrics.observe(p.Name(), elapsed, err)
+	return res, err
+}
+
+// complete 补全 provider 返回的结果，在线数据源的结果写入缓存
+func (r *Resolver) complete(ctx context.Context, ip net.IP, key, provider string, res *Result) *Result {
+	// 在线数据源往往只返回国家码，其余字段尽量从 mmdb 补全
+	if provider != ProviderMMDB && res.CountryCode != "" {
+		_, span := r.startSpan(ctx, "geoip.mmdb")
+		dbRes, err := r.db.lookup(ip)
+		endSpan(span, dbRes, err)
+		if err == nil && dbRes.CountryCode == res.CountryCode {
+			res.fill(dbRes)
+		}
+	}
+	if res.ASN == nil {
+		if asn, err := r.asn.lookupASN(ip); err == nil {
+			res.ASN = asn
+		}
 	}
+	decorate(res)
+	// 本地数据库查询足够快，且替换数据库后应立即生效，只缓存在线数据源的结果
+	if r.cache != nil && !isLocalProvider(provider) {
+		cp := *res
+		r.cache.Set(key, &cp)
+	}
+	return res
+}
 
-	// ==== 国家码优先级 ====
-	// 1) 优先 country_code（适配你外部化的 mmdb）
-	if record.CountryCode != "" {
-		return strings.ToLower(record.CountryCode), nil
+// decorate 补全由国家码推导出的字段
+func decorate(res *Result) *Result {
+	res.Flag = FlagEmoji(res.CountryCode)
+	// 数据库未提供欧盟标记时使用内置的成员国列表
+	res.IsEU = res.IsEU || IsEU(res.CountryCode)
+	// 只返回国家码的数据源（如 ipinfo /country）由国家码推导所属的洲
+	if res.ContinentCode == "" {
+		res.ContinentCode = ContinentOf(res.CountryCode)
 	}
-	// 2) 其次 country 刚好是 2 位（适配内置 mmdb 把代码放在 country 的情况）
-	if record.Country != "" && len(record.Country) == 2 {
-		return strings.ToLower(record.Country), nil
+	if res.ContinentName == "" {
+		res.ContinentName = ContinentName(res.ContinentCode, "en")
 	}
+	return res
+}
 
-	// ==== 洲码兜底（极端情况用洲代码）====
-	if record.ContinentCode != "" {
-		return strings.ToLower(record.ContinentCode), nil
+// LookupASN 返回 IP 所属的 AS 号与组织名称
+// 优先使用 ASN 数据库，其次是同时包含 ASN 的离线库（如新版 ipinfo Lite），都没有时再请求 ipinfo
+func (r *Resolver) LookupASN(ip net.IP) (*ASN, error) {
+	if asn, err := r.LookupASNLocal(ip); err == nil {
+		return asn, nil
 	}
-	if record.Continent != "" && len(record.Continent) == 2 {
-		return strings.ToLower(record.Continent), nil
+	return r.ipinfo.lookupASN(context.Background(), ip)
+}
+
+// LookupASNLocal 只查询本地的 ASN 数据库与离线库，不发起网络请求，都没有时返回 ErrNotFound
+func (r *Resolver) LookupASNLocal(ip net.IP) (*ASN, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+	if asn, err := r.asn.lookupASN(ip); err == nil {
+		return asn, nil
+	}
+	if res, err := r.db.lookup(ip); err == nil && res.ASN != nil {
+		return res.ASN, nil
+	}
+	return nil, ErrNotFound
+}
+
+// LookupCity 返回城市级位置信息，仅使用 mmdb
+// 当前数据库只有国家级数据时返回空的 City 而不是错误
+func (r *Resolver) LookupCity(ip net.IP) (*City, error) {
+	res, err := r.db.lookup(ip)
+	if err != nil {
+		return nil, err
+	}
+	if res.City == nil {
+		return &City{}, nil
+	}
+	return res.City, nil
+}
+
+// LookupTimezone 返回 IP 所在地的 IANA 时区
+// 优先使用城市级 mmdb 中的数据，没有时再请求 ipinfo
+func (r *Resolver) LookupTimezone(ip net.IP) (string, error) {
+	if res, err := r.db.lookup(ip); err == nil && res.Timezone != "" {
+		return res.Timezone, nil
+	}
+	return r.ipinfo.lookupTimezone(context.Background(), ip)
+}
+
+// LookupPrivacy 返回 IP 的 VPN/代理/机房检测信息
+// 优先使用已加载的隐私检测 mmdb，没有时再请求 ipinfo 的 /privacy 接口
+func (r *Resolver) LookupPrivacy(ip net.IP) (*Privacy, error) {
+	if res, err := r.db.lookup(ip); err == nil && res.Privacy != nil {
+		return res.Privacy, nil
+	}
+	return r.ipinfo.lookupPrivacy(context.Background(), ip)
+}
+
+// LookupSelf 通过 Cloudflare 的 trace 接口获取本机的公网 IP 及其国家码，无需 token
+func (r *Resolver) LookupSelf(ctx context.Context) (net.IP, *Result, error) {
+	ip, res, err := r.cloudflare.trace(ctx)
+	if err != nil {
+		return ip, nil, err
 	}
+	return ip, decorate(res), nil
+}
+
+//====================
+// 5. 默认实例
+//====================
+
+// Lookup 使用默认实例查询，见 Resolver.Lookup
+func Lookup(ip net.IP) (string, error) {
+	return Default().Lookup(ip)
+}
+
+// LookupContext 使用默认实例查询，见 Resolver.LookupContext
+func LookupContext(ctx context.Context, ip net.IP) (string, error) {
+	return Default().LookupContext(ctx, ip)
+}
+
+// LookupMany 使用默认实例批量查询，见 Resolver.LookupMany
+func LookupMany(ips []net.IP) map[string]string {
+	return Default().LookupMany(ips)
+}
+
+// LookupString 使用默认实例查询，见 Resolver.LookupString
+func LookupString(ip string) (string, error) {
+	return Default().LookupString(ip)
+}
+
+// LookupStringContext 使用默认实例查询，见 Resolver.LookupStringContext
+func LookupStringContext(ctx context.Context, ip string) (string, error) {
+	return Default().LookupStringContext(ctx, ip)
+}
+
+// LookupAddr 使用默认实例查询，见 Resolver.LookupAddr
+func LookupAddr(addr netip.Addr) (string, error) {
+	return Default().LookupAddr(addr)
+}
+
+// LookupDetail 使用默认实例查询，见 Resolver.LookupDetail
+func LookupDetail(ip net.IP) (*Result, error) {
+	return Default().LookupDetail(ip)
+}
+
+// LookupDetailContext 使用默认实例查询，见 Resolver.LookupDetailContext
+func LookupDetailContext(ctx context.Context, ip net.IP) (*Result, error) {
+	return Default().LookupDetailContext(ctx, ip)
+}
+
+// LookupDetailStringContext 使用默认实例查询，见 Resolver.LookupDetailStringContext
+func LookupDetailStringContext(ctx context.Context, ip string) (*Result, error) {
+	return Default().LookupDetailStringContext(ctx, ip)
+}
+
+// LookupSelf 使用默认实例查询本机的公网 IP，见 Resolver.LookupSelf
+func LookupSelf(ctx context.Context) (net.IP, *Result, error) {
+	return Default().LookupSelf(ctx)
+}
+
+// LookupASN 使用默认实例查询，见 Resolver.LookupASN
+func LookupASN(ip net.IP) (*ASN, error) {
+	return Default().LookupASN(ip)
+}
+
+// LookupASNLocal 使用默认实例查询，见 Resolver.LookupASNLocal
+func LookupASNLocal(ip net.IP) (*ASN, error) {
+	return Default().LookupASNLocal(ip)
+}
+
+// LookupCity 使用默认实例查询，见 Resolver.LookupCity
+func LookupCity(ip net.IP) (*City, error) {
+	return Default().LookupCity(ip)
+}
+
+// LookupTimezone 使用默认实例查询，见 Resolver.LookupTimezone
+func LookupTimezone(ip net.IP) (string, error) {
+	return Default().LookupTimezone(ip)
+}
 
-	return "", errors.New("IP not found")
+// LookupPrivacy 使用默认实例查询，见 Resolver.LookupPrivacy
+func LookupPrivacy(ip net.IP) (*Privacy, error) {
+	return Default().LookupPrivacy(ip)
 }
@@ -0,0 +1,125 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLookupErrors(t *testing.T) {
+	if _, err := LookupString("not-an-ip"); !errors.Is(err, ErrInvalidIP) {
+		t.Fatalf("LookupString error = %v, expected ErrInvalidIP", err)
+	}
+	if _, err := Lookup(nil); !errors.Is(err, ErrInvalidIP) {
+		t.Fatalf("Lookup(nil) error = %v, expected ErrInvalidIP", err)
+	}
+
+	r := New(WithProviders(
+		&staticProvider{name: "down", err: ErrProviderUnavailable},
+		&staticProvider{name: "empty", res: &Result{}},
+	))
+	_, err := r.Lookup(net.ParseIP("1.1.1.1"))
+	if !errors.Is(err, ErrProviderUnavailable) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected both ErrProviderUnavailable and ErrNotFound", err)
+	}
+}
+
+func TestLookupASNLocal(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte("AS13335 Cloudflare, Inc."))
+	}))
+	defer srv.Close()
+
+	r := New(WithDBPaths(), WithASNDBPaths())
+	r.ipinfo.endpoint = srv.URL
+	if _, err := r.LookupASNLocal(net.ParseIP("1.1.1.1")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("LookupASNLocal error = %v, expected ErrNotFound", err)
+	}
+	if _, err := r.LookupASNLocal(nil); !errors.Is(err, ErrInvalidIP) {
+		t.Fatalf("LookupASNLocal(nil) error = %v, expected ErrInvalidIP", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("LookupASNLocal made %d network requests, expected none", n)
+	}
+}
+
+func TestLookupSingleflight(t *testing.T) {
+	p := &blockingProvider{release: make(chan struct{})}
+	r := New(WithProviders(p))
+
+	var wg sync.WaitGroup
+	results := make([]*Result, 8)
+	for i := range results {
+		wg.Go(func() {
+			results[i], _ = r.LookupDetail(net.ParseIP("1.1.1.1"))
+		})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(p.release)
+	wg.Wait()
+
+	if n := p.calls.Load(); n != 1 {
+		t.Fatalf("provider called %d times, expected 1", n)
+	}
+	for i, res := range results {
+		if res == nil || res.CountryCode != "de" {
+			t.Fatalf("result %d = %+v", i, res)
+		}
+	}
+	if results[0] == results[1] {
+		t.Fatal("callers should get independent copies of the result")
+	}
+}
+
+func TestLookupSingleflightCancel(t *testing.T) {
+	p := &blockingProvider{release: make(chan struct{})}
+	r := New(WithProviders(p))
+	ip := net.ParseIP("1.1.1.1")
+
+	// 发起合并查询的调用方取消后，其他等待同一查询的调用方仍然拿到结果
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := r.LookupDetailContext(ctx, ip)
+		first <- err
+	}()
+	for p.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan *Result, 1)
+	go func() {
+		res, _ := r.LookupDetailContext(context.Background(), ip)
+		second <- res
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller error = %v, expected context.Canceled", err)
+	}
+	close(p.release)
+	if res := <-second; res == nil || res.CountryCode != "de" {
+		t.Fatalf("waiting caller result = %+v, expected de", res)
+	}
+	if n := p.calls.Load(); n != 1 {
+		t.Fatalf("provider called %d times, expected 1", n)
+	}
+
+	// 合并查询本身超时同样不会写入查询失败的缓存
+	r = New(WithProviders(&blockingProvider{release: make(chan struct{})}))
+	r.flightTimeout = 10 * time.Millisecond
+	if _, err := r.LookupDetail(ip); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("timed out lookup error = %v, expected context.DeadlineExceeded", err)
+	}
+	if _, ok := r.negative.get(ip.String()); ok {
+		t.Fatal("a timed out lookup should not be negative-cached")
+	}
+}
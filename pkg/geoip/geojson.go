@@ -0,0 +1,65 @@
+package geoip
+
+import "maps"
+
+// GeoJSON 的精度，写入 Feature 的 precision 属性
+const (
+	PrecisionCity    = "city"    // 数据源返回的坐标
+	PrecisionCountry = "country" // 国家的近似地理中心
+)
+
+// FeatureCollection 是 RFC 7946 的 GeoJSON FeatureCollection，可以直接交给 Leaflet、MapLibre 等地图库
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature 是一个点要素
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Point          `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Point 是 GeoJSON 的 Point，Coordinates 按 GeoJSON 的规定经度在前
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// NewFeatureCollection 返回空的 FeatureCollection，序列化后 features 为 [] 而不是 null
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+}
+
+// Add 添加 res 所在位置的点，properties 中再补充 country_code、city 与 precision
+// 有坐标时使用坐标，否则使用国家的近似地理中心，两者都没有时不添加并返回 false
+func (fc *FeatureCollection) Add(res *Result, properties map[string]any) bool {
+	if res == nil {
+		return false
+	}
+	loc, precision := res.Location, PrecisionCity
+	if loc == nil || loc.Latitude == 0 && loc.Longitude == 0 {
+		centroid, ok := CountryCentroid(res.CountryCode)
+		if !ok {
+			return false
+		}
+		loc, precision = &centroid, PrecisionCountry
+	}
+
+	props := make(map[string]any, len(properties)+3)
+	maps.Copy(props, properties)
+	props["precision"] = precision
+	if res.CountryCode != "" {
+		props["country_code"] = res.CountryCode
+	}
+	if res.City != nil && res.City.Name != "" {
+		props["city"] = res.City.Name
+	}
+	fc.Features = append(fc.Features, &Feature{
+		Type:       "Feature",
+		Geometry:   Point{Type: "Point", Coordinates: [2]float64{loc.Longitude, loc.Latitude}},
+		Properties: props,
+	})
+	return true
+}
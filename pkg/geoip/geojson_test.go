@@ -0,0 +1,35 @@
+package geoip
+
+import "testing"
+
+func TestFeatureCollection(t *testing.T) {
+	fc := NewFeatureCollection()
+	if !fc.Add(&Result{CountryCode: "fr", City: &City{Name: "Paris"}, Location: &Location{Latitude: 48.86, Longitude: 2.35}}, map[string]any{"id": 1}) {
+		t.Fatal("result with location not added")
+	}
+	if !fc.Add(&Result{CountryCode: "JP"}, nil) {
+		t.Fatal("result with country not added")
+	}
+	if fc.Add(&Result{ContinentCode: "eu"}, nil) || fc.Add(nil, nil) {
+		t.Fatal("result without location added")
+	}
+	if len(fc.Features) != 2 {
+		t.Fatalf("features = %d, want 2", len(fc.Features))
+	}
+
+	city := fc.Features[0]
+	if city.Geometry.Coordinates != [2]float64{2.35, 48.86} || city.Properties["precision"] != PrecisionCity || city.Properties["city"] != "Paris" || city.Properties["id"] != 1 {
+		t.Fatalf("city feature = %+v", city)
+	}
+	country := fc.Features[1]
+	if centroid, _ := CountryCentroid("jp"); country.Geometry.Coordinates != [2]float64{centroid.Longitude, centroid.Latitude} || country.Properties["precision"] != PrecisionCountry {
+		t.Fatalf("country feature = %+v", country)
+	}
+
+	// 每个有名称的国家都应该有地理中心
+	for code := range countryNames {
+		if _, ok := CountryCentroid(code); !ok {
+			t.Fatalf("no centroid for %s", code)
+		}
+	}
+}
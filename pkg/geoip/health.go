@@ -0,0 +1,171 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// 健康检查使用的 IP，选择一个所有数据源都一定能查到的地址
+var healthProbeIP = net.IPv4(8, 8, 8, 8)
+
+const (
+	// 错误率与延迟的指数移动平均系数，越大越偏向最近的请求
+	healthAlpha = 0.2
+	// 连续失败达到该次数或错误率超过 unhealthyErrorRate 时认为数据源不健康
+	unhealthyFailures  = 3
+	unhealthyErrorRate = 0.5
+)
+
+// ProviderHealth 描述一个数据源最近的健康状况
+type ProviderHealth struct {
+	Provider            string        `json:"provider"`
+	Healthy             bool          `json:"healthy"`
+	Requests            uint64        `json:"requests"`
+	Failures            uint64        `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	ErrorRate           float64       `json:"error_rate"` // 指数移动平均，0~1
+	Latency             time.Duration `json:"latency"`    // 指数移动平均
+	LastSuccess         time.Time     `json:"last_success,omitzero"`
+	LastFailure         time.Time     `json:"last_failure,omitzero"`
+	LastError           string        `json:"last_error,omitempty"`
+	CircuitOpen         bool          `json:"circuit_open"` // 在线数据源的熔断是否生效中，见 WithCircuitBreaker
+}
+
+func (h *ProviderHealth) healthy() bool {
+	return h.ConsecutiveFailures < unhealthyFailures && h.ErrorRate < unhealthyErrorRate
+}
+
+// healthTable 记录每个数据源的请求结果，包括正常查询与定时健康检查
+type healthTable struct {
+	mu sync.Mutex
+	m  map[string]*ProviderHealth
+}
+
+func newHealthTable() *healthTable {
+	return &healthTable{m: make(map[string]*ProviderHealth)}
+}
+
+// record 记录一次请求；ErrNotFound 说明数据源工作正常，只是没有数据，不计为失败
+func (t *healthTable) record(name string, latency time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.m[name]
+	if !ok {
+		h = &ProviderHealth{Provider: name, Latency: latency}
+		t.m[name] = h
+	}
+	h.Requests++
+	h.Latency = time.Duration(healthAlpha*float64(latency) + (1-healthAlpha)*float64(h.Latency))
+
+	failed := err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrInvalidIP)
+	if !failed {
+		h.ConsecutiveFailures = 0
+		h.ErrorRate *= 1 - healthAlpha
+		h.LastSuccess = time.Now()
+		return
+	}
+	h.Failures++
+	h.ConsecutiveFailures++
+	h.ErrorRate = healthAlpha + (1-healthAlpha)*h.ErrorRate
+	h.LastFailure = time.Now()
+	h.LastError = err.Error()
+}
+
+// healthy 返回数据源是否健康，没有记录的数据源视为健康
+func (t *healthTable) healthy(name string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.m[name]
+	return !ok || h.healthy()
+}
+
+// order 将不健康的数据源移到查询链末尾，其余保持原来的顺序
+// 不健康的数据源不会被移除，所有健康的数据源都失败时仍然会尝试它们
+func (t *healthTable) order(chain []Provider) []Provider {
+	healthy := make([]Provider, 0, len(chain))
+	var unhealthy []Provider
+	for _, p := range chain {
+		if t.healthy(p.Name()) {
+			healthy = append(healthy, p)
+		} else {
+			unhealthy = append(unhealthy, p)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (t *healthTable) snapshot(name string) ProviderHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.m[name]
+	if !ok {
+		return ProviderHealth{Provider: name, Healthy: true}
+	}
+	s := *h
+	s.Healthy = h.healthy()
+	return s
+}
+
+// Health 按查询链的顺序返回各数据源的健康状况
+func (r *Resolver) Health() []ProviderHealth {
+	chain := r.Providers()
+	stats := make([]ProviderHealth, 0, len(chain))
+	for _, p := range chain {
+		h := r.health.snapshot(p.Name())
+		if b, ok := p.(breakerProvider); ok {
+			h.CircuitOpen = b.circuitOpen()
+		}
+		stats = append(stats, h)
+	}
+	return stats
+}
+
+// checkHealth 用 healthProbeIP 请求查询链中的每个数据源一次
+func (r *Resolver) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, p := range r.Providers() {
+		wg.Go(func() {
+			start := time.Now()
+			res, err := p.Lookup(ctx, healthProbeIP)
+			if err == nil && !res.found() {
+				err = ErrNotFound
+			}
+			r.health.record(p.Name(), time.Since(start), err)
+		})
+	}
+	wg.Wait()
+}
+
+// healthLoop 每隔 interval 检查一次各数据源，直到 ctx 被取消
+func (r *Resolver) healthLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sortedByHealth 供查询链使用，未开启自适应排序时原样返回
+func (r *Resolver) sortedByHealth(chain []Provider) []Provider {
+	if !r.adaptive {
+		return chain
+	}
+	return r.health.order(chain)
+}
+
+// Health 返回默认实例各数据源的健康状况，见 Resolver.Health
+func Health() []ProviderHealth {
+	return Default().Health()
+}
@@ -0,0 +1,31 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestAdaptiveOrder(t *testing.T) {
+	broken := &staticProvider{name: "broken", err: ErrProviderUnavailable}
+	good := &staticProvider{name: "good", res: &Result{CountryCode: "jp"}}
+	r := New(WithProviders(broken, good), WithAdaptiveOrder(true))
+
+	for i := range unhealthyFailures + 1 {
+		if _, err := r.LookupDetail(net.ParseIP(fmt.Sprintf("1.1.1.%d", i+1))); err != nil {
+			t.Fatalf("LookupDetail: %v", err)
+		}
+	}
+	health := r.Health()
+	if len(health) != 2 || health[0].Healthy || health[0].Requests != unhealthyFailures || !health[1].Healthy {
+		t.Fatalf("Health = %+v", health)
+	}
+
+	// 恢复后回到原来的位置
+	broken.err, broken.res = nil, &Result{CountryCode: "us"}
+	r.checkHealth(context.Background())
+	if res, err := r.LookupDetail(net.ParseIP("1.1.1.100")); err != nil || res.CountryCode != "us" {
+		t.Fatalf("LookupDetail after recovery = %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,164 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 在线查询单次请求的默认超时
+const defaultTimeout = 2 * time.Second
+
+// 计算合并查询的超时时按查询链中最多依次请求的在线数据源个数估算，每个数据源最多 retries+1 次请求
+const flightProviders = 3
+
+// newTransport 创建在线查询使用的 Transport，proxyURL 为空时使用 HTTP_PROXY 等环境变量
+// 国内部分机器无法直连 ipinfo.io，可以单独为 GeoIP 查询配置代理而不影响面板的其他请求
+func newTransport(proxyURL string) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if proxyURL == "" {
+		return t
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		log.Printf("NEZHA>> geoip: invalid proxy %q, using environment proxy settings", proxyURL)
+		return t
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		t.Proxy = http.ProxyURL(u)
+	default:
+		log.Printf("NEZHA>> geoip: unsupported proxy scheme %q, using environment proxy settings", u.Scheme)
+	}
+	return t
+}
+
+// 重试的默认次数与退避时间
+const (
+	defaultRetries = 1
+	defaultBackoff = 200 * time.Millisecond
+	maxBackoff     = 2 * time.Second
+)
+
+// errRateLimited 表示数据源返回了 429
+var errRateLimited = errors.New("rate limited")
+
+// httpFetcher 是各在线数据源共用的请求逻辑
+type httpFetcher struct {
+	name    string // 用于错误信息，如 ipinfo
+	client  *http.Client
+	timeout time.Duration // 单次请求的超时，调用方的 ctx 设置了 deadline 时以 ctx 为准
+	retries int           // 网络错误、429、5xx 等临时故障的最大重试次数
+	backoff time.Duration // 第一次重试前的等待时间，之后指数增长并加入随机抖动
+	offline bool          // 离线模式下不发起任何请求
+	breaker *circuitBreaker
+	limiter *rateLimiter // 客户端的请求额度，为 nil 时不限制
+}
+
+func (f *httpFetcher) rateLimiter() *rateLimiter {
+	return f.limiter
+}
+
+// fetch 发起请求并返回响应体；网络错误与非 200 响应包装为 ErrProviderUnavailable，404 视为 ErrNotFound
+// 临时故障会按指数退避重试，最多重试 retries 次
+func (f *httpFetcher) fetch(ctx context.Context, req *http.Request) ([]byte, error) {
+	if f.offline {
+		return nil, fmt.Errorf("%w: offline mode", ErrProviderUnavailable)
+	}
+	if !f.breaker.allow() {
+		return nil, fmt.Errorf("%w: %s circuit open", ErrProviderUnavailable, f.name)
+	}
+
+	for attempt := 0; ; attempt++ {
+		// 每次请求（包括重试）都会消耗额度
+		if !f.limiter.allow() {
+			return nil, fmt.Errorf("%w: %s quota exhausted", ErrProviderUnavailable, f.name)
+		}
+		body, retryable, err := f.fetchOnce(ctx, req)
+		switch {
+		case err == nil || !retryable:
+			// 404、403 等说明数据源本身是正常的
+			f.breaker.success()
+			return body, err
+		case errors.Is(err, errRateLimited):
+			// 额度用尽时重试没有意义，直接熔断
+			f.breaker.failure(true)
+			return nil, err
+		case attempt >= f.retries || ctx.Err() != nil:
+			f.breaker.failure(false)
+			return nil, err
+		}
+
+		select {
+		case <-time.After(f.backoffFor(attempt)):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrProviderUnavailable, ctx.Err())
+		}
+
+		// POST 等带 body 的请求需要重新获取 body
+		if req.GetBody != nil {
+			rc, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = rc
+		}
+	}
+}
+
+// backoffFor 返回第 attempt 次失败后的等待时间：backoff * 2^attempt，再加上最多同等长度的随机抖动
+func (f *httpFetcher) backoffFor(attempt int) time.Duration {
+	d := f.backoff << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// fetchOnce 发起一次请求，retryable 表示失败是否为可重试的临时故障
+func (f *httpFetcher) fetchOnce(ctx context.Context, req *http.Request) (body []byte, retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	resp, err := f.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, true, fmt.Errorf("%w: %w: %s", ErrProviderUnavailable, errRateLimited, f.name)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("%w: %s status %d", ErrProviderUnavailable, f.name, resp.StatusCode)
+	default:
+		return nil, false, fmt.Errorf("%w: %s status %d", ErrProviderUnavailable, f.name, resp.StatusCode)
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+	return body, false, nil
+}
+
+// get 发起 GET 请求并返回响应体
+func (f *httpFetcher) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.fetch(ctx, req)
+}
@@ -0,0 +1,64 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewTransportProxy(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://ipinfo.io/1.1.1.1/country", nil)
+	for proxy, expected := range map[string]string{
+		"socks5://127.0.0.1:1080":   "socks5://127.0.0.1:1080",
+		"http://proxy.example:3128": "http://proxy.example:3128",
+		"ftp://proxy.example":       "",
+	} {
+		t.Setenv("HTTPS_PROXY", "")
+		u, err := newTransport(proxy).Proxy(req)
+		if err != nil {
+			t.Fatalf("Proxy(%s): %v", proxy, err)
+		}
+		got := ""
+		if u != nil {
+			got = u.String()
+		}
+		if got != expected {
+			t.Fatalf("proxy for %s = %q, expected %q", proxy, got, expected)
+		}
+	}
+}
+
+func TestFetchRetry(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case calls < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	f := &httpFetcher{name: "test", client: srv.Client(), timeout: time.Second, retries: 2, backoff: time.Millisecond}
+	if body, err := f.get(context.Background(), srv.URL); err != nil || string(body) != "ok" || calls != 3 {
+		t.Fatalf("get = %q, %v after %d calls", body, err, calls)
+	}
+
+	calls = 0
+	if _, err := f.get(context.Background(), srv.URL+"/missing"); !errors.Is(err, ErrNotFound) || calls != 1 {
+		t.Fatalf("get missing = %v after %d calls, expected ErrNotFound without retry", err, calls)
+	}
+
+	calls = 0
+	f.retries = 0
+	if _, err := f.get(context.Background(), srv.URL); !errors.Is(err, ErrProviderUnavailable) || calls != 1 {
+		t.Fatalf("get without retries = %v after %d calls", err, calls)
+	}
+}
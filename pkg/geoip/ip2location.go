@@ -0,0 +1,290 @@
+package geoip
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SourceIP2Location 表示结果来自 IP2Location 的 BIN 数据库
+const SourceIP2Location = "ip2location"
+
+// IP2Location BIN 各字段所在的列，下标为数据库类型 DB1~DB26，0 表示该类型没有这个字段
+// 第 1 列是起始 IP，国家从第 2 列开始
+var (
+	ip2locationRegionColumn    = [27]uint8{0, 0, 0, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3}
+	ip2locationCityColumn      = [27]uint8{0, 0, 0, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4}
+	ip2locationLatitudeColumn  = [27]uint8{0, 0, 0, 0, 0, 5, 5, 0, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+	ip2locationLongitudeColumn = [27]uint8{0, 0, 0, 0, 0, 6, 6, 0, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6}
+)
+
+const ip2locationCountryColumn = 2
+
+// ip2locationDB 读取 IP2Location 的 BIN 数据库
+// 文件头中记录了数据库类型、列数以及 IPv4/IPv6 数据区的位置，数据区按起始 IP 排序，每行的结束 IP 为下一行的起始 IP
+// 文件中的位置均从 1 开始计数
+type ip2locationDB struct {
+	f       *os.File
+	size    int64
+	dbType  uint8
+	columns uint8
+
+	v4Count, v4Base uint32
+	v6Count, v6Base uint32
+}
+
+func openIP2Location(path string) (*ip2locationDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	header := make([]byte, 29)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("invalid IP2Location database %s: %w", path, err)
+	}
+	db := &ip2locationDB{
+		f:       f,
+		size:    info.Size(),
+		dbType:  header[0],
+		columns: header[1],
+		v4Count: binary.LittleEndian.Uint32(header[5:]),
+		v4Base:  binary.LittleEndian.Uint32(header[9:]),
+		v6Count: binary.LittleEndian.Uint32(header[13:]),
+		v6Base:  binary.LittleEndian.Uint32(header[17:]),
+	}
+	if db.dbType == 0 || int(db.dbType) >= len(ip2locationCityColumn) || db.columns < ip2locationCountryColumn ||
+		int64(db.v4Base)+int64(db.v4Count)*int64(db.columns)*4 > db.size+1 {
+		f.Close()
+		return nil, fmt.Errorf("invalid IP2Location database %s", path)
+	}
+	return db, nil
+}
+
+func (db *ip2locationDB) close() error {
+	return db.f.Close()
+}
+
+func (db *ip2locationDB) readAt(n int, pos uint32) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := db.f.ReadAt(buf, int64(pos)-1); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readString 读取以 1 字节长度开头的字符串，pos 从 0 开始计数
+func (db *ip2locationDB) readString(pos uint32) (string, error) {
+	n, err := db.readAt(1, pos+1)
+	if err != nil {
+		return "", err
+	}
+	s, err := db.readAt(int(n[0]), pos+2)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// lookup 二分查找 ip 所在的行，返回该行除起始 IP 以外的各列
+func (db *ip2locationDB) lookup(ip net.IP) ([]byte, error) {
+	var (
+		ipNum      *big.Int
+		count      uint32
+		base       uint32
+		firstCol   uint32
+		rowSize    uint32
+		readIPFrom func(pos uint32) (*big.Int, error)
+	)
+	if ip4 := ip.To4(); ip4 != nil {
+		ipNum = new(big.Int).SetUint64(uint64(binary.BigEndian.Uint32(ip4)))
+		count, base, firstCol = db.v4Count, db.v4Base, 4
+		rowSize = uint32(db.columns) * 4
+		readIPFrom = func(pos uint32) (*big.Int, error) {
+			b, err := db.readAt(4, pos)
+			if err != nil {
+				return nil, err
+			}
+			return new(big.Int).SetUint64(uint64(binary.LittleEndian.Uint32(b))), nil
+		}
+	} else {
+		ipNum = new(big.Int).SetBytes(ip.To16())
+		count, base, firstCol = db.v6Count, db.v6Base, 16
+		rowSize = 16 + uint32(db.columns-1)*4
+		readIPFrom = func(pos uint32) (*big.Int, error) {
+			b, err := db.readAt(16, pos)
+			if err != nil {
+				return nil, err
+			}
+			// 按小端存储的 128 位整数
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+			return new(big.Int).SetBytes(b), nil
+		}
+	}
+	if count == 0 {
+		return nil, ErrNotFound
+	}
+
+	low, high := uint32(0), count-1
+	for low <= high {
+		mid := low + (high-low)/2
+		rowPos := base + mid*rowSize
+		from, err := readIPFrom(rowPos)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+		}
+		// 最后一行的结束 IP 视为无穷大
+		var to *big.Int
+		if mid+1 < count {
+			if to, err = readIPFrom(rowPos + rowSize); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+			}
+		}
+
+		switch {
+		case ipNum.Cmp(from) < 0:
+			if mid == 0 {
+				return nil, ErrNotFound
+			}
+			high = mid - 1
+		case to != nil && ipNum.Cmp(to) >= 0:
+			low = mid + 1
+		default:
+			row, err := db.readAt(int(rowSize-firstCol), rowPos+firstCol)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+			}
+			return row, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// column 返回行中第 col 列的 4 字节数据，col 从 1 开始计数且第 1 列是起始 IP
+func column(row []byte, col uint8) uint32 {
+	off := (int(col) - 2) * 4
+	return binary.LittleEndian.Uint32(row[off:])
+}
+
+func (db *ip2locationDB) result(ip net.IP) (*Result, error) {
+	row, err := db.lookup(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := column(row, ip2locationCountryColumn)
+	code, err := db.readString(ptr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+	}
+	// LITE 数据库中没有数据的地址国家码为 "-"
+	if len(code) != 2 {
+		return nil, ErrNotFound
+	}
+	name, _ := db.readString(ptr + 3)
+	res := &Result{
+		CountryCode: strings.ToLower(code),
+		CountryName: name,
+		Source:      SourceIP2Location,
+	}
+
+	var city City
+	if col := ip2locationRegionColumn[db.dbType]; col > 0 {
+		city.Subdivision, _ = db.readString(column(row, col))
+	}
+	if col := ip2locationCityColumn[db.dbType]; col > 0 {
+		city.Name, _ = db.readString(column(row, col))
+	}
+	if city.Subdivision == "-" {
+		city.Subdivision = ""
+	}
+	if city.Name == "-" {
+		city.Name = ""
+	}
+	if city != (City{}) {
+		res.City = &city
+	}
+
+	if lat, lng := ip2locationLatitudeColumn[db.dbType], ip2locationLongitudeColumn[db.dbType]; lat > 0 && lng > 0 {
+		res.Location = &Location{
+			Latitude:  float64(math.Float32frombits(column(row, lat))),
+			Longitude: float64(math.Float32frombits(column(row, lng))),
+		}
+	}
+	return res, nil
+}
+
+// ip2locationProvider 使用 IP2Location LITE 的 BIN 数据库查询，首次查询时打开文件
+type ip2locationProvider struct {
+	path string
+	log  *logger
+
+	mu      sync.RWMutex
+	loaded  bool
+	db      *ip2locationDB
+	openErr error
+}
+
+func (p *ip2locationProvider) Name() string {
+	return ProviderIP2Location
+}
+
+func (p *ip2locationProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	// 与 mmdbStore.with 相同，查询期间持有读锁，保证文件不会被 close 关闭
+	for {
+		p.mu.RLock()
+		if p.loaded {
+			break
+		}
+		p.mu.RUnlock()
+
+		p.mu.Lock()
+		if !p.loaded {
+			p.db, p.openErr = openIP2Location(p.path)
+			p.loaded = true
+			if p.openErr != nil {
+				p.log.error("failed to load IP2Location database", "path", p.path, "error", p.openErr)
+			} else {
+				p.log.info("using IP2Location database", "path", p.path, "type", fmt.Sprintf("DB%d", p.db.dbType))
+			}
+		}
+		p.mu.Unlock()
+	}
+	defer p.mu.RUnlock()
+
+	if p.openErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDBUnavailable, p.openErr)
+	}
+	return p.db.result(ip)
+}
+
+// close 关闭当前的文件，下一次查询时重新打开，用于替换数据库文件后重新加载
+func (p *ip2locationProvider) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var err error
+	if p.db != nil {
+		err = p.db.close()
+	}
+	p.db, p.openErr, p.loaded = nil, nil, false
+	return err
+}
@@ -0,0 +1,73 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeIP2LocationDB3 生成一个只有 IPv4 数据的 DB3 测试数据库：1.0.0.0/8 为澳大利亚昆士兰布里斯班，其余没有数据
+func writeIP2LocationDB3(t *testing.T) string {
+	var strs bytes.Buffer
+	const strBase = 64 + 3*16
+	str := func(values ...string) uint32 {
+		pos := uint32(strBase + strs.Len())
+		for _, v := range values {
+			strs.WriteByte(byte(len(v)))
+			strs.WriteString(v)
+		}
+		return pos
+	}
+	none := str("-", "", "-")
+	au, region, city := str("AU", "Australia"), str("Queensland"), str("Brisbane")
+
+	header := make([]byte, 64)
+	header[0], header[1] = 3, 4
+	binary.LittleEndian.PutUint32(header[5:], 3)
+	binary.LittleEndian.PutUint32(header[9:], 65)
+
+	var rows bytes.Buffer
+	for _, row := range [][4]uint32{
+		{0, none, none, none},
+		{1 << 24, au, region, city},
+		{2 << 24, none, none, none},
+	} {
+		binary.Write(&rows, binary.LittleEndian, row)
+	}
+
+	path := filepath.Join(t.TempDir(), "IP2LOCATION-LITE-DB3.BIN")
+	data := slices.Concat(header, rows.Bytes(), strs.Bytes())
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIP2Location(t *testing.T) {
+	p := &ip2locationProvider{path: writeIP2LocationDB3(t)}
+	res, err := p.Lookup(context.Background(), net.ParseIP("1.2.3.4"))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.CountryCode != "au" || res.CountryName != "Australia" || res.City == nil ||
+		res.City.Name != "Brisbane" || res.City.Subdivision != "Queensland" || res.Location != nil {
+		t.Fatalf("unexpected result: %+v %+v", res, res.City)
+	}
+	for _, ip := range []string{"0.1.2.3", "8.8.8.8", "2001:db8::1"} {
+		if _, err := p.Lookup(context.Background(), net.ParseIP(ip)); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Lookup(%s) error = %v, expected ErrNotFound", ip, err)
+		}
+	}
+
+	p.close()
+	p.path = filepath.Join(t.TempDir(), "missing.BIN")
+	if _, err := p.Lookup(context.Background(), net.ParseIP("1.2.3.4")); !errors.Is(err, ErrDBUnavailable) {
+		t.Fatalf("Lookup with missing file error = %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SourceIPAPI 表示结果来自 ip-api.com
+const SourceIPAPI = "ip-api.com"
+
+// 免费接口只支持 http，每分钟限 45 次单个查询、15 次批量查询，批量查询每次最多 100 个 IP
+const (
+	ipapiEndpoint  = "http://ip-api.com"
+	ipapiFields    = "status,message,query,continent,continentCode,country,countryCode,regionName,city,zip,lat,lon,timezone,proxy,hosting"
+	ipapiBatchSize = 100
+)
+
+// ipapiProvider 通过 ip-api.com 查询，免费且无需 token
+// 免费接口只支持明文 HTTP，查询的 IP 与结果都可能被中间人看到或篡改，因此不在默认查询链中
+type ipapiProvider struct {
+	httpFetcher
+	endpoint string
+}
+
+type ipapiResponse struct {
+	Status        string  `json:"status"`
+	Message       string  `json:"message"`
+	Query         string  `json:"query"`
+	Continent     string  `json:"continent"`
+	ContinentCode string  `json:"continentCode"`
+	Country       string  `json:"country"`
+	CountryCode   string  `json:"countryCode"`
+	RegionName    string  `json:"regionName"`
+	City          string  `json:"city"`
+	Zip           string  `json:"zip"`
+	Lat           float64 `json:"lat"`
+	Lon           float64 `json:"lon"`
+	Timezone      string  `json:"timezone"`
+	Proxy         bool    `json:"proxy"`
+	Hosting       bool    `json:"hosting"`
+}
+
+func (p *ipapiProvider) Name() string {
+	return ProviderIPAPI
+}
+
+func (p *ipapiProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	body, err := p.get(ctx, p.endpoint+"/json/"+ip.String()+"?fields="+ipapiFields)
+	if err != nil {
+		return nil, err
+	}
+	var resp ipapiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: invalid response from ip-api: %w", ErrProviderUnavailable, err)
+	}
+	return resp.toResult()
+}
+
+// LookupBatch 通过批量接口一次查询多个 IP，返回 ip.String() → 结果；查询失败的 IP 不会出现在结果中
+func (p *ipapiProvider) LookupBatch(ctx context.Context, ips []net.IP) (map[string]*Result, error) {
+	result := make(map[string]*Result, len(ips))
+	for chunk := range chunkIPs(ips, ipapiBatchSize) {
+		queries := make([]string, 0, len(chunk))
+		for _, ip := range chunk {
+			queries = append(queries, ip.String())
+		}
+		data, err := json.Marshal(queries)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/batch?fields="+ipapiFields, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		body, err := p.fetch(ctx, req)
+		if err != nil {
+			return result, err
+		}
+
+		var resps []ipapiResponse
+		if err := json.Unmarshal(body, &resps); err != nil {
+			return result, fmt.Errorf("%w: invalid response from ip-api: %w", ErrProviderUnavailable, err)
+		}
+		for _, resp := range resps {
+			if res, err := resp.toResult(); err == nil {
+				result[resp.Query] = res
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *ipapiResponse) toResult() (*Result, error) {
+	// 内网、保留地址等查不到的 IP 返回 status=fail，message 为 private range、reserved range 等
+	if r.Status != "success" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, r.Message)
+	}
+
+	res := &Result{
+		CountryCode:   strings.ToLower(r.CountryCode),
+		CountryName:   r.Country,
+		ContinentCode: strings.ToLower(r.ContinentCode),
+		ContinentName: r.Continent,
+		Timezone:      r.Timezone,
+		Source:        SourceIPAPI,
+	}
+	if r.City != "" || r.RegionName != "" || r.Zip != "" {
+		res.City = &City{Name: r.City, Subdivision: r.RegionName, PostalCode: r.Zip}
+	}
+	if r.Lat != 0 || r.Lon != 0 {
+		res.Location = &Location{Latitude: r.Lat, Longitude: r.Lon}
+	}
+	res.Privacy = &Privacy{Proxy: r.Proxy, Hosting: r.Hosting}
+	return res, nil
+}
+
+// chunkIPs 将 ips 按 size 分组，跳过 nil
+func chunkIPs(ips []net.IP, size int) func(yield func([]net.IP) bool) {
+	return func(yield func([]net.IP) bool) {
+		chunk := make([]net.IP, 0, size)
+		for _, ip := range ips {
+			if ip == nil {
+				continue
+			}
+			chunk = append(chunk, ip)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]net.IP, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
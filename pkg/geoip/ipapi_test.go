@@ -0,0 +1,88 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIPAPIProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json/1.1.1.1":
+			w.Write([]byte(`{"status":"success","query":"1.1.1.1","continentCode":"OC","country":"Australia","countryCode":"AU","city":"Sydney","lat":-33.86,"lon":151.2,"timezone":"Australia/Sydney","hosting":true}`))
+		case "/batch":
+			w.Write([]byte(`[{"status":"success","query":"8.8.8.8","countryCode":"US"},{"status":"fail","message":"reserved range","query":"240.0.0.1"}]`))
+		default:
+			w.Write([]byte(`{"status":"fail","message":"invalid query"}`))
+		}
+	}))
+	defer srv.Close()
+
+	p := &ipapiProvider{
+		httpFetcher: newTestFetcher(ProviderIPAPI, srv),
+		endpoint:    srv.URL,
+	}
+
+	res, err := p.Lookup(context.Background(), net.ParseIP("1.1.1.1"))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.CountryCode != "au" || res.ContinentCode != "oc" || res.City.Name != "Sydney" ||
+		res.Location == nil || res.Timezone != "Australia/Sydney" || !res.Privacy.Hosting || res.Source != SourceIPAPI {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	if _, err := p.Lookup(context.Background(), net.ParseIP("1.0.0.1")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected ErrNotFound", err)
+	}
+
+	batch, err := p.LookupBatch(context.Background(), []net.IP{net.ParseIP("8.8.8.8"), nil, net.ParseIP("240.0.0.1")})
+	if err != nil {
+		t.Fatalf("LookupBatch: %v", err)
+	}
+	if len(batch) != 1 || batch["8.8.8.8"].CountryCode != "us" {
+		t.Fatalf("unexpected batch result: %+v", batch)
+	}
+}
+
+func TestIPAPIChain(t *testing.T) {
+	// ip-api 使用明文 HTTP，默认查询链中不包含
+	r := New(WithDBPaths(), WithCache(NewLRUCache(10, time.Hour)))
+	for _, p := range r.Providers() {
+		if p.Name() == ProviderIPAPI {
+			t.Fatalf("default chain = %v, should not contain %s", r.Providers(), ProviderIPAPI)
+		}
+	}
+
+	var batches, singles atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/batch" {
+			batches.Add(1)
+			w.Write([]byte(`[{"status":"success","query":"8.8.8.8","countryCode":"US"},{"status":"success","query":"1.1.1.1","countryCode":"AU"}]`))
+			return
+		}
+		singles.Add(1)
+		w.Write([]byte(`{"status":"fail","message":"invalid query"}`))
+	}))
+	defer srv.Close()
+
+	// 显式启用后 LookupMany 通过批量接口查询
+	p := r.builtins[ProviderIPAPI].(*ipapiProvider)
+	p.endpoint, p.client = srv.URL, srv.Client()
+	if err := r.SetChain(ProviderIPAPI); err != nil {
+		t.Fatalf("SetChain: %v", err)
+	}
+	got := r.LookupMany([]net.IP{net.ParseIP("8.8.8.8"), net.ParseIP("1.1.1.1"), net.ParseIP("10.0.0.1")})
+	if len(got) != 2 || got["8.8.8.8"] != "us" || got["1.1.1.1"] != "au" {
+		t.Fatalf("LookupMany = %v", got)
+	}
+	if batches.Load() != 1 || singles.Load() != 0 {
+		t.Fatalf("batch requests = %d, single requests = %d, expected 1 and 0", batches.Load(), singles.Load())
+	}
+}
@@ -0,0 +1,85 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// SourceIPAPICo 表示结果来自 ipapi.co
+const SourceIPAPICo = "ipapi.co"
+
+const ipapicoEndpoint = "https://ipapi.co"
+
+// ipapicoProvider 通过 ipapi.co 查询，配置了 key 时使用付费额度
+type ipapicoProvider struct {
+	httpFetcher
+	endpoint string
+	key      string
+}
+
+type ipapicoResponse struct {
+	Error         bool     `json:"error"`
+	Reason        string   `json:"reason"`
+	Reserved      bool     `json:"reserved"`
+	City          string   `json:"city"`
+	Region        string   `json:"region"`
+	CountryCode   string   `json:"country_code"`
+	CountryName   string   `json:"country_name"`
+	ContinentCode string   `json:"continent_code"`
+	InEU          bool     `json:"in_eu"`
+	Postal        string   `json:"postal"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	Timezone      string   `json:"timezone"`
+}
+
+func (p *ipapicoProvider) Name() string {
+	return ProviderIPAPICo
+}
+
+func (p *ipapicoProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	u := p.endpoint + "/" + ip.String() + "/json/"
+	if p.key != "" {
+		u += "?key=" + url.QueryEscape(p.key)
+	}
+	body, err := p.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ipapicoResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: invalid response from ipapi.co: %w", ErrProviderUnavailable, err)
+	}
+	if resp.Error {
+		// 保留地址、无效 IP 属于查不到；额度用尽等其他错误可以稍后重试
+		if resp.Reserved || strings.Contains(resp.Reason, "Invalid") {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, resp.Reason)
+		}
+		return nil, fmt.Errorf("%w: ipapi.co: %s", ErrProviderUnavailable, resp.Reason)
+	}
+
+	res := &Result{
+		CountryCode:   strings.ToLower(resp.CountryCode),
+		CountryName:   resp.CountryName,
+		ContinentCode: strings.ToLower(resp.ContinentCode),
+		IsEU:          resp.InEU,
+		Timezone:      resp.Timezone,
+		Source:        SourceIPAPICo,
+	}
+	if resp.City != "" || resp.Region != "" || resp.Postal != "" {
+		res.City = &City{Name: resp.City, Subdivision: resp.Region, PostalCode: resp.Postal}
+	}
+	if resp.Latitude != nil && resp.Longitude != nil {
+		res.Location = &Location{Latitude: *resp.Latitude, Longitude: *resp.Longitude}
+	}
+	return res, nil
+}
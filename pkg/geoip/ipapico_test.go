@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPAPICoProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/9.9.9.9/json/":
+			w.Write([]byte(`{"ip":"9.9.9.9","city":"Berkeley","region":"California","country_code":"US","country_name":"United States","continent_code":"NA","in_eu":false,"latitude":37.87,"longitude":-122.27,"timezone":"America/Los_Angeles"}`))
+		default:
+			w.Write([]byte(`{"error":true,"reason":"Reserved IP Address","reserved":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	p := &ipapicoProvider{
+		httpFetcher: newTestFetcher(ProviderIPAPICo, srv),
+		endpoint:    srv.URL,
+		key:         "secret",
+	}
+	res, err := p.Lookup(context.Background(), net.ParseIP("9.9.9.9"))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.CountryCode != "us" || res.ContinentCode != "na" || res.City.Subdivision != "California" || res.Location == nil || res.Source != SourceIPAPICo {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if _, err := p.Lookup(context.Background(), net.ParseIP("1.0.0.1")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected ErrNotFound", err)
+	}
+
+	p.key = "wrong"
+	if _, err := p.Lookup(context.Background(), net.ParseIP("9.9.9.9")); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("Lookup error = %v, expected ErrProviderUnavailable", err)
+	}
+
+	if ps := New(WithIPAPICoKey("secret")).Providers(); ps[0].Name() != ProviderIPAPICo {
+		t.Fatalf("first provider = %s, expected %s", ps[0].Name(), ProviderIPAPICo)
+	}
+}
@@ -0,0 +1,231 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//====================
+// 3. 先用 ipinfo.io 查询
+//====================
+
+// ipinfoProvider 通过 ipinfo.io 的 /country 接口查询国家码，ASN、时区等接口也由它请求
+type ipinfoProvider struct {
+	httpFetcher
+	endpoint  string
+	full      bool        // 使用完整的 JSON 接口，一次拿到城市、ASN、主机名等信息
+	tokens    []string    // 为空时使用免费额度，多个 token 轮流使用以分摊额度
+	tokenFile *secretFile // 设置后优先使用文件中的 token，多个 token 用逗号或换行分隔
+	next      atomic.Uint32
+}
+
+const ipinfoEndpoint = "https://ipinfo.io"
+
+// url 拼接请求地址，配置了 token 时轮流附加到请求参数中
+func (p *ipinfoProvider) url(ip net.IP, path string) string {
+	u := p.endpoint + "/" + ip.String() + path
+	tokens := p.tokens
+	if p.tokenFile != nil {
+		if fileTokens := strings.FieldsFunc(p.tokenFile.get(), isTokenSeparator); len(fileTokens) > 0 {
+			tokens = fileTokens
+		}
+	}
+	if len(tokens) == 0 {
+		return u
+	}
+	token := tokens[int(p.next.Add(1)-1)%len(tokens)]
+	return u + "?token=" + url.QueryEscape(token)
+}
+
+func isTokenSeparator(r rune) bool {
+	return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t'
+}
+
+func (p *ipinfoProvider) Name() string {
+	return ProviderIPInfo
+}
+
+func (p *ipinfoProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if p.full {
+		return p.lookupFull(ctx, ip)
+	}
+
+	code, err := p.lookupCountry(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+	// code 已经是 2 位小写，如 hk、us、cn
+	return &Result{CountryCode: code, Source: SourceIPInfo}, nil
+}
+
+// ipinfo 完整 JSON 接口的响应，如 {"ip":"8.8.8.8","hostname":"dns.google","city":"Mountain View","region":"California",
+// "country":"US","loc":"37.4056,-122.0775","org":"AS15169 Google LLC","postal":"94043","timezone":"America/Los_Angeles"}
+type ipinfoFullResponse struct {
+	Hostname string `json:"hostname"`
+	City     string `json:"city"`
+	Region   string `json:"region"`
+	Country  string `json:"country"`
+	Loc      string `json:"loc"`
+	Org      string `json:"org"`
+	Postal   string `json:"postal"`
+	Timezone string `json:"timezone"`
+	Bogon    bool   `json:"bogon"`
+}
+
+// 从 ipinfo 的完整 JSON 接口获取国家、城市、坐标、ASN、主机名与时区
+func (p *ipinfoProvider) lookupFull(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	body, err := p.get(ctx, p.url(ip, "/json"))
+	if err != nil {
+		return nil, err
+	}
+	var resp ipinfoFullResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("%w: invalid response from ipinfo: %w", ErrProviderUnavailable, err)
+	}
+	if resp.Bogon || len(resp.Country) != 2 {
+		return nil, fmt.Errorf("%w: no country from ipinfo", ErrNotFound)
+	}
+
+	code := strings.ToLower(resp.Country)
+	res := &Result{
+		CountryCode: code,
+		CountryName: CountryName(code, "en"),
+		Timezone:    resp.Timezone,
+		Hostname:    resp.Hostname,
+		Source:      SourceIPInfo,
+	}
+	if resp.City != "" || resp.Region != "" || resp.Postal != "" {
+		res.City = &City{Name: resp.City, Subdivision: resp.Region, PostalCode: resp.Postal}
+	}
+	if lat, lng, ok := strings.Cut(resp.Loc, ","); ok {
+		latitude, latOK := toFloat(lat)
+		longitude, lngOK := toFloat(lng)
+		if latOK && lngOK {
+			res.Location = &Location{Latitude: latitude, Longitude: longitude}
+		}
+	}
+	if asn, err := parseASOrg(resp.Org); err == nil {
+		res.ASN = asn
+	}
+	return res, nil
+}
+
+// 通用的请求函数：请求 u，返回去掉首尾空白的纯文本响应
+func (p *ipinfoProvider) fetchText(ctx context.Context, u string) (string, error) {
+	body, err := p.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// 请求 u，返回 2 位小写国家码
+func (p *ipinfoProvider) fetchCountry(ctx context.Context, u string) (string, error) {
+	code, err := p.fetchText(ctx, u)
+	if err != nil {
+		return "", err
+	}
+
+	// 简化判断：只要不是 2 个字符，就认为失败
+	if len(code) != 2 {
+		return "", fmt.Errorf("%w: invalid country code %q from ipinfo", ErrNotFound, code)
+	}
+
+	// 统一转成小写返回
+	return strings.ToLower(code), nil
+}
+
+// 尝试从 ipinfo 获取国家代码；没有 token 时使用免费额度
+// token 从环境变量 IPINFO_TOKEN 读取，多个 token 用逗号分隔，在 docker-compose.yml 中配置
+func (p *ipinfoProvider) lookupCountry(ctx context.Context, ip net.IP) (string, error) {
+	if ip == nil {
+		return "", ErrInvalidIP
+	}
+
+	return p.fetchCountry(ctx, p.url(ip, "/country"))
+}
+
+// 从 ipinfo 的 /timezone 获取 IANA 时区
+func (p *ipinfoProvider) lookupTimezone(ctx context.Context, ip net.IP) (string, error) {
+	if ip == nil {
+		return "", ErrInvalidIP
+	}
+
+	tz, err := p.fetchText(ctx, p.url(ip, "/timezone"))
+	if err != nil {
+		return "", err
+	}
+	if _, err := time.LoadLocation(tz); tz == "" || err != nil {
+		return "", fmt.Errorf("%w: invalid timezone %q from ipinfo", ErrNotFound, tz)
+	}
+	return tz, nil
+}
+
+// 从 ipinfo 的 /org 获取 ASN，返回格式如 "AS13335 Cloudflare, Inc."
+func (p *ipinfoProvider) lookupASN(ctx context.Context, ip net.IP) (*ASN, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	org, err := p.fetchText(ctx, p.url(ip, "/org"))
+	if err != nil {
+		return nil, err
+	}
+	return parseASOrg(org)
+}
+
+// 从 ipinfo 的 /privacy 获取隐私检测信息，该接口需要付费 token
+func (p *ipinfoProvider) lookupPrivacy(ctx context.Context, ip net.IP) (*Privacy, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	body, err := p.fetchText(ctx, p.url(ip, "/privacy"))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		VPN     bool   `json:"vpn"`
+		Proxy   bool   `json:"proxy"`
+		Tor     bool   `json:"tor"`
+		Relay   bool   `json:"relay"`
+		Hosting bool   `json:"hosting"`
+		Service string `json:"service"`
+	}
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		return nil, fmt.Errorf("%w: invalid privacy response from ipinfo: %w", ErrProviderUnavailable, err)
+	}
+	return &Privacy{
+		Hosting: raw.Hosting,
+		VPN:     raw.VPN,
+		Proxy:   raw.Proxy,
+		Tor:     raw.Tor,
+		Relay:   raw.Relay,
+		Service: raw.Service,
+	}, nil
+}
+
+// parseASOrg 解析 "AS<number> <organization>" 格式的字符串
+func parseASOrg(s string) (*ASN, error) {
+	asn, org, _ := strings.Cut(s, " ")
+	if len(asn) < 3 || !strings.EqualFold(asn[:2], "AS") {
+		return nil, fmt.Errorf("%w: invalid asn %q from ipinfo", ErrNotFound, s)
+	}
+	number, err := strconv.ParseUint(asn[2:], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid asn %q from ipinfo", ErrNotFound, s)
+	}
+	return &ASN{Number: uint(number), Organization: strings.TrimSpace(org)}, nil
+}
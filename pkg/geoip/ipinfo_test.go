@@ -0,0 +1,64 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIPInfoFullJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/8.8.8.8/json":
+			w.Write([]byte(`{"ip":"8.8.8.8","hostname":"dns.google","city":"Mountain View","region":"California","country":"US","loc":"37.4056,-122.0775","org":"AS15169 Google LLC","postal":"94043","timezone":"America/Los_Angeles"}`))
+		default:
+			w.Write([]byte(`{"ip":"1.0.0.1","bogon":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	p := &ipinfoProvider{
+		httpFetcher: newTestFetcher(ProviderIPInfo, srv),
+		endpoint:    srv.URL,
+		full:        true,
+	}
+	res, err := p.Lookup(context.Background(), net.ParseIP("8.8.8.8"))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.CountryCode != "us" || res.CountryName != "United States" || res.Hostname != "dns.google" ||
+		res.ASN == nil || res.ASN.Number != 15169 || res.ASN.Organization != "Google LLC" ||
+		res.City.Name != "Mountain View" || res.Location == nil || res.Location.Latitude != 37.4056 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if _, err := p.Lookup(context.Background(), net.ParseIP("1.0.0.1")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected ErrNotFound", err)
+	}
+}
+
+func TestIPInfoTokenRotation(t *testing.T) {
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.URL.Query().Get("token"))
+		w.Write([]byte("US\n"))
+	}))
+	defer srv.Close()
+
+	p := &ipinfoProvider{
+		httpFetcher: newTestFetcher(ProviderIPInfo, srv),
+		endpoint:    srv.URL,
+		tokens:      []string{"a", "b"},
+	}
+	for range 3 {
+		if res, err := p.Lookup(context.Background(), net.ParseIP("8.8.8.8")); err != nil || res.CountryCode != "us" {
+			t.Fatalf("Lookup = %+v, %v", res, err)
+		}
+	}
+	if strings.Join(tokens, ",") != "a,b,a" {
+		t.Fatalf("tokens used = %q, expected round-robin", tokens)
+	}
+}
@@ -0,0 +1,40 @@
+package geoip
+
+import (
+	"net"
+	"net/netip"
+)
+
+// 内嵌 IPv4 地址的 IPv6 过渡地址段，查询时使用其中的 IPv4 地址
+var (
+	prefix6to4   = netip.MustParsePrefix("2002::/16")    // 6to4，第 16~47 位为 IPv4 地址
+	prefixTeredo = netip.MustParsePrefix("2001::/32")    // Teredo，最后 32 位为按位取反的客户端 IPv4 地址
+	prefixNAT64  = netip.MustParsePrefix("64:ff9b::/96") // NAT64 知名前缀，最后 32 位为 IPv4 地址
+)
+
+// normalizeIP 在查询前统一 IP 的形式：::ffff:a.b.c.d 转为 IPv4，6to4、Teredo 与 NAT64 地址转为其中内嵌的 IPv4
+// 这些地址在数据库与在线接口中都没有自己的数据，内嵌的 IPv4 才是真正的出口地址
+func normalizeIP(ip net.IP) net.IP {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return ip
+	}
+	if addr.Is4In6() || addr.Is4() {
+		return net.IP(addr.Unmap().AsSlice())
+	}
+	b := addr.As16()
+	var v4 [4]byte
+	switch {
+	case prefix6to4.Contains(addr):
+		copy(v4[:], b[2:6])
+	case prefixTeredo.Contains(addr):
+		for i := range v4 {
+			v4[i] = ^b[12+i]
+		}
+	case prefixNAT64.Contains(addr):
+		copy(v4[:], b[12:])
+	default:
+		return ip
+	}
+	return net.IP(v4[:])
+}
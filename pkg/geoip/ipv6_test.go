@@ -0,0 +1,33 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestNormalizeIP(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"::ffff:8.8.8.8", "8.8.8.8"},
+		{"2002:0808:0808::1", "8.8.8.8"},
+		{"2001:0:4136:e378:8000:63bf:f7f7:f7f7", "8.8.8.8"},
+		{"64:ff9b::808:808", "8.8.8.8"},
+		{"2001:4860:4860::8888", "2001:4860:4860::8888"},
+		{"1.1.1.1", "1.1.1.1"},
+	}
+	for _, c := range cases {
+		if got := normalizeIP(net.ParseIP(c.in)).String(); got != c.want {
+			t.Fatalf("normalizeIP(%s) = %s, want %s", c.in, got, c.want)
+		}
+	}
+
+	r := newTestResolver(t, WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "us"}}))
+	for _, ip := range []string{"fe80::1%eth0", "[2002:0808:0808::1]"} {
+		if _, err := r.LookupDetailStringContext(context.Background(), ip); err != nil && !errors.Is(err, ErrNotFound) {
+			t.Fatalf("lookup %s: %v", ip, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+)
+
+// mmdbLayers 是按优先级排列的多个 mmdb，例如国家用 ipinfo_lite、城市用 GeoLite2-City、ASN 用 GeoLite2-ASN
+// 第一个是主数据库，找不到可用的文件时回退到内置数据库；其余的是附加数据库，文件不存在时跳过
+type mmdbLayers []*mmdbStore
+
+// newMMDBLayers 创建主数据库与附加数据库，每个附加数据库只对应一个文件
+func newMMDBLayers(paths, layerPaths []string) mmdbLayers {
+	layers := mmdbLayers{newMMDBStore(paths)}
+	for _, path := range layerPaths {
+		s := newMMDBStore([]string{path})
+		s.noEmbedded = true
+		layers = append(layers, s)
+	}
+	return layers
+}
+
+// lookup 依次查询各数据库，按优先级合并字段
+func (l mmdbLayers) lookup(ip net.IP) (*Result, error) {
+	var (
+		res  *Result
+		errs []error
+	)
+	for _, s := range l {
+		layer, err := s.lookup(ip)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if res == nil {
+			res = layer
+			continue
+		}
+		res.merge(layer)
+	}
+	if res == nil {
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
+
+// paths 返回所有数据库的候选路径
+func (l mmdbLayers) paths() []string {
+	var paths []string
+	for _, s := range l {
+		paths = append(paths, s.paths...)
+	}
+	return paths
+}
+
+// reload 重新加载所有数据库，附加数据库不存在时不返回错误
+func (l mmdbLayers) reload() error {
+	var errs []error
+	for i, s := range l {
+		if err := s.reload(); err != nil && i == 0 {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l mmdbLayers) close() error {
+	var errs []error
+	for _, s := range l {
+		errs = append(errs, s.close())
+	}
+	return errors.Join(errs...)
+}
+
+// metadata 返回主数据库的信息，已加载的附加数据库放在 Layers 中
+func (l mmdbLayers) metadata() (*DBMetadata, error) {
+	md, err := l[0].metadata()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range l[1:] {
+		if layer, err := s.metadata(); err == nil {
+			md.Layers = append(md.Layers, *layer)
+		}
+	}
+	return md, nil
+}
+
+// merge 用附加数据库的结果补全 r 中为空的字段
+// 国家不一致时不使用城市、坐标等位置信息，避免拼出错误的结果；ASN 与隐私检测信息与国家无关，总是补全
+func (r *Result) merge(other *Result) {
+	if r.CountryCode == "" && other.CountryCode != "" {
+		r.CountryCode, r.CountryName, r.IsEU = other.CountryCode, other.CountryName, other.IsEU
+	}
+	if other.CountryCode == "" || other.CountryCode == r.CountryCode {
+		asn, privacy := r.ASN, r.Privacy
+		r.fill(other)
+		r.ASN, r.Privacy = asn, privacy
+	}
+	if r.ASN == nil {
+		r.ASN = other.ASN
+	}
+	if r.Privacy == nil {
+		r.Privacy = other.Privacy
+	}
+}
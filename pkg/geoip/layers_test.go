@@ -0,0 +1,33 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultMerge(t *testing.T) {
+	res := &Result{CountryCode: "us", Source: SourceExternalDB}
+	res.merge(&Result{CountryCode: "us", City: &City{Name: "Ashburn"}, Timezone: "America/New_York"})
+	res.merge(&Result{ASN: &ASN{Number: 15169, Organization: "Google LLC"}})
+	// 国家不一致时只补全 ASN 等与位置无关的字段
+	res.merge(&Result{CountryCode: "de", Location: &Location{Latitude: 50}, Privacy: &Privacy{Hosting: true}})
+
+	if res.City == nil || res.City.Name != "Ashburn" || res.Timezone != "America/New_York" ||
+		res.ASN == nil || res.ASN.Number != 15169 || res.Location != nil || res.Privacy == nil {
+		t.Fatalf("merged result = %+v", res)
+	}
+
+	empty := &Result{}
+	empty.merge(&Result{CountryCode: "fr", CountryName: "France", ContinentCode: "eu"})
+	if empty.CountryCode != "fr" || empty.ContinentCode != "eu" {
+		t.Fatalf("merge into empty result = %+v", empty)
+	}
+
+	// 附加数据库不存在时不影响查询
+	layers := newMMDBLayers(nil, []string{filepath.Join(t.TempDir(), "GeoLite2-ASN.mmdb")})
+	if _, err := layers[1].lookup(net.ParseIP("8.8.8.8")); !errors.Is(err, ErrDBUnavailable) {
+		t.Fatalf("missing layer lookup error = %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// logger 输出 geoip 的日志，设置了 WithLogger 时使用 slog，否则沿用标准库 log，格式为 NEZHA>> geoip: msg key=value
+// 为 nil 时与未设置 WithLogger 相同
+type logger struct {
+	slog *slog.Logger
+}
+
+func newLogger(l *slog.Logger) *logger {
+	return &logger{slog: l}
+}
+
+func (lg *logger) log(level slog.Level, msg string, args ...any) {
+	if lg != nil && lg.slog != nil {
+		lg.slog.Log(context.Background(), level, msg, args...)
+		return
+	}
+	var b strings.Builder
+	b.WriteString("NEZHA>> geoip: ")
+	b.WriteString(msg)
+	record := slog.NewRecord(time.Time{}, level, msg, 0)
+	record.Add(args...)
+	record.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	})
+	log.Print(b.String())
+}
+
+func (lg *logger) info(msg string, args ...any) {
+	lg.log(slog.LevelInfo, msg, args...)
+}
+
+func (lg *logger) warn(msg string, args ...any) {
+	lg.log(slog.LevelWarn, msg, args...)
+}
+
+func (lg *logger) error(msg string, args ...any) {
+	lg.log(slog.LevelError, msg, args...)
+}
+
+// verbose 只在设置了 WithLogger 时输出，如每次查询时数据源的失败，默认的日志中不输出这些信息
+func (lg *logger) verbose(level slog.Level, msg string, args ...any) {
+	if lg != nil && lg.slog != nil {
+		lg.slog.Log(context.Background(), level, msg, args...)
+	}
+}
+
+// levelForError 返回数据源失败时 verbose 日志的级别，查不到数据是正常情况，只在 Debug 级别输出
+func levelForError(err error) slog.Level {
+	if errors.Is(err, ErrNotFound) {
+		return slog.LevelDebug
+	}
+	return slog.LevelWarn
+}
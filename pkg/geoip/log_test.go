@@ -0,0 +1,22 @@
+package geoip
+
+import (
+	"bytes"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r := newTestResolver(t,
+		WithProviders(&staticProvider{name: "failing", err: ErrProviderUnavailable}, &staticProvider{name: "static", res: &Result{CountryCode: "fr"}}),
+		WithLogger(l),
+	)
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "provider=failing") {
+		t.Fatalf("provider failure not logged: %s", out)
+	}
+}
@@ -0,0 +1,116 @@
+package geoip
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// lookupStatsBuckets 是滑动窗口划分的桶数，窗口为 1 小时时每个桶为 1 分钟
+const lookupStatsBuckets = 60
+
+// 统计中没有国家码或 ASN 时使用的键
+const (
+	StatsUnknown = "unknown" // 查不到或所有数据源都失败
+	StatsPrivate = "private" // 内网或保留地址
+)
+
+// LookupStats 是最近一段时间内查询结果的分布，更换数据源或离线库后大量服务器变成 unknown 时可以及时发现
+type LookupStats struct {
+	Enabled   bool              `json:"enabled"`
+	Window    time.Duration     `json:"window"`
+	Total     uint64            `json:"total"`
+	Countries map[string]uint64 `json:"countries"` // 国家码（以洲码兜底）→ 查询次数，包括 unknown 与 private
+	ASNs      map[string]uint64 `json:"asns"`      // 如 AS13335 → 查询次数，结果中没有 ASN 时计入 unknown
+}
+
+type lookupStatsBucket struct {
+	index     int64 // 桶的序号，与当前序号相差超过桶数时已过期
+	total     uint64
+	countries map[string]uint64
+	asns      map[string]uint64
+}
+
+// lookupStats 按时间分桶统计每次查询的结果，包括缓存命中
+type lookupStats struct {
+	window time.Duration
+	width  time.Duration // 每个桶的时长
+
+	mu      sync.Mutex
+	buckets [lookupStatsBuckets]lookupStatsBucket
+}
+
+// newLookupStats 创建查询结果统计，window <= 0 时返回 nil，不统计
+func newLookupStats(window time.Duration) *lookupStats {
+	if window <= 0 {
+		return nil
+	}
+	return &lookupStats{window: window, width: max(window/lookupStatsBuckets, time.Second)}
+}
+
+// record 记录一次查询的结果，传入的 IP 无效时不计入
+func (s *lookupStats) record(res *Result, err error) {
+	if s == nil || errors.Is(err, ErrInvalidIP) {
+		return
+	}
+	country, asn := StatsUnknown, StatsUnknown
+	switch {
+	case err != nil:
+	case res.Private:
+		country, asn = StatsPrivate, StatsPrivate
+	default:
+		if code := res.Code(); code != "" {
+			country = code
+		}
+		if res.ASN != nil && res.ASN.Number != 0 {
+			asn = "AS" + strconv.FormatUint(uint64(res.ASN.Number), 10)
+		}
+	}
+
+	index := time.Now().UnixNano() / int64(s.width)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := &s.buckets[index%lookupStatsBuckets]
+	if b.index != index || b.countries == nil {
+		*b = lookupStatsBucket{index: index, countries: make(map[string]uint64), asns: make(map[string]uint64)}
+	}
+	b.total++
+	b.countries[country]++
+	b.asns[asn]++
+}
+
+// snapshot 汇总窗口内所有未过期的桶
+func (s *lookupStats) snapshot() LookupStats {
+	if s == nil {
+		return LookupStats{}
+	}
+	out := LookupStats{Enabled: true, Window: s.window, Countries: make(map[string]uint64), ASNs: make(map[string]uint64)}
+	index := time.Now().UnixNano() / int64(s.width)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.countries == nil || index-b.index >= lookupStatsBuckets {
+			continue
+		}
+		out.Total += b.total
+		for k, v := range b.countries {
+			out.Countries[k] += v
+		}
+		for k, v := range b.asns {
+			out.ASNs[k] += v
+		}
+	}
+	return out
+}
+
+// RecentLookups 返回 WithLookupStats 设置的时间窗口内查询结果按国家与 ASN 的分布，未开启时 Enabled 为 false
+func (r *Resolver) RecentLookups() LookupStats {
+	return r.lookupStats.snapshot()
+}
+
+// RecentLookups 返回默认实例最近的查询结果分布，见 Resolver.RecentLookups
+func RecentLookups() LookupStats {
+	return Default().RecentLookups()
+}
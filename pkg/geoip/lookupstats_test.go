@@ -0,0 +1,26 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLookupStats(t *testing.T) {
+	r := newTestResolver(t,
+		WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "fr", ASN: &ASN{Number: 13335}}}),
+		WithLookupStats(time.Hour),
+	)
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("10.0.0.1"))
+	r.Lookup(nil)
+
+	stats := r.RecentLookups()
+	if !stats.Enabled || stats.Total != 3 || stats.Countries["fr"] != 2 || stats.Countries[StatsPrivate] != 1 || stats.ASNs["AS13335"] != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if New(WithProviders()).RecentLookups().Enabled {
+		t.Fatal("stats enabled by default")
+	}
+}
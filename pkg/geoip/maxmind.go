@@ -0,0 +1,160 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SourceMaxMind 表示结果来自 MaxMind GeoIP2 Precision 网络服务
+const SourceMaxMind = "maxmind"
+
+const maxmindEndpoint = "https://geoip.maxmind.com/geoip/v2.1"
+
+// maxmindProvider 通过 MaxMind GeoIP2 Precision 网络服务查询，需要商业账号
+type maxmindProvider struct {
+	httpFetcher
+	endpoint   string
+	accountID  string
+	licenseKey string
+	keyFile    *secretFile // 设置后优先使用文件中的 license key
+	service    string      // country、city 或 insights
+}
+
+// maxmindNames 是 MaxMind 数据中的多语言名称
+type maxmindNames map[string]string
+
+// en 返回英文名称
+func (n maxmindNames) en() string {
+	return n["en"]
+}
+
+// maxmindCountry 是 MaxMind 数据中的国家
+type maxmindCountry struct {
+	ISOCode           string       `json:"iso_code" maxminddb:"iso_code"`
+	Names             maxmindNames `json:"names" maxminddb:"names"`
+	IsInEuropeanUnion bool         `json:"is_in_european_union" maxminddb:"is_in_european_union"`
+}
+
+// maxmindRecord 是 GeoIP2 网络服务返回的数据结构，与 GeoLite2/GeoIP2 mmdb 中的记录结构相同
+type maxmindRecord struct {
+	Continent struct {
+		Code  string       `json:"code" maxminddb:"code"`
+		Names maxmindNames `json:"names" maxminddb:"names"`
+	} `json:"continent" maxminddb:"continent"`
+	Country maxmindCountry `json:"country" maxminddb:"country"`
+	// 注册地所在的国家，部分 Anycast 等地址没有 country 时使用
+	RegisteredCountry maxmindCountry `json:"registered_country" maxminddb:"registered_country"`
+
+	// 以下为城市级数据，GeoLite2-City/GeoIP2-City 中才有
+	City struct {
+		Names maxmindNames `json:"names" maxminddb:"names"`
+	} `json:"city" maxminddb:"city"`
+	Subdivisions []struct {
+		Names maxmindNames `json:"names" maxminddb:"names"`
+	} `json:"subdivisions" maxminddb:"subdivisions"`
+	Postal struct {
+		Code string `json:"code" maxminddb:"code"`
+	} `json:"postal" maxminddb:"postal"`
+	Location *struct {
+		Latitude       float64 `json:"latitude" maxminddb:"latitude"`
+		Longitude      float64 `json:"longitude" maxminddb:"longitude"`
+		AccuracyRadius uint16  `json:"accuracy_radius" maxminddb:"accuracy_radius"`
+		TimeZone       string  `json:"time_zone" maxminddb:"time_zone"`
+	} `json:"location" maxminddb:"location"`
+
+	// GeoLite2-ASN 数据库中的字段
+	ASNumber       uint   `json:"-" maxminddb:"autonomous_system_number"`
+	ASOrganization string `json:"-" maxminddb:"autonomous_system_organization"`
+
+	Traits struct {
+		Network            string `json:"network"`
+		IsAnonymousVPN     bool   `json:"is_anonymous_vpn"`
+		IsHostingProvider  bool   `json:"is_hosting_provider"`
+		IsPublicProxy      bool   `json:"is_public_proxy"`
+		IsResidentialProxy bool   `json:"is_residential_proxy"`
+		IsTorExitNode      bool   `json:"is_tor_exit_node"`
+	} `json:"traits"`
+}
+
+func (p *maxmindProvider) Name() string {
+	return ProviderMaxMind
+}
+
+func (p *maxmindProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"/"+p.service+"/"+ip.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	key := p.licenseKey
+	if fileKey := p.keyFile.get(); fileKey != "" {
+		key = fileKey
+	}
+	req.SetBasicAuth(p.accountID, key)
+	req.Header.Set("Accept", "application/json")
+
+	body, err := p.fetch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var record maxmindRecord
+	if err := json.Unmarshal(body, &record); err != nil {
+		return nil, fmt.Errorf("%w: invalid response from maxmind: %w", ErrProviderUnavailable, err)
+	}
+	return record.toResult(SourceMaxMind), nil
+}
+
+func (r *maxmindRecord) toResult(source string) *Result {
+	country := r.Country
+	if country.ISOCode == "" {
+		country = r.RegisteredCountry
+	}
+	res := &Result{
+		CountryCode:   strings.ToLower(country.ISOCode),
+		CountryName:   country.Names.en(),
+		IsEU:          country.IsInEuropeanUnion,
+		ContinentCode: strings.ToLower(r.Continent.Code),
+		ContinentName: r.Continent.Names.en(),
+		Network:       r.Traits.Network,
+		Source:        source,
+	}
+
+	city := City{Name: r.City.Names.en(), PostalCode: r.Postal.Code}
+	if len(r.Subdivisions) > 0 {
+		city.Subdivision = r.Subdivisions[0].Names.en()
+	}
+	if city != (City{}) {
+		res.City = &city
+	}
+
+	if r.Location != nil {
+		res.Location = &Location{
+			Latitude:       r.Location.Latitude,
+			Longitude:      r.Location.Longitude,
+			AccuracyRadius: r.Location.AccuracyRadius,
+		}
+		res.Timezone = r.Location.TimeZone
+	}
+
+	if r.ASNumber > 0 {
+		res.ASN = &ASN{Number: r.ASNumber, Organization: r.ASOrganization}
+	}
+
+	t := r.Traits
+	if t.IsAnonymousVPN || t.IsHostingProvider || t.IsPublicProxy || t.IsResidentialProxy || t.IsTorExitNode {
+		res.Privacy = &Privacy{
+			Hosting: t.IsHostingProvider,
+			VPN:     t.IsAnonymousVPN,
+			Proxy:   t.IsPublicProxy || t.IsResidentialProxy,
+			Tor:     t.IsTorExitNode,
+		}
+	}
+	return res
+}
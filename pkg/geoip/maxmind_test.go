@@ -0,0 +1,81 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaxMindProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "42" || pass != "license" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/city/81.2.69.142" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"IP_ADDRESS_NOT_FOUND"}`))
+			return
+		}
+		w.Write([]byte(`{"continent":{"code":"EU","names":{"en":"Europe"}},"country":{"iso_code":"GB","names":{"en":"United Kingdom"}},"city":{"names":{"en":"London"}},"subdivisions":[{"names":{"en":"England"}}],"location":{"latitude":51.5,"longitude":-0.1,"accuracy_radius":10,"time_zone":"Europe/London"},"traits":{"network":"81.2.69.0/24","is_hosting_provider":true}}`))
+	}))
+	defer srv.Close()
+
+	p := &maxmindProvider{
+		httpFetcher: newTestFetcher(ProviderMaxMind, srv),
+		endpoint:    srv.URL,
+		accountID:   "42",
+		licenseKey:  "license",
+		service:     "city",
+	}
+	res, err := p.Lookup(context.Background(), net.ParseIP("81.2.69.142"))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if res.CountryCode != "gb" || res.ContinentName != "Europe" || res.City.Name != "London" || res.City.Subdivision != "England" ||
+		res.Timezone != "Europe/London" || res.Network != "81.2.69.0/24" || !res.Privacy.Hosting {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if _, err := p.Lookup(context.Background(), net.ParseIP("1.0.0.1")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup error = %v, expected ErrNotFound", err)
+	}
+
+	if ps := New(WithMaxMind("42", "license", "")).Providers(); ps[0].Name() != ProviderMaxMind {
+		t.Fatalf("first provider = %s, expected %s", ps[0].Name(), ProviderMaxMind)
+	}
+}
+
+func TestMaxMindRecordFallback(t *testing.T) {
+	var record maxmindRecord
+	record.RegisteredCountry.ISOCode = "US"
+	record.RegisteredCountry.Names = maxmindNames{"en": "United States"}
+	if res := record.toResult(SourceExternalDB); res.CountryCode != "us" || res.CountryName != "United States" {
+		t.Fatalf("toResult = %+v, expected registered country", res)
+	}
+
+	record.City.Names = maxmindNames{"en": "Ashburn"}
+	record.Subdivisions = append(record.Subdivisions, struct {
+		Names maxmindNames `json:"names" maxminddb:"names"`
+	}{Names: maxmindNames{"en": "Virginia"}})
+	if res := record.toResult(SourceExternalDB); res.City == nil || res.City.Name != "Ashburn" || res.City.Subdivision != "Virginia" {
+		t.Fatalf("toResult city = %+v", res.City)
+	}
+	if !isCityLevel("GeoLite2-City") || isCityLevel("GeoLite2-Country") {
+		t.Fatal("isCityLevel mismatch")
+	}
+
+	for dbType, want := range map[string]bool{
+		"GeoLite2-Country":  true,
+		"GeoIP2-City":       true,
+		"DBIP-Country-Lite": true,
+		"ipinfo_lite.mmdb":  false,
+		"GeoIP-Legacy":      false,
+	} {
+		if got := isMaxMindFormat(dbType); got != want {
+			t.Errorf("isMaxMindFormat(%q) = %v, want %v", dbType, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package geoip
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics 是一个 Resolver 的 Prometheus 指标，通过 Resolver.Collector 注册到面板的 /metrics
+type metrics struct {
+	r *Resolver
+
+	lookups     *prometheus.CounterVec   // 按数据来源统计查到的结果，缓存命中时按结果原来的来源统计
+	errors      *prometheus.CounterVec   // 按数据源统计失败的请求，ErrNotFound 不计入
+	latency     *prometheus.HistogramVec // 按数据源统计每次请求的耗时
+	cacheHits   prometheus.Counter
+	cacheMisses prometheus.Counter
+	circuitOpen *prometheus.Desc // 采集时从各数据源读取熔断状态
+}
+
+func newMetrics(r *Resolver) *metrics {
+	return &metrics{
+		r: r,
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nezha", Subsystem: "geoip", Name: "lookups_total",
+			Help: "Number of resolved lookups by result source.",
+		}, []string{"source"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nezha", Subsystem: "geoip", Name: "provider_errors_total",
+			Help: "Number of failed provider requests, not counting IPs without data.",
+		}, []string{"provider"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "nezha", Subsystem: "geoip", Name: "provider_duration_seconds",
+			Help:    "Duration of provider requests, including retries.",
+			Buckets: []float64{.0005, .001, .005, .01, .05, .1, .25, .5, 1, 2, 5},
+		}, []string{"provider"}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nezha", Subsystem: "geoip", Name: "cache_hits_total",
+			Help: "Number of lookups answered by the result cache.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nezha", Subsystem: "geoip", Name: "cache_misses_total",
+			Help: "Number of lookups not found in the result cache.",
+		}),
+		circuitOpen: prometheus.NewDesc("nezha_geoip_circuit_open",
+			"Whether the circuit breaker of an online provider is open (1) or closed (0).",
+			[]string{"provider"}, nil),
+	}
+}
+
+// observe 记录一次数据源请求
+func (m *metrics) observe(provider string, d time.Duration, err error) {
+	m.latency.WithLabelValues(provider).Observe(d.Seconds())
+	if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrInvalidIP) {
+		m.errors.WithLabelValues(provider).Inc()
+	}
+}
+
+// cacheLookup 记录一次缓存查询
+func (m *metrics) cacheLookup(hit bool) {
+	if hit {
+		m.cacheHits.Inc()
+	} else {
+		m.cacheMisses.Inc()
+	}
+}
+
+// breakerProvider 由内嵌 httpFetcher 的数据源实现，用于导出熔断状态
+type breakerProvider interface {
+	circuitOpen() bool
+}
+
+func (f *httpFetcher) circuitOpen() bool {
+	return f.breaker.open()
+}
+
+func (m *metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.lookups.Describe(ch)
+	m.errors.Describe(ch)
+	m.latency.Describe(ch)
+	m.cacheHits.Describe(ch)
+	m.cacheMisses.Describe(ch)
+	ch <- m.circuitOpen
+}
+
+func (m *metrics) Collect(ch chan<- prometheus.Metric) {
+	m.lookups.Collect(ch)
+	m.errors.Collect(ch)
+	m.latency.Collect(ch)
+	m.cacheHits.Collect(ch)
+	m.cacheMisses.Collect(ch)
+	for _, p := range m.r.chain() {
+		b, ok := p.(breakerProvider)
+		if !ok {
+			continue
+		}
+		var open float64
+		if b.circuitOpen() {
+			open = 1
+		}
+		ch <- prometheus.MustNewConstMetric(m.circuitOpen, prometheus.GaugeValue, open, p.Name())
+	}
+}
+
+// Collector 返回 Resolver 的 Prometheus 指标，包括各数据来源的查询数、数据源的错误数与耗时、缓存命中数和熔断状态
+// 同一个 Registry 只能注册一次
+func (r *Resolver) Collector() prometheus.Collector {
+	return r.metrics
+}
+
+// Collector 返回默认实例的 Prometheus 指标，见 Resolver.Collector
+func Collector() prometheus.Collector {
+	return Default().Collector()
+}
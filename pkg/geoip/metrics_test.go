@@ -0,0 +1,37 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	ok := &staticProvider{name: "static", res: &Result{CountryCode: "fr", Source: "static"}}
+	failing := &staticProvider{name: "failing", err: ErrProviderUnavailable}
+	r := New(WithProviders(failing, ok), WithCache(NewLRUCache(10, time.Hour)))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+
+	cases := []struct {
+		name   string
+		metric prometheus.Collector
+		value  float64
+	}{
+		{"lookups", r.metrics.lookups.WithLabelValues("static"), 2},
+		{"errors", r.metrics.errors.WithLabelValues("failing"), 1},
+		{"cache hits", r.metrics.cacheHits, 1},
+		{"cache misses", r.metrics.cacheMisses, 1},
+	}
+	for _, c := range cases {
+		if v := testutil.ToFloat64(c.metric); v != c.value {
+			t.Fatalf("%s = %v, expected %v", c.name, v, c.value)
+		}
+	}
+	if n := testutil.CollectAndCount(r.metrics.latency); n != 2 {
+		t.Fatalf("latency series = %d, expected 2", n)
+	}
+}
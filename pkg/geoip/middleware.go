@@ -0,0 +1,111 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Client 是 Middleware 写入请求 context 的客户端信息
+type Client struct {
+	IP      netip.Addr
+	Country string  // 2 位小写国家码，内网地址或查不到时为空
+	Result  *Result // 查询失败时为空
+}
+
+type clientKey struct{}
+
+// ClientFromContext 返回 Middleware 写入 ctx 的客户端信息
+func ClientFromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(*Client)
+	return c, ok
+}
+
+// Middleware 返回 net/http 中间件，通过 RealIP 解析客户端的真实 IP 并查询国家码，通过 ClientFromContext 读取
+// 只有直连地址属于 trusted 时才读取 X-Forwarded-For 与 X-Real-IP，否则任何人都可以伪造这两个请求头
+// 查询失败不会中断请求，查询不经过反向解析
+func (r *Resolver) Middleware(trusted ...netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			c := &Client{IP: RealIP(req, trusted)}
+			if c.IP.IsValid() {
+				if res, err := r.lookupDetail(req.Context(), net.IP(c.IP.AsSlice())); err == nil {
+					c.Result = res
+					if !res.Private {
+						c.Country = res.Code()
+					}
+				}
+			}
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), clientKey{}, c)))
+		})
+	}
+}
+
+// RealIP 返回请求的客户端地址：直连地址不属于 trustedCIDRs 时直接使用直连地址，
+// 否则从右往左跳过 X-Forwarded-For 中属于 trustedCIDRs 的代理，取第一个不受信任的地址；
+// 没有 X-Forwarded-For 时使用 X-Real-IP，都无法解析时退回直连地址
+func RealIP(r *http.Request, trustedCIDRs []netip.Prefix) netip.Addr {
+	return ForwardedIP(remoteAddr(r.RemoteAddr), r.Header.Values("X-Forwarded-For"), r.Header.Get("X-Real-IP"), trustedCIDRs)
+}
+
+// ForwardedIP 与 RealIP 相同，但直接接受直连地址与请求头的值，供 gRPC metadata 等不是 http.Request 的场景使用
+// forwardedFor 是所有 X-Forwarded-For 请求头的值，按顺序拼接
+func ForwardedIP(remote netip.Addr, forwardedFor []string, realIP string, trustedCIDRs []netip.Prefix) netip.Addr {
+	remote = remote.WithZone("").Unmap()
+	if !remote.IsValid() || !isTrusted(remote, trustedCIDRs) {
+		return remote
+	}
+
+	var hops []string
+	for _, v := range forwardedFor {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	var leftmost netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := parseAddr(hops[i])
+		if err != nil {
+			// 无法解析的地址之前的部分都不可信
+			break
+		}
+		if !isTrusted(addr, trustedCIDRs) {
+			return addr
+		}
+		leftmost = addr
+	}
+	if leftmost.IsValid() {
+		// 所有地址都是受信任的代理，客户端本身就在内网里
+		return leftmost
+	}
+	if addr, err := parseAddr(realIP); err == nil {
+		return addr
+	}
+	return remote
+}
+
+// remoteAddr 解析 http.Request.RemoteAddr，兼容没有端口的形式
+func remoteAddr(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := parseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware 使用默认实例，见 Resolver.Middleware
+func Middleware(trusted ...netip.Prefix) func(http.Handler) http.Handler {
+	return Default().Middleware(trusted...)
+}
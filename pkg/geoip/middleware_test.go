@@ -0,0 +1,74 @@
+package geoip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	r := newTestResolver(t, WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "jp"}}))
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+	cases := []struct {
+		remote, xff, realIP string
+		want                string
+	}{
+		{"8.8.8.8:1234", "1.1.1.1", "", "8.8.8.8"},                     // 不受信任的直连地址不读取请求头
+		{"10.0.0.1:1234", "1.1.1.1, 9.9.9.9, 10.0.0.2", "", "9.9.9.9"}, // 跳过受信任的代理
+		{"10.0.0.1:1234", "10.0.0.3, 10.0.0.2", "", "10.0.0.3"},        // 都是代理时取最左边
+		{"10.0.0.1:1234", "", "1.1.1.1", "1.1.1.1"},                    // 没有 X-Forwarded-For 时使用 X-Real-IP
+		{"[::1]:1234", "garbage", "", "::1"},                           // 都无法解析时退回直连地址
+		{"10.0.0.1:1234", "::ffff:1.1.1.1", "", "1.1.1.1"},
+	}
+	for _, c := range cases {
+		var got *Client
+		h := r.Middleware(trusted...)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			got, _ = ClientFromContext(req.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		if c.realIP != "" {
+			req.Header.Set("X-Real-IP", c.realIP)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if got == nil || got.IP.String() != c.want {
+			t.Fatalf("%+v: client = %+v, want %s", c, got, c.want)
+		}
+		wantCountry := "jp"
+		if got.IP.IsPrivate() || got.IP.IsLoopback() {
+			wantCountry = ""
+		}
+		if got.Country != wantCountry {
+			t.Fatalf("%+v: country = %q, want %q", c, got.Country, wantCountry)
+		}
+	}
+}
+
+func TestForwardedIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.1/32"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	cases := []struct {
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"192.168.1.1", []string{"1.1.1.1", "10.0.0.5"}, "1.1.1.1"}, // 多个请求头按顺序拼接
+		{"fd00::1", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"::ffff:10.0.0.1", []string{"8.8.8.8"}, "8.8.8.8"},
+		{"192.168.1.2", []string{"8.8.8.8"}, "192.168.1.2"},
+	}
+	for _, c := range cases {
+		got := ForwardedIP(netip.MustParseAddr(c.remote), c.xff, "", trusted)
+		if got.String() != c.want {
+			t.Fatalf("ForwardedIP(%s, %v) = %s, want %s", c.remote, c.xff, got, c.want)
+		}
+	}
+}
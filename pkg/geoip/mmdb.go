@@ -0,0 +1,549 @@
+package geoip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+//====================
+// 1. 内置 geoip.db
+//====================
+
+// 内置数据库见 embed.go，使用 nogeoipembed 编译时为空
+var (
+	embeddedOnce sync.Once
+	embeddedDB   []byte
+	embeddedErr  error
+)
+
+// loadEmbeddedDB 解压内置数据库，解压后的数据在进程内只保留一份，reload 时复用
+func loadEmbeddedDB() ([]byte, error) {
+	embeddedOnce.Do(func() {
+		if len(embeddedDBGz) == 0 {
+			embeddedErr = errors.New("the embedded database is excluded by the nogeoipembed build tag")
+			return
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(embeddedDBGz))
+		if err != nil {
+			embeddedErr = fmt.Errorf("decompress embedded database: %w", err)
+			return
+		}
+		defer gz.Close()
+		if embeddedDB, err = io.ReadAll(gz); err != nil {
+			embeddedErr = fmt.Errorf("decompress embedded database: %w", err)
+		}
+	})
+	return embeddedDB, embeddedErr
+}
+
+//====================
+// 2. 外部 ipinfo_lite.mmdb
+//====================
+
+// 容器内部的路径：对应宿主机 /opt/nezha/dashboard/data/ipinfo_lite.mmdb
+// 非 docker 部署可以通过 GEOIP_DB_PATH 环境变量或 WithDBPaths 指定其他路径
+const externalDBPath = "/dashboard/data/ipinfo_lite.mmdb"
+
+// defaultDBPaths 是默认的候选数据库，按顺序使用第一个可以打开的文件，不需要把下载的数据库改名为 ipinfo_lite.mmdb
+var defaultDBPaths = []string{
+	externalDBPath,
+	"/dashboard/data/GeoLite2-City.mmdb",
+	"/dashboard/data/GeoLite2-Country.mmdb",
+	"/dashboard/data/dbip-country-lite.mmdb",
+}
+
+// mmdbStore 管理一个 Resolver 使用的 mmdb，首次查询时按 paths 顺序选择第一个可用的文件，都不可用时回退到内置数据库
+type mmdbStore struct {
+	paths      []string
+	noEmbedded bool // 不回退到内置数据库，用于附加数据库
+	fullVerify bool // 打开时完整校验搜索树与数据区，见 verifyReader
+	inMemory   bool // 将文件完整读入内存，而不是 mmap
+	maxAge     time.Duration
+	log        *logger
+
+	mu        sync.RWMutex
+	loaded    bool
+	reader    *maxminddb.Reader
+	source    string
+	path      string // 内置数据库时为空
+	initErr   error
+	lookupErr error // 包装为 ErrDBUnavailable 的 initErr，可选的数据库不存在时不需要每次查询都生成错误
+
+	decoded     sync.Map // 数据区偏移 → 已解码的 *Result，同一条记录只解码一次
+	decodedSize atomic.Int64
+}
+
+// maxDecodedRecords 是每个数据库缓存的已解码记录数上限
+// 国家库只有几百条不同的记录，城市库的记录较多，超过上限时清空重新缓存
+const maxDecodedRecords = 65536
+
+// errNoDatabase 表示所有候选路径都没有可用的数据库
+var errNoDatabase = errors.New("no database found")
+
+func newMMDBStore(paths []string) *mmdbStore {
+	cleaned := make([]string, len(paths))
+	for i, path := range paths {
+		cleaned[i] = filepath.Clean(path)
+	}
+	return &mmdbStore{paths: cleaned}
+}
+
+// open 按优先级选择并打开数据库，返回 reader、数据来源与文件路径
+func (s *mmdbStore) open() (*maxminddb.Reader, string, string, error) {
+	// 优先尝试外部 mmdb
+	for _, path := range s.paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		reader, err := s.openFile(path)
+		if err == nil {
+			if err = verifyReader(reader, path, s.fullVerify, s.log); err == nil {
+				return reader, SourceExternalDB, path, nil
+			}
+			reader.Close()
+		}
+		// 如果打开失败，就继续尝试下一个，最终用内置的 embeddedDB
+		s.log.warn("skipping invalid database", "path", path, "error", err)
+	}
+
+	if s.noEmbedded {
+		return nil, "", "", fmt.Errorf("%w in %v", errNoDatabase, s.paths)
+	}
+
+	// 外部文件不存在或失败 → 回退到内置 geoip.db
+	data, err := loadEmbeddedDB()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("no external database found in %v: %w", s.paths, err)
+	}
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return reader, SourceEmbeddedDB, "", nil
+}
+
+// openFile 默认使用 mmap 打开文件，只有查询到的页才会读入内存，适合内存较小的机器
+// inMemory 时一次性读入内存，避免数据目录在 NFS 等网络存储上时查询因缺页而卡顿
+func (s *mmdbStore) openFile(path string) (*maxminddb.Reader, error) {
+	if !s.inMemory {
+		return maxminddb.Open(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return maxminddb.FromBytes(data)
+}
+
+// logLoaded 记录当前选中的数据库，调用方需持有写锁
+func (s *mmdbStore) logLoaded() {
+	// 可选的数据库不存在是正常情况，不记录日志
+	if errors.Is(s.initErr, errNoDatabase) {
+		return
+	}
+	if s.initErr != nil {
+		s.log.error("failed to load database", "error", s.initErr)
+		return
+	}
+	name := s.source
+	if s.path != "" {
+		name = s.path
+	}
+	built := time.Unix(int64(s.reader.Metadata.BuildEpoch), 0)
+	s.log.info("using database", "database", name, "type", s.reader.Metadata.DatabaseType, "built", built.Format(time.DateOnly))
+	if s.isStale(built) {
+		s.log.warn("database is outdated, consider updating it", "database", name, "age_days", int(time.Since(built).Hours()/24))
+	}
+}
+
+// with 首次调用时加载数据库，并在持有读锁的情况下调用 fn，保证查询期间 reader 不会被 reload/close 关闭
+func (s *mmdbStore) with(fn func(db *maxminddb.Reader, source string) error) error {
+	for {
+		s.mu.RLock()
+		if s.loaded {
+			break
+		}
+		s.mu.RUnlock()
+
+		s.mu.Lock()
+		if !s.loaded {
+			s.reader, s.source, s.path, s.initErr = s.open()
+			s.loaded = true
+			if s.initErr != nil {
+				s.lookupErr = fmt.Errorf("%w: %w", ErrDBUnavailable, s.initErr)
+			}
+			s.logLoaded()
+		}
+		s.mu.Unlock()
+	}
+	defer s.mu.RUnlock()
+
+	if s.initErr != nil {
+		return s.lookupErr
+	}
+	return fn(s.reader, s.source)
+}
+
+// reload 重新选择并打开数据库，新数据库打开失败时继续使用原来的数据库
+func (s *mmdbStore) reload() error {
+	reader, source, path, err := s.open()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+	}
+
+	s.mu.Lock()
+	old := s.reader
+	s.reader, s.source, s.path, s.initErr, s.lookupErr = reader, source, path, nil, nil
+	s.loaded = true
+	s.resetDecoded()
+	s.logLoaded()
+	s.mu.Unlock()
+
+	// 写锁释放前已没有查询持有旧的 reader
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// close 关闭当前的数据库，之后的查询会重新加载数据库
+func (s *mmdbStore) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.reader != nil {
+		err = s.reader.Close()
+	}
+	s.reader, s.source, s.path, s.initErr, s.lookupErr = nil, "", "", nil, nil
+	s.loaded = false
+	s.resetDecoded()
+	return err
+}
+
+// DBMetadata 描述当前使用的 mmdb
+type DBMetadata struct {
+	Source       string    `json:"source"`         // SourceExternalDB 或 SourceEmbeddedDB
+	Path         string    `json:"path,omitempty"` // 外部数据库的文件路径，内置数据库为空
+	DatabaseType string    `json:"database_type"`  // 如 ipinfo_lite.mmdb、GeoLite2-Country
+	CityLevel    bool      `json:"city_level"`     // 是否为包含城市、坐标的城市级数据库
+	Description  string    `json:"description,omitempty"`
+	BuildTime    time.Time `json:"build_time"`
+	IPVersion    uint      `json:"ip_version"`
+	Languages    []string  `json:"languages,omitempty"`
+	NodeCount    uint      `json:"node_count"` // 搜索树节点数，可近似反映记录规模
+	RecordSize   uint      `json:"record_size"`
+	Stale        bool      `json:"stale"` // 构建时间已超过 WithMaxDBAge 设置的时长
+
+	Layers []DBMetadata `json:"layers,omitempty"` // 通过 WithDBLayers 加载的附加数据库
+	ASN    *DBMetadata  `json:"asn,omitempty"`    // 已加载的 ASN 数据库
+}
+
+func (s *mmdbStore) metadata() (*DBMetadata, error) {
+	var md *DBMetadata
+	err := s.with(func(db *maxminddb.Reader, source string) error {
+		md = readerMetadata(db, source, s.path)
+		md.Stale = s.isStale(md.BuildTime)
+		return nil
+	})
+	return md, err
+}
+
+func readerMetadata(db *maxminddb.Reader, source, path string) *DBMetadata {
+	return &DBMetadata{
+		Source:       source,
+		Path:         path,
+		DatabaseType: db.Metadata.DatabaseType,
+		CityLevel:    isCityLevel(db.Metadata.DatabaseType),
+		Description:  db.Metadata.Description["en"],
+		BuildTime:    time.Unix(int64(db.Metadata.BuildEpoch), 0),
+		IPVersion:    db.Metadata.IPVersion,
+		Languages:    db.Metadata.Languages,
+		NodeCount:    db.Metadata.NodeCount,
+		RecordSize:   db.Metadata.RecordSize,
+	}
+}
+
+// 支持两种扁平的 mmdb 格式：
+// - 内置 geoip.db：country/continent 是代码，country_name/continent_name 是名字
+// - 外部 ipinfo_lite.mmdb：country/country_name 是名字，country_code/continent_code 是代码
+// GeoLite2 等嵌套结构的数据库使用 maxmindRecord 解析
+type IPInfo struct {
+	CountryCode   string `maxminddb:"country_code"`
+	Country       string `maxminddb:"country"`
+	CountryName   string `maxminddb:"country_name"`
+	ContinentCode string `maxminddb:"continent_code"`
+	Continent     string `maxminddb:"continent"`
+	ContinentName string `maxminddb:"continent_name"`
+	IsInEU        bool   `maxminddb:"is_in_european_union"`
+
+	// 城市级数据库（如 ipinfo 城市库）才有的字段
+	City       string `maxminddb:"city"`
+	Region     string `maxminddb:"region"`
+	PostalCode string `maxminddb:"postal_code"`
+	// 不同数据库里经纬度可能是浮点数也可能是字符串，统一在 toResult 中转换
+	Lat            any    `maxminddb:"lat"`
+	Lng            any    `maxminddb:"lng"`
+	AccuracyRadius uint16 `maxminddb:"accuracy_radius"`
+	Timezone       string `maxminddb:"timezone"`
+
+	// 隐私检测数据库（如 ipinfo privacy 库）才有的字段，可能是布尔值也可能是 "true" 字符串
+	Hosting any    `maxminddb:"hosting"`
+	VPN     any    `maxminddb:"vpn"`
+	Proxy   any    `maxminddb:"proxy"`
+	Tor     any    `maxminddb:"tor"`
+	Relay   any    `maxminddb:"relay"`
+	Service string `maxminddb:"service"`
+
+	// ipinfo Lite 新版同时包含 ASN，asn 为 "AS13335" 形式
+	ASN      string `maxminddb:"asn"`
+	ASName   string `maxminddb:"as_name"`
+	ASDomain string `maxminddb:"as_domain"`
+}
+
+// toResult 将两种 mmdb 格式的记录统一转换为 Result
+func (r *IPInfo) toResult(source string) *Result {
+	res := &Result{Source: source}
+
+	// ==== 国家 ====
+	// 1) 优先 country_code（适配你外部化的 mmdb）
+	// 2) 其次 country 刚好是 2 位（适配内置 mmdb 把代码放在 country 的情况）
+	res.CountryName = r.CountryName
+	if r.CountryCode != "" {
+		res.CountryCode = strings.ToLower(r.CountryCode)
+		if res.CountryName == "" {
+			res.CountryName = r.Country
+		}
+	} else if len(r.Country) == 2 {
+		res.CountryCode = strings.ToLower(r.Country)
+	}
+
+	res.IsEU = r.IsInEU
+
+	// ==== 洲 ====
+	res.ContinentName = r.ContinentName
+	if r.ContinentCode != "" {
+		res.ContinentCode = strings.ToLower(r.ContinentCode)
+		if res.ContinentName == "" {
+			res.ContinentName = r.Continent
+		}
+	} else if len(r.Continent) == 2 {
+		res.ContinentCode = strings.ToLower(r.Continent)
+	}
+
+	// ==== 城市 ====
+	if r.City != "" || r.Region != "" || r.PostalCode != "" {
+		res.City = &City{
+			Name:        r.City,
+			Subdivision: r.Region,
+			PostalCode:  r.PostalCode,
+		}
+	}
+
+	// ==== 坐标 ====
+	lat, latOK := toFloat(r.Lat)
+	lng, lngOK := toFloat(r.Lng)
+	if latOK && lngOK {
+		res.Location = &Location{
+			Latitude:       lat,
+			Longitude:      lng,
+			AccuracyRadius: r.AccuracyRadius,
+		}
+	}
+
+	res.Timezone = r.Timezone
+
+	// ==== 隐私检测 ====
+	if r.Hosting != nil || r.VPN != nil || r.Proxy != nil || r.Tor != nil || r.Relay != nil {
+		res.Privacy = &Privacy{
+			Hosting: toBool(r.Hosting),
+			VPN:     toBool(r.VPN),
+			Proxy:   toBool(r.Proxy),
+			Tor:     toBool(r.Tor),
+			Relay:   toBool(r.Relay),
+			Service: r.Service,
+		}
+	}
+
+	// ==== ASN ====
+	if r.ASN != "" {
+		if asn, err := parseASOrg(r.ASN); err == nil {
+			if r.ASName != "" {
+				asn.Organization = r.ASName
+			}
+			asn.Domain = r.ASDomain
+			res.ASN = asn
+		}
+	}
+
+	return res
+}
+
+func toFloat(v any) (float64, bool) {
+	switch f := v.(type) {
+	case float64:
+		return f, true
+	case float32:
+		return float64(f), true
+	case string:
+		n, err := strconv.ParseFloat(f, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		ok, _ := strconv.ParseBool(b)
+		return ok
+	case uint64:
+		return b != 0
+	}
+	return false
+}
+
+// recordOffset 只记录命中的记录在数据区中的偏移而不解码，实现了 maxminddb 的 deserializer 接口
+// 查询时只遍历一次搜索树，再按偏移复用已解码的结果
+type recordOffset struct {
+	offset uintptr
+}
+
+func (r *recordOffset) ShouldSkip(offset uintptr) (bool, error) {
+	r.offset = offset
+	return true, nil
+}
+
+func (r *recordOffset) StartSlice(uint) error  { return nil }
+func (r *recordOffset) StartMap(uint) error    { return nil }
+func (r *recordOffset) End() error             { return nil }
+func (r *recordOffset) String(string) error    { return nil }
+func (r *recordOffset) Float64(float64) error  { return nil }
+func (r *recordOffset) Bytes([]byte) error     { return nil }
+func (r *recordOffset) Uint16(uint16) error    { return nil }
+func (r *recordOffset) Uint32(uint32) error    { return nil }
+func (r *recordOffset) Int32(int32) error      { return nil }
+func (r *recordOffset) Uint64(uint64) error    { return nil }
+func (r *recordOffset) Uint128(*big.Int) error { return nil }
+func (r *recordOffset) Bool(bool) error        { return nil }
+func (r *recordOffset) Float32(float32) error  { return nil }
+
+var recordOffsetPool = sync.Pool{New: func() any { return new(recordOffset) }}
+
+func (s *mmdbStore) lookup(ip net.IP) (*Result, error) {
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	var res *Result
+	err := s.with(func(db *maxminddb.Reader, source string) error {
+		rec := recordOffsetPool.Get().(*recordOffset)
+		defer recordOffsetPool.Put(rec)
+
+		network, ok, err := db.LookupNetwork(ip, rec)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+		}
+		if !ok {
+			res = &Result{Source: source}
+			return nil
+		}
+		decoded, err := s.decode(db, source, rec.offset)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDBUnavailable, err)
+		}
+		// 解码缓存中的结果由所有查询共享，返回深拷贝，调用方修改 City 等字段不会影响其他查询
+		res = decoded.clone()
+		res.Network = network.String()
+		return nil
+	})
+	return res, err
+}
+
+// decode 返回 offset 处的记录转换后的结果，调用方需持有读锁
+func (s *mmdbStore) decode(db *maxminddb.Reader, source string, offset uintptr) (*Result, error) {
+	if cached, ok := s.decoded.Load(offset); ok {
+		return cached.(*Result), nil
+	}
+
+	var res *Result
+	if isMaxMindFormat(db.Metadata.DatabaseType) {
+		var record maxmindRecord
+		if err := db.Decode(offset, &record); err != nil {
+			return nil, err
+		}
+		res = record.toResult(source)
+	} else {
+		var record IPInfo
+		if err := db.Decode(offset, &record); err != nil {
+			return nil, err
+		}
+		res = record.toResult(source)
+	}
+
+	if s.decodedSize.Add(1) > maxDecodedRecords {
+		s.decoded.Clear()
+		s.decodedSize.Store(1)
+	}
+	if _, loaded := s.decoded.LoadOrStore(offset, res); loaded {
+		s.decodedSize.Add(-1)
+	}
+	return res, nil
+}
+
+// resetDecoded 清空已解码的记录，更换或关闭 reader 时调用，调用方需持有写锁
+func (s *mmdbStore) resetDecoded() {
+	s.decoded.Clear()
+	s.decodedSize.Store(0)
+}
+
+// isMaxMindFormat 根据 metadata 中的数据库类型判断记录是否为 GeoLite2/GeoIP2 的嵌套结构
+// 如 GeoLite2-Country、GeoIP2-City；DB-IP 的免费库也使用相同的结构
+func isMaxMindFormat(databaseType string) bool {
+	return strings.HasPrefix(databaseType, "GeoLite2-") || strings.HasPrefix(databaseType, "GeoIP2-") ||
+		strings.HasPrefix(databaseType, "DBIP-")
+}
+
+// isCityLevel 根据 metadata 中的数据库类型判断是否为城市级数据库，如 GeoLite2-City、ipinfo 的城市库
+func isCityLevel(databaseType string) bool {
+	t := strings.ToLower(databaseType)
+	return strings.Contains(t, "city") || strings.Contains(t, "enterprise") || strings.Contains(t, "insights")
+}
+
+// mmdbProvider 使用外部 mmdb 或内置 geoip.db 查询
+type mmdbProvider struct {
+	db mmdbLayers
+}
+
+func (p *mmdbProvider) Name() string {
+	return ProviderMMDB
+}
+
+func (p *mmdbProvider) Lookup(ctx context.Context, ip net.IP) (*Result, error) {
+	res, err := p.db.lookup(ip)
+	if err != nil {
+		return nil, err
+	}
+	if !res.found() {
+		return nil, ErrNotFound
+	}
+	return res, nil
+}
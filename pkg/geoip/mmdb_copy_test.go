@@ -0,0 +1,44 @@
+package geoip_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+func TestLookupReturnsCopies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeRecordDB(t, path, "ipinfo_lite.mmdb", map[string]builder.Record{
+		"8.0.0.0/8": {"country_code": "US", "city": "Ashburn", "region": "Virginia", "asn": "AS15169", "as_name": "Google LLC"},
+	})
+	r := newDBResolver(t, path)
+	ip, other := net.ParseIP("8.8.8.8"), net.ParseIP("8.8.4.4")
+
+	// 修改返回的结果后再次查询同一条记录，不应看到修改
+	city, err := r.LookupCity(ip)
+	if err != nil || city.Name != "Ashburn" {
+		t.Fatalf("LookupCity = %+v, %v", city, err)
+	}
+	city.Name = "changed"
+	asn, err := r.LookupASNLocal(ip)
+	if err != nil || asn.Number != 15169 {
+		t.Fatalf("LookupASNLocal = %+v, %v", asn, err)
+	}
+	asn.Organization = "changed"
+	res, err := r.LookupDetail(ip)
+	if err != nil || res.City == nil || res.ASN == nil {
+		t.Fatalf("LookupDetail = %+v, %v", res, err)
+	}
+	res.City.Subdivision = "changed"
+
+	for _, ip := range []net.IP{ip, other} {
+		if city, _ := r.LookupCity(ip); city.Name != "Ashburn" || city.Subdivision != "Virginia" {
+			t.Fatalf("LookupCity(%s) after editing a result = %+v", ip, city)
+		}
+		if asn, _ := r.LookupASNLocal(ip); asn.Organization != "Google LLC" {
+			t.Fatalf("LookupASNLocal(%s) after editing a result = %+v", ip, asn)
+		}
+	}
+}
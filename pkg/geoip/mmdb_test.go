@@ -0,0 +1,14 @@
+package geoip
+
+import "testing"
+
+func TestIPInfoLiteASN(t *testing.T) {
+	record := IPInfo{CountryCode: "US", Country: "United States", ASN: "AS13335", ASName: "Cloudflare, Inc.", ASDomain: "cloudflare.com"}
+	res := record.toResult(SourceExternalDB)
+	if res.CountryCode != "us" || res.ASN == nil || *res.ASN != (ASN{Number: 13335, Organization: "Cloudflare, Inc.", Domain: "cloudflare.com"}) {
+		t.Fatalf("toResult = %+v, asn = %+v", res, res.ASN)
+	}
+	if res := (&IPInfo{CountryCode: "US", ASN: "invalid"}).toResult(SourceExternalDB); res.ASN != nil {
+		t.Fatalf("invalid asn decoded: %+v", res.ASN)
+	}
+}
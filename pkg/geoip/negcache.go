@@ -0,0 +1,107 @@
+package geoip
+
+import (
+	"sync"
+	"time"
+)
+
+// 默认实例缓存失败结果的时长，可以通过 GEOIP_NEGATIVE_CACHE_TTL 修改
+const (
+	defaultNegativeTTL = time.Minute
+	negativeCacheSize  = 4096
+)
+
+// negativeCache 短时间缓存查不到或所有数据源都失败的结果
+// Agent 每次上报都会查询 IP，某个 IP 查不到时不必每次都把整个查询链走一遍
+type negativeCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]negativeEntry
+}
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+// newNegativeCache 创建失败结果的缓存，ttl <= 0 时返回 nil，不缓存
+func newNegativeCache(ttl time.Duration) *negativeCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &negativeCache{ttl: ttl, entries: make(map[string]negativeEntry)}
+}
+
+func (c *negativeCache) get(key string) (error, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.err, true
+}
+
+func (c *negativeCache) set(key string, err error) {
+	if c == nil {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= negativeCacheSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		// 仍然太多时随意淘汰一个，失败结果的缓存不需要精确
+		for k := range c.entries {
+			if len(c.entries) < negativeCacheSize {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = negativeEntry{err: err, expires: now.Add(c.ttl)}
+}
+
+// delete 删除一个 IP 的失败结果
+func (c *negativeCache) delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// len 返回缓存的失败结果数，包括已过期但尚未清理的结果
+func (c *negativeCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// purge 清空所有失败结果，数据库重新加载后查不到的 IP 可能已经可以查到
+func (c *negativeCache) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
@@ -0,0 +1,40 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNegativeCache(t *testing.T) {
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{}}}
+	r := New(WithProviders(p), WithNegativeCache(time.Hour))
+	for range 3 {
+		if _, err := r.Lookup(net.ParseIP("1.1.1.1")); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Lookup error = %v", err)
+		}
+	}
+	if p.calls != 1 {
+		t.Fatalf("provider called %d times, expected 1", p.calls)
+	}
+
+	// 重新加载数据库后清空失败结果
+	r.Reload()
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	if p.calls != 2 {
+		t.Fatalf("provider called %d times after reload, expected 2", p.calls)
+	}
+
+	// 调用方已取消时不发起查询，也不缓存
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.LookupContext(ctx, net.ParseIP("2.2.2.2")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled Lookup error = %v, expected context.Canceled", err)
+	}
+	r.Lookup(net.ParseIP("2.2.2.2"))
+	if p.calls != 3 {
+		t.Fatalf("provider called %d times, expected 3", p.calls)
+	}
+}
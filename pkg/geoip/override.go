@@ -0,0 +1,209 @@
+package geoip
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// SourceOverride 表示结果来自用户提供的 CIDR 覆盖表
+const SourceOverride = "override"
+
+// 默认的覆盖表位置，与外部 mmdb 同在 data 目录下，按顺序取第一个存在的文件
+// YAML 格式：
+//
+//	10.1.0.0/16: de
+//	10.2.0.0/16: us
+//
+// CSV 格式（# 开头为注释）：
+//
+//	10.1.0.0/16,de
+//	10.2.3.4,us
+var defaultOverridePaths = []string{
+	"/dashboard/data/geoip_override.yaml",
+	"/dashboard/data/geoip_override.yml",
+	"/dashboard/data/geoip_override.csv",
+}
+
+type overrideEntry struct {
+	prefix  netip.Prefix
+	country string
+}
+
+// overrideTable 是一个 Resolver 使用的覆盖表，未显式设置时首次查询从 paths 中第一个存在的文件加载
+type overrideTable struct {
+	paths []string
+	log   *logger
+
+	once    sync.Once
+	mu      sync.RWMutex
+	entries []overrideEntry // 按前缀长度从长到短排序，第一个命中的即为最精确匹配
+}
+
+func newOverrideTable(paths []string) *overrideTable {
+	return &overrideTable{paths: paths}
+}
+
+// loadFile 从 YAML 或 CSV 文件加载覆盖表，文件类型由扩展名决定，.csv 按 CSV 解析，其余按 YAML 解析
+func (t *overrideTable) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	entries, err := parseOverrides(data, strings.EqualFold(filepath.Ext(path), ".csv"))
+	if err != nil {
+		return fmt.Errorf("geoip override %s: %w", path, err)
+	}
+
+	t.once.Do(func() {}) // 显式加载后不再读取默认位置
+	t.set(entries)
+	return nil
+}
+
+func (t *overrideTable) setTable(table map[string]string) error {
+	entries := make([]overrideEntry, 0, len(table))
+	for cidr, country := range table {
+		entry, err := newOverrideEntry(cidr, country)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	t.once.Do(func() {})
+	t.set(entries)
+	return nil
+}
+
+func (t *overrideTable) set(entries []overrideEntry) {
+	slices.SortStableFunc(entries, func(a, b overrideEntry) int {
+		return b.prefix.Bits() - a.prefix.Bits()
+	})
+
+	t.mu.Lock()
+	t.entries = entries
+	t.mu.Unlock()
+}
+
+func (t *overrideTable) loadDefault() {
+	for _, path := range t.paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var entries []overrideEntry
+			entries, err = parseOverrides(data, strings.EqualFold(filepath.Ext(path), ".csv"))
+			if err == nil {
+				t.set(entries)
+				return
+			}
+		}
+		t.log.error("failed to load override file", "path", path, "error", err)
+		return
+	}
+}
+
+func parseOverrides(data []byte, isCSV bool) ([]overrideEntry, error) {
+	if !isCSV {
+		var table map[string]string
+		if err := yaml.Unmarshal(data, &table); err != nil {
+			return nil, err
+		}
+		entries := make([]overrideEntry, 0, len(table))
+		for cidr, country := range table {
+			entry, err := newOverrideEntry(cidr, country)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+
+	var entries []overrideEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cidr, country, ok := strings.Cut(text, ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected cidr,country", line)
+		}
+		entry, err := newOverrideEntry(cidr, country)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func newOverrideEntry(cidr, country string) (overrideEntry, error) {
+	cidr = strings.TrimSpace(cidr)
+	country = strings.ToLower(strings.TrimSpace(country))
+	if len(country) != 2 {
+		return overrideEntry{}, fmt.Errorf("invalid country code %q for %s", country, cidr)
+	}
+
+	var prefix netip.Prefix
+	if strings.Contains(cidr, "/") {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return overrideEntry{}, err
+		}
+		prefix = p.Masked()
+	} else {
+		addr, err := parseAddr(cidr)
+		if err != nil {
+			return overrideEntry{}, err
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return overrideEntry{prefix: prefix, country: country}, nil
+}
+
+// lookup 在覆盖表中查找 ip，命中时返回国家码与对应的网段
+func (t *overrideTable) lookup(ip net.IP) (string, netip.Prefix, bool) {
+	t.once.Do(t.loadDefault)
+
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return "", netip.Prefix{}, false
+	}
+	addr = addr.Unmap()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, e := range t.entries {
+		if e.prefix.Contains(addr) {
+			return e.country, e.prefix, true
+		}
+	}
+	return "", netip.Prefix{}, false
+}
+
+func (t *overrideTable) lookupResult(ip net.IP) (*Result, bool) {
+	code, prefix, ok := t.lookup(ip)
+	if !ok {
+		return nil, false
+	}
+	return &Result{
+		CountryCode: code,
+		CountryName: CountryName(code, "en"),
+		Network:     prefix.String(),
+		Source:      SourceOverride,
+	}, true
+}
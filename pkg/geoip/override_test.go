@@ -0,0 +1,47 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+)
+
+func TestOverrides(t *testing.T) {
+	r := New(WithOverridePaths(), WithProviders())
+
+	csv := []byte("# datacenter ranges\n10.0.0.0/8,us\n10.1.0.0/16, DE\n2001:db8::1,jp\n")
+	entries, err := parseOverrides(csv, true)
+	if err != nil {
+		t.Fatalf("parseOverrides csv: %v", err)
+	}
+	r.overrides.set(entries)
+
+	cases := map[string]string{
+		"10.2.3.4":        "us",
+		"10.1.2.3":        "de",
+		"::ffff:10.1.2.3": "de",
+		"2001:db8::1":     "jp",
+		"2001:db8::2":     "",
+		"192.168.1.1":     "",
+	}
+	for ip, expected := range cases {
+		code, _, _ := r.overrides.lookup(net.ParseIP(ip))
+		if code != expected {
+			t.Fatalf("lookupOverride(%s) = %q, expected %q", ip, code, expected)
+		}
+	}
+
+	res, err := r.LookupDetail(net.ParseIP("10.1.0.1"))
+	if err != nil || res.Source != SourceOverride || res.CountryCode != "de" || res.Network != "10.1.0.0/16" {
+		t.Fatalf("lookupDetail = %+v, %v", res, err)
+	}
+
+	if _, err := parseOverrides([]byte("10.0.0.0/8: usa\n"), false); err == nil {
+		t.Fatalf("expected error for invalid country code")
+	}
+	if err := r.SetOverrides(map[string]string{"172.16.0.0/12": "sg"}); err != nil {
+		t.Fatalf("SetOverrides: %v", err)
+	}
+	if code, _, _ := r.overrides.lookup(net.ParseIP("172.20.0.1")); code != "sg" {
+		t.Fatalf("lookupOverride after SetOverrides = %q", code)
+	}
+}
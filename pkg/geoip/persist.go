@@ -0,0 +1,156 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// persistInterval 是持久化缓存写入文件的间隔，Resolver.Close 时也会写入一次
+const persistInterval = 5 * time.Minute
+
+// PersistentCache 在 LRUCache 的基础上将结果保存到文件，面板重启或重新部署后不需要重新查询所有服务器
+type PersistentCache struct {
+	*LRUCache
+	path  string
+	dirty atomic.Bool
+}
+
+// persistedEntry 是缓存文件中的一条结果
+type persistedEntry struct {
+	Key     string    `json:"key"`
+	Result  *Result   `json:"result"`
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+// NewPersistentCache 创建保存到 path 的缓存，并读取文件中未过期的结果；文件不存在时从空缓存开始
+func NewPersistentCache(path string, size int, ttl time.Duration) (*PersistentCache, error) {
+	c := &PersistentCache{LRUCache: NewLRUCache(size, ttl), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []persistedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// 文件损坏时丢弃，缓存的内容可以重新查询
+		log.Printf("NEZHA>> geoip: ignoring invalid cache file %s: %v", path, err)
+		return c, nil
+	}
+	c.restore(entries)
+	return c, nil
+}
+
+func (c *PersistentCache) Set(key string, res *Result) {
+	c.LRUCache.Set(key, res)
+	c.dirty.Store(true)
+}
+
+func (c *PersistentCache) Delete(key string) {
+	c.LRUCache.Delete(key)
+	c.dirty.Store(true)
+}
+
+func (c *PersistentCache) Purge() {
+	c.LRUCache.Purge()
+	c.dirty.Store(true)
+}
+
+// Save 将缓存写入文件，自上次写入后没有新结果时什么都不做
+func (c *PersistentCache) Save() error {
+	if !c.dirty.Swap(false) {
+		return nil
+	}
+	data, err := json.Marshal(c.snapshot())
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".geoip-cache-*.json")
+	if err != nil {
+		c.dirty.Store(true)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	if err != nil {
+		c.dirty.Store(true)
+		return fmt.Errorf("geoip: save cache: %w", err)
+	}
+	return nil
+}
+
+// snapshot 按最近使用的顺序返回未过期的结果
+func (c *LRUCache) snapshot() []persistedEntry {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]persistedEntry, 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*lruEntry)
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			continue
+		}
+		entries = append(entries, persistedEntry{Key: entry.key, Result: entry.res, Expires: entry.expires})
+	}
+	return entries
+}
+
+// restore 按 snapshot 的顺序写回结果，保留原来的过期时间
+func (c *LRUCache) restore(entries []persistedEntry) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Result == nil || !entry.Expires.IsZero() && now.After(entry.Expires) {
+			continue
+		}
+		if _, ok := c.items[entry.Key]; ok {
+			continue
+		}
+		c.items[entry.Key] = c.ll.PushFront(&lruEntry{key: entry.Key, res: entry.Result, expires: entry.Expires})
+		if c.ll.Len() > c.size {
+			oldest := c.ll.Back()
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+// saver 是需要定时写入的缓存
+type saver interface {
+	Save() error
+}
+
+// saveLoop 定时将缓存写入文件
+func (r *Resolver) saveLoop(ctx context.Context, s saver) {
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				log.Printf("NEZHA>> geoip: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPersistentCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip_cache.json")
+	c, err := NewPersistentCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentCache: %v", err)
+	}
+	r := New(WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "fr"}}), WithCache(c))
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	r.Close()
+
+	// 重新创建后直接从文件中读取，不再查询数据源
+	c, err = NewPersistentCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentCache: %v", err)
+	}
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{CountryCode: "de"}}}
+	r = New(WithProviders(p), WithCache(c))
+	defer r.Close()
+	if code, err := r.Lookup(net.ParseIP("1.1.1.1")); err != nil || code != "fr" || p.calls != 0 {
+		t.Fatalf("Lookup = %q, %v, calls = %d", code, err, p.calls)
+	}
+
+	// 文件损坏时从空缓存开始
+	os.WriteFile(path, []byte("{"), 0o644)
+	if c, err := NewPersistentCache(path, 10, time.Hour); err != nil || c.Len() != 0 {
+		t.Fatalf("corrupt cache file: %v", err)
+	}
+}
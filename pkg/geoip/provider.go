@@ -0,0 +1,162 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// 内置 Provider 的名称，可用于 SetChain 与 GEOIP_PROVIDERS
+const (
+	ProviderOverride = "override" // CIDR 覆盖表，不是真正的 Provider，只能放在查询链最前面
+	ProviderIPInfo   = "ipinfo"
+	ProviderIPAPI    = "ip-api"
+	ProviderIPAPICo  = "ipapi.co"
+	ProviderMaxMind  = "maxmind"
+
+	ProviderCloudflare  = "cloudflare"
+	ProviderMMDB        = "mmdb"
+	ProviderIP2Location = "ip2location"
+)
+
+// isLocalProvider 判断是否是本地数据库，本地数据库的结果不写入缓存
+func isLocalProvider(name string) bool {
+	switch name {
+	case ProviderOverride, ProviderMMDB, ProviderIP2Location:
+		return true
+	}
+	return false
+}
+
+// Provider 是一个 IP 地理位置数据源
+// Lookup 查不到时应返回 error 或没有国家码/洲码的 Result，查询链会继续尝试下一个 Provider
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, ip net.IP) (*Result, error)
+}
+
+// SetChain 按名称重新指定查询链，如 SetChain("override", "mmdb", "ipinfo", "ip-api")
+// 名称可以是内置 Provider 或已通过 Register 注册的 Provider；不包含 override 时不使用覆盖表
+// 覆盖表总是最先查询，override 必须是第一个
+func (r *Resolver) SetChain(names ...string) error {
+	r.providersMu.Lock()
+	defer r.providersMu.Unlock()
+
+	var chain []Provider
+	noOverride := true
+	for i, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+			continue
+		case name == ProviderOverride:
+			if i != 0 {
+				return fmt.Errorf("geoip: %s must be the first in the provider chain", ProviderOverride)
+			}
+			noOverride = false
+			continue
+		}
+
+		p, ok := r.builtins[name]
+		if j := r.indexProvider(name); j >= 0 {
+			p, ok = r.providers[j], true
+		}
+		if !ok {
+			return fmt.Errorf("geoip: unknown provider %q", name)
+		}
+		if slices.Contains(chain, p) {
+			return fmt.Errorf("geoip: duplicate provider %q", name)
+		}
+		chain = append(chain, p)
+	}
+
+	r.providers = chain
+	r.noOverride = noOverride
+	return nil
+}
+
+// Register 将 p 追加到查询链末尾；已存在同名 Provider 时替换到原来的位置
+// 修改查询链时总是生成新的切片，见 chain
+func (r *Resolver) Register(p Provider) {
+	r.providersMu.Lock()
+	defer r.providersMu.Unlock()
+
+	providers := slices.Clone(r.providers)
+	if i := r.indexProvider(p.Name()); i >= 0 {
+		providers[i] = p
+	} else {
+		providers = append(providers, p)
+	}
+	r.providers = providers
+}
+
+// RegisterFirst 将 p 放到查询链最前面；已存在同名 Provider 时先移除旧的
+func (r *Resolver) RegisterFirst(p Provider) {
+	r.providersMu.Lock()
+	defer r.providersMu.Unlock()
+
+	providers := slices.Clone(r.providers)
+	if i := r.indexProvider(p.Name()); i >= 0 {
+		providers = slices.Delete(providers, i, i+1)
+	}
+	r.providers = slices.Insert(providers, 0, p)
+}
+
+// Unregister 从查询链中移除指定名称的 Provider
+func (r *Resolver) Unregister(name string) {
+	r.providersMu.Lock()
+	defer r.providersMu.Unlock()
+
+	if i := r.indexProvider(name); i >= 0 {
+		r.providers = slices.Delete(slices.Clone(r.providers), i, i+1)
+	}
+}
+
+// Providers 返回当前查询链的副本
+func (r *Resolver) Providers() []Provider {
+	r.providersMu.RLock()
+	defer r.providersMu.RUnlock()
+
+	return slices.Clone(r.providers)
+}
+
+// chain 返回当前的查询链，查询链只会被整体替换，返回的切片不会被修改，查询时不需要复制
+func (r *Resolver) chain() []Provider {
+	r.providersMu.RLock()
+	defer r.providersMu.RUnlock()
+
+	return r.providers
+}
+
+func (r *Resolver) indexProvider(name string) int {
+	return slices.IndexFunc(r.providers, func(p Provider) bool {
+		return p.Name() == name
+	})
+}
+
+// SetChain 按名称重新指定默认实例的查询链，见 Resolver.SetChain
+func SetChain(names ...string) error {
+	return Default().SetChain(names...)
+}
+
+// Register 将 p 追加到默认实例的查询链末尾；已存在同名 Provider 时原地替换
+func Register(p Provider) {
+	Default().Register(p)
+}
+
+// RegisterFirst 将 p 放到默认实例的查询链最前面
+func RegisterFirst(p Provider) {
+	Default().RegisterFirst(p)
+}
+
+// Unregister 从默认实例的查询链中移除指定名称的 Provider
+func Unregister(name string) {
+	Default().Unregister(name)
+}
+
+// Providers 返回默认实例当前查询链的副本
+func Providers() []Provider {
+	return Default().Providers()
+}
@@ -0,0 +1,66 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestProviderChain(t *testing.T) {
+	r := New(WithProviders(
+		&staticProvider{name: "broken", err: errors.New("unavailable")},
+		&staticProvider{name: "empty", res: &Result{}},
+	))
+	r.Register(&staticProvider{name: "static", res: &Result{CountryCode: "jp", Source: "static"}})
+
+	res, err := r.LookupDetail(net.ParseIP("1.1.1.1"))
+	if err != nil {
+		t.Fatalf("lookupDetail: %v", err)
+	}
+	if res.CountryCode != "jp" || res.Source != "static" || res.Flag != "🇯🇵" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	r.RegisterFirst(&staticProvider{name: "first", res: &Result{CountryCode: "de"}})
+	if code, _ := r.Lookup(net.ParseIP("1.1.1.1")); code != "de" {
+		t.Fatalf("Lookup = %q, expected de", code)
+	}
+
+	r.Unregister("first")
+	r.Unregister("static")
+	if _, err := r.Lookup(net.ParseIP("1.1.1.1")); err == nil {
+		t.Fatalf("expected error after all providers failed")
+	}
+	if n := len(r.Providers()); n != 2 {
+		t.Fatalf("len(Providers()) = %d, expected 2", n)
+	}
+}
+
+func TestSetChain(t *testing.T) {
+	r := New(WithChain("mmdb", "ipinfo", " IP-API "))
+	var names []string
+	for _, p := range r.Providers() {
+		names = append(names, p.Name())
+	}
+	if strings.Join(names, ",") != "mmdb,ipinfo,ip-api" {
+		t.Fatalf("chain = %v", names)
+	}
+	if !r.noOverride {
+		t.Fatalf("override should be disabled when not in the chain")
+	}
+
+	r.Register(&staticProvider{name: "internal", res: &Result{CountryCode: "de"}})
+	if err := r.SetChain("override", "internal", "mmdb"); err != nil {
+		t.Fatalf("SetChain: %v", err)
+	}
+	if ps := r.Providers(); len(ps) != 2 || ps[0].Name() != "internal" || r.noOverride {
+		t.Fatalf("unexpected chain after SetChain: %v", ps)
+	}
+
+	for _, names := range [][]string{{"mmdb", "override"}, {"nope"}, {"mmdb", "mmdb"}} {
+		if err := r.SetChain(names...); err == nil {
+			t.Fatalf("SetChain(%v) should fail", names)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package geoip
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ipinfo 免费额度为每月 5 万次请求，默认按这个额度限制 ipinfo 的请求
+const defaultIPInfoMonthlyQuota = 50000
+
+// rateLimit 描述一个数据源的请求额度，为 0 的项不限制
+type rateLimit struct {
+	perMinute int
+	perMonth  int
+}
+
+// rateLimiter 在客户端限制数据源的请求频率，每分钟的额度用令牌桶实现，每月的额度按自然月（UTC）计数
+// 额度用完后直接返回 ErrProviderUnavailable，查询会落到后面的数据源
+// 计数只保存在内存中，重启后重新计数
+type rateLimiter struct {
+	limit  rateLimit
+	bucket *rate.Limiter
+
+	mu     sync.Mutex
+	period time.Time // 当前计数周期的开始时间
+	used   int
+}
+
+func newRateLimiter(limit rateLimit) *rateLimiter {
+	if limit.perMinute <= 0 && limit.perMonth <= 0 {
+		return nil
+	}
+	l := &rateLimiter{limit: limit}
+	if limit.perMinute > 0 {
+		l.bucket = rate.NewLimiter(rate.Limit(float64(limit.perMinute)/60), limit.perMinute)
+	}
+	return l
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// allow 判断是否还有额度，有额度时消耗一次
+func (l *rateLimiter) allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if period := monthStart(time.Now()); !period.Equal(l.period) {
+		l.period, l.used = period, 0
+	}
+	if l.limit.perMonth > 0 && l.used >= l.limit.perMonth {
+		return false
+	}
+	if l.bucket != nil && !l.bucket.Allow() {
+		return false
+	}
+	l.used++
+	return true
+}
+
+// RateLimitStats 描述一个数据源的请求额度与剩余额度
+type RateLimitStats struct {
+	Provider          string    `json:"provider"`
+	PerMinute         int       `json:"per_minute,omitempty"` // 为 0 时不限制
+	PerMonth          int       `json:"per_month,omitempty"`  // 为 0 时不限制
+	UsedThisMonth     int       `json:"used_this_month"`
+	RemainingMonth    int       `json:"remaining_month"`  // 不限制每月额度时为 -1
+	RemainingMinute   int       `json:"remaining_minute"` // 不限制每分钟额度时为 -1
+	MonthlyQuotaReset time.Time `json:"monthly_quota_reset"`
+}
+
+func (l *rateLimiter) stats(provider string) RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	period := monthStart(now)
+	used := l.used
+	if !period.Equal(l.period) {
+		used = 0
+	}
+	s := RateLimitStats{
+		Provider:          provider,
+		PerMinute:         l.limit.perMinute,
+		PerMonth:          l.limit.perMonth,
+		UsedThisMonth:     used,
+		RemainingMonth:    -1,
+		RemainingMinute:   -1,
+		MonthlyQuotaReset: period.AddDate(0, 1, 0),
+	}
+	if l.limit.perMonth > 0 {
+		s.RemainingMonth = max(l.limit.perMonth-used, 0)
+	}
+	if l.bucket != nil {
+		s.RemainingMinute = max(int(l.bucket.TokensAt(now)), 0)
+	}
+	return s
+}
+
+// rateLimited 由内嵌 httpFetcher 的数据源实现，用于汇总各数据源的额度
+type rateLimited interface {
+	rateLimiter() *rateLimiter
+}
+
+// RateLimits 返回设置了请求额度的数据源及其剩余额度，按数据源名称排序
+func (r *Resolver) RateLimits() []RateLimitStats {
+	var stats []RateLimitStats
+	for name, p := range r.builtins {
+		rl, ok := p.(rateLimited)
+		if !ok || rl.rateLimiter() == nil {
+			continue
+		}
+		stats = append(stats, rl.rateLimiter().stats(name))
+	}
+	slices.SortFunc(stats, func(a, b RateLimitStats) int {
+		return strings.Compare(a.Provider, b.Provider)
+	})
+	return stats
+}
+
+// RateLimits 返回默认实例各数据源的剩余额度，见 Resolver.RateLimits
+func RateLimits() []RateLimitStats {
+	return Default().RateLimits()
+}
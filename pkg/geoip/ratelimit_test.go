@@ -0,0 +1,39 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f := &httpFetcher{name: "test", client: srv.Client(), timeout: time.Second, limiter: newRateLimiter(rateLimit{perMinute: 10, perMonth: 2})}
+	for range 2 {
+		if _, err := f.get(context.Background(), srv.URL); err != nil {
+			t.Fatalf("get within quota: %v", err)
+		}
+	}
+	if _, err := f.get(context.Background(), srv.URL); !errors.Is(err, ErrProviderUnavailable) || calls != 2 {
+		t.Fatalf("get over quota = %v after %d calls", err, calls)
+	}
+	if s := f.limiter.stats("test"); s.UsedThisMonth != 2 || s.RemainingMonth != 0 || s.RemainingMinute != 8 {
+		t.Fatalf("stats = %+v", s)
+	}
+
+	r := New(WithDBPaths(), WithRateLimit(ProviderIPAPI, 0, 100))
+	stats := r.RateLimits()
+	if len(stats) != 2 || stats[0].Provider != ProviderIPAPI || stats[1].Provider != ProviderIPInfo ||
+		stats[1].PerMonth != defaultIPInfoMonthlyQuota || stats[1].RemainingMinute != -1 {
+		t.Fatalf("RateLimits = %+v", stats)
+	}
+}
@@ -0,0 +1,98 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRDNSTimeout = time.Second
+	defaultRDNSTTL     = time.Hour
+	rdnsCacheSize      = 4096
+)
+
+// rdnsResolver 在详细查询结果中补充 PTR 记录，查到与查不到的结果都缓存 ttl
+// 与 IP 定位的缓存分开，PTR 记录的有效期通常比定位结果短得多
+type rdnsResolver struct {
+	timeout time.Duration
+	ttl     time.Duration
+	lookup  func(ctx context.Context, addr string) ([]string, error)
+
+	mu      sync.Mutex
+	entries map[string]rdnsEntry
+}
+
+type rdnsEntry struct {
+	hostname string
+	expires  time.Time
+}
+
+// newRDNSResolver 创建反向解析，timeout <= 0 时返回 nil，不解析
+func newRDNSResolver(timeout, ttl time.Duration) *rdnsResolver {
+	if timeout <= 0 {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = defaultRDNSTTL
+	}
+	return &rdnsResolver{
+		timeout: timeout,
+		ttl:     ttl,
+		lookup:  net.DefaultResolver.LookupAddr,
+		entries: make(map[string]rdnsEntry),
+	}
+}
+
+// hostname 返回 ip 的第一个 PTR 记录，去掉末尾的点；查不到或超时返回空字符串
+func (d *rdnsResolver) hostname(ctx context.Context, ip net.IP) string {
+	key := ip.String()
+	now := time.Now()
+	d.mu.Lock()
+	if entry, ok := d.entries[key]; ok && now.Before(entry.expires) {
+		d.mu.Unlock()
+		return entry.hostname
+	}
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	var hostname string
+	names, err := d.lookup(ctx, key)
+	if err == nil && len(names) > 0 {
+		hostname = strings.TrimSuffix(names[0], ".")
+	}
+	// ctx 被调用方取消时不缓存，下一次查询重新解析
+	if ctx.Err() != nil && hostname == "" {
+		return ""
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.entries) >= rdnsCacheSize {
+		for k, entry := range d.entries {
+			if now.After(entry.expires) {
+				delete(d.entries, k)
+			}
+		}
+		for k := range d.entries {
+			if len(d.entries) < rdnsCacheSize {
+				break
+			}
+			delete(d.entries, k)
+		}
+	}
+	d.entries[key] = rdnsEntry{hostname: hostname, expires: now.Add(d.ttl)}
+	return hostname
+}
+
+// withHostname 在开启反向解析时为 res 补充主机名，数据源已经返回主机名或内网地址时不解析
+func (r *Resolver) withHostname(ctx context.Context, ip net.IP, res *Result, err error) (*Result, error) {
+	if r.rdns == nil || err != nil || res.Hostname != "" || res.Private {
+		return res, err
+	}
+	res.Hostname = r.rdns.hostname(ctx, ip)
+	return res, nil
+}
@@ -0,0 +1,32 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReverseDNS(t *testing.T) {
+	r := newTestResolver(t, WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "us"}}), WithReverseDNS(time.Second, time.Minute))
+	var calls int
+	r.rdns.lookup = func(ctx context.Context, addr string) ([]string, error) {
+		calls++
+		return []string{"dns.google."}, nil
+	}
+	for range 2 {
+		res, err := r.LookupDetail(net.ParseIP("8.8.8.8"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Hostname != "dns.google" {
+			t.Fatalf("hostname = %q, want dns.google", res.Hostname)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("ptr lookups = %d, want 1", calls)
+	}
+	if res, _ := r.LookupDetail(net.ParseIP("192.168.1.1")); res.Hostname != "" || calls != 1 {
+		t.Fatalf("private address resolved: %+v", res)
+	}
+}
@@ -0,0 +1,42 @@
+package geoip
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestRedisCacheKeys(t *testing.T) {
+	keys := prefixKeys(netip.MustParseAddr("1.2.3.4"))
+	if len(keys) != 25 || keys[0] != "1.2.3.4/32" || keys[8] != "1.2.3.0/24" || keys[24] != "1.0.0.0/8" {
+		t.Fatalf("prefixKeys = %v", keys)
+	}
+	if keys := prefixKeys(netip.MustParseAddr("2a00:1450::1")); keys[0] != "2a00:1450::1/128" || keys[len(keys)-1] != "2a00::/16" {
+		t.Fatalf("prefixKeys = %v", keys)
+	}
+
+	addr := netip.MustParseAddr("1.2.3.4")
+	cases := []struct {
+		network string
+		want    string
+	}{
+		{"1.2.0.0/16", "1.2.0.0/16"},
+		{"", "1.2.3.4/32"},
+		{"5.6.0.0/16", "1.2.3.4/32"}, // 网段不包含该 IP
+	}
+	for _, c := range cases {
+		if got := networkKey(addr, &Result{Network: c.network}); got != c.want {
+			t.Errorf("networkKey(%q) = %q, want %q", c.network, got, c.want)
+		}
+	}
+
+	// Redis 不可用时视为未命中
+	c, err := NewRedisCacheURL("redis://127.0.0.1:1/0", time.Hour)
+	if err != nil {
+		t.Fatalf("NewRedisCacheURL: %v", err)
+	}
+	c.Set("1.2.3.4", &Result{CountryCode: "us"})
+	if _, ok := c.Get("1.2.3.4"); ok {
+		t.Fatalf("expected miss when redis is unavailable")
+	}
+}
@@ -0,0 +1,864 @@
+package geoip
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// Resolver 是一个独立的查询实例，拥有自己的数据库、查询链、覆盖表与缓存
+// 包级别的 Lookup 等函数使用 Default() 返回的默认实例
+type Resolver struct {
+	db            mmdbLayers
+	asn           *mmdbStore // 独立的 ASN 数据库，与国家数据库互不影响，任何一个都可以不存在
+	ipinfo        *ipinfoProvider
+	cloudflare    *cloudflareProvider
+	overrides     *overrideTable
+	cache         Cache
+	negative      *negativeCache // 查询失败的结果，与 cache 独立，有效期较短
+	inflight      singleflight.Group
+	flightTimeout time.Duration // 合并查询整体的超时，与发起查询的调用方无关
+	health        *healthTable
+	adaptive      bool // 将不健康的数据源移到查询链末尾
+	preferOnline  bool // 数据库过旧时优先使用在线数据源
+	updater       *updater
+	metrics       *metrics
+	log           *logger
+	tracer        trace.Tracer
+	rdns          *rdnsResolver // 为详细查询结果补充 PTR 记录，未开启时为 nil
+	self          *selfIPDetector
+	lookupStats   *lookupStats       // 最近一段时间内查询结果的分布，未开启时为 nil
+	consensus     []string           // WithConsensus 指定的数据源
+	stop          context.CancelFunc // 停止定时健康检查、数据库文件监听等后台任务
+
+	// 返回给调用方之前对结果的调整，见 WithUppercaseCodes、WithContinentFallback
+	uppercaseCodes      bool
+	noContinentFallback bool
+
+	builtins map[string]Provider // 内置的 Provider，供 SetChain 按名称选择
+
+	providersMu sync.RWMutex
+	providers   []Provider
+	noOverride  bool // 通过 SetChain 指定的查询链中不包含 override 时不使用覆盖表
+}
+
+type options struct {
+	dbPaths       []string
+	dbLayers      []string
+	asnDBPaths    []string
+	overridePaths []string
+	providers     []Provider
+	providersSet  bool
+	chain         []string
+	httpClient    *http.Client
+	proxy         string
+	timeout       time.Duration
+	retries       int
+	backoff       time.Duration
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	rateLimits       map[string]rateLimit
+	healthInterval   time.Duration
+	watch            bool
+	fullVerify       bool
+	dbInMemory       bool
+	maxDBAge         time.Duration
+	preferOnline     bool
+	update           updater
+	adaptive         bool
+
+	cache           Cache
+	negativeTTL     time.Duration
+	offline         bool
+	rdnsTimeout     time.Duration
+	rdnsTTL         time.Duration
+	selfIPServices  []string
+	statsWindow     time.Duration
+	consensus       []string
+	ipapicoKey      string
+	ipinfoFull      bool
+	ipinfoTokens    []string
+	ipinfoTokenFile string
+
+	uppercaseCodes      bool
+	noContinentFallback bool
+
+	maxmindAccountID  string
+	maxmindLicenseKey string
+	maxmindKeyFile    string
+
+	ip2locationPath string
+	maxmindService  string
+
+	logger         *slog.Logger
+	tracerProvider trace.TracerProvider
+}
+
+// Option 用于配置 New 创建的 Resolver
+type Option func(*options)
+
+// WithDBPaths 设置外部 mmdb 的候选路径，按顺序使用第一个可打开的文件，都不可用时回退到内置数据库
+func WithDBPaths(paths ...string) Option {
+	return func(o *options) {
+		o.dbPaths = paths
+	}
+}
+
+// WithDBLayers 在主数据库之外同时加载多个附加数据库，查询时按顺序补全主数据库中没有的字段
+// 如主数据库为 ipinfo_lite.mmdb，附加 GeoLite2-City.mmdb 补全城市与坐标、GeoLite2-ASN.mmdb 补全 ASN
+func WithDBLayers(paths ...string) Option {
+	return func(o *options) {
+		o.dbLayers = paths
+	}
+}
+
+// WithASNDBPaths 设置 ASN 数据库的候选路径，按顺序使用第一个可以打开的文件
+// 查到的 AS 号与组织名称会补全到查询结果中，文件都不存在时不影响国家的查询
+func WithASNDBPaths(paths ...string) Option {
+	return func(o *options) {
+		o.asnDBPaths = paths
+	}
+}
+
+// WithOverridePaths 设置 CIDR 覆盖表的候选路径，按顺序使用第一个存在的文件
+func WithOverridePaths(paths ...string) Option {
+	return func(o *options) {
+		o.overridePaths = paths
+	}
+}
+
+// WithProviders 用 ps 替换默认的 ipinfo → mmdb 查询链，不传参数时查询链为空
+func WithProviders(ps ...Provider) Option {
+	return func(o *options) {
+		o.providers = ps
+		o.providersSet = true
+	}
+}
+
+// WithChain 按名称指定查询链，见 Resolver.SetChain；名称无效时记录日志并使用默认查询链
+func WithChain(names ...string) Option {
+	return func(o *options) {
+		o.chain = names
+	}
+}
+
+// WithHTTPClient 设置在线查询使用的 http.Client
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithProxy 设置在线查询使用的代理，支持 http://、https://、socks5:// 与 socks5h://
+// 未设置时使用 HTTP_PROXY、HTTPS_PROXY 与 NO_PROXY 环境变量；与 WithHTTPClient 同时使用时不生效
+func WithProxy(proxyURL string) Option {
+	return func(o *options) {
+		o.proxy = proxyURL
+	}
+}
+
+// WithTimeout 设置在线查询单次请求的超时，ctx 的 deadline 更早时以 ctx 为准
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRetries 设置在线查询遇到网络错误、429、5xx 等临时故障时的最大重试次数与首次重试前的等待时间
+// 之后每次重试的等待时间翻倍并加入随机抖动，retries 为 0 时不重试
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.backoff = backoff
+	}
+}
+
+// WithCircuitBreaker 设置在线数据源的熔断：连续失败 threshold 次或返回 429 后，cooldown 内不再请求该数据源
+// threshold 为 0 时不熔断
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.breakerThreshold = threshold
+		o.breakerCooldown = cooldown
+	}
+}
+
+// WithRateLimit 设置数据源在客户端的请求额度，perMinute、perMonth 为 0 时不限制对应的额度
+// 额度用完后跳过该数据源，由查询链中后面的数据源（最终是 mmdb）查询；ipinfo 默认限制为每月 50000 次
+func WithRateLimit(provider string, perMinute, perMonth int) Option {
+	return func(o *options) {
+		o.rateLimits[provider] = rateLimit{perMinute: perMinute, perMonth: perMonth}
+	}
+}
+
+// WithHealthCheck 每隔 interval 用固定的 IP 请求一次查询链中的每个数据源，记录延迟与错误率，见 Resolver.Health
+// interval 为 0 时不做定时检查，健康状况只根据正常的查询统计；注意每次检查都会消耗在线数据源的额度
+func WithHealthCheck(interval time.Duration) Option {
+	return func(o *options) {
+		o.healthInterval = interval
+	}
+}
+
+// WithAdaptiveOrder 开启后，连续失败或错误率过高的数据源会被移到查询链末尾，恢复后回到原来的位置
+func WithAdaptiveOrder(adaptive bool) Option {
+	return func(o *options) {
+		o.adaptive = adaptive
+	}
+}
+
+// WithLogger 设置输出日志的 slog.Logger，默认使用标准库 log
+// 设置后额外输出每次查询时数据源的失败与回退，失败为 Warn 级别，查不到数据为 Debug 级别
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithTracerProvider 设置创建 span 的 TracerProvider，默认使用 otel.GetTracerProvider
+// 只有调用方的 ctx 中已有 span 时才会为查询、各数据源的请求与 mmdb 查询创建子 span
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithWatch 设置是否监听外部 mmdb 文件，文件被替换或修改后自动重新加载，默认开启
+func WithWatch(watch bool) Option {
+	return func(o *options) {
+		o.watch = watch
+	}
+}
+
+// WithFullVerify 打开外部 mmdb 时完整校验整个文件，默认只检查 metadata 与几个固定地址的查询结果
+// 完整校验可以发现文件中间的损坏，但大的城市库需要数秒
+func WithFullVerify(full bool) Option {
+	return func(o *options) {
+		o.fullVerify = full
+	}
+}
+
+// WithDBInMemory 将外部 mmdb 完整读入内存，默认使用 mmap 打开
+// 数据目录在 NFS 等网络存储上时建议开启，内存较小的机器建议保持默认
+func WithDBInMemory(inMemory bool) Option {
+	return func(o *options) {
+		o.dbInMemory = inMemory
+	}
+}
+
+// WithMaxDBAge 设置数据库的最长使用时间，构建时间超过 maxAge 时记录警告并在 Metadata 中标记，0 为不检查，默认 90 天
+func WithMaxDBAge(maxAge time.Duration) Option {
+	return func(o *options) {
+		o.maxDBAge = maxAge
+	}
+}
+
+// WithPreferOnlineWhenStale 设置主数据库过旧时是否将 mmdb 移到查询链末尾，优先使用在线数据源
+func WithPreferOnlineWhenStale(prefer bool) Option {
+	return func(o *options) {
+		o.preferOnline = prefer
+	}
+}
+
+// WithAutoUpdate 每隔 interval 从 url 下载一次数据库到第一个候选路径，下载成功后自动重新加载
+// checksumURL 为空时只校验下载的文件能否作为 mmdb 打开；interval 为 0 时使用默认的 24 小时
+func WithAutoUpdate(url, checksumURL string, interval time.Duration) Option {
+	return func(o *options) {
+		o.update.url = url
+		o.update.checksumURL = checksumURL
+		o.update.interval = interval
+	}
+}
+
+// WithCache 设置在线数据源查询结果的缓存，默认不缓存；默认实例使用 NewLRUCache 创建的缓存
+// mmdb 等本地数据库的结果不缓存，重新加载数据库时缓存会清空
+func WithCache(c Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithNegativeCache 将查不到或所有数据源都失败的结果缓存 ttl，期间再次查询同一个 IP 直接返回上次的错误
+// 默认不缓存，默认实例缓存 1 分钟；重新加载数据库时会清空
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithOffline 开启离线模式：不发起任何在线查询，只使用覆盖表与 mmdb
+// 适用于无法访问外网的部署，避免每次查询都要等待在线查询超时
+func WithOffline(offline bool) Option {
+	return func(o *options) {
+		o.offline = offline
+	}
+}
+
+// WithReverseDNS 在 LookupDetail 的结果中补充 PTR 记录作为主机名，机房 IP 的主机名通常能直接看出服务商与机房位置
+// timeout 为每次解析的超时，<= 0 时不解析；ttl 为解析结果的缓存时长，<= 0 时使用 1 小时，查不到的结果同样缓存
+// 数据源已经返回主机名（如 ipinfo 完整 JSON 接口）时不再解析，Lookup 等只返回国家码的查询不解析
+func WithReverseDNS(timeout, ttl time.Duration) Option {
+	return func(o *options) {
+		o.rdnsTimeout, o.rdnsTTL = timeout, ttl
+	}
+}
+
+// WithSelfIPServices 设置 SelfIP 按顺序尝试的服务，https:// 地址应返回纯文本的 IP 或 Cloudflare trace 格式，
+// stun:host:port 使用 STUN Binding 请求；为空时使用默认的 Cloudflare、ipify、icanhazip 与 Google STUN
+func WithSelfIPServices(services ...string) Option {
+	return func(o *options) {
+		o.selfIPServices = services
+	}
+}
+
+// WithLookupStats 统计最近 window 时间内每次查询（包括缓存命中）的结果按国家与 ASN 的分布，通过 RecentLookups 读取
+// window <= 0 时不统计，默认不统计
+func WithLookupStats(window time.Duration) Option {
+	return func(o *options) {
+		o.statsWindow = window
+	}
+}
+
+// WithConsensus 开启多数一致模式：每次查询同时请求 providers 中的所有数据源（如 mmdb 与 ipinfo），返回多数一致的国家，
+// 结果的 Confidence 为一致的数据源占比，存在分歧时输出日志；anycast 等网段单个数据源定位错误时可以由其他数据源纠正
+// 至少需要两个数据源，所有数据源都查不到时仍按查询链查询；会增加在线数据源的额度消耗
+func WithConsensus(providers ...string) Option {
+	return func(o *options) {
+		o.consensus = nil
+		for _, name := range providers {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				o.consensus = append(o.consensus, name)
+			}
+		}
+	}
+}
+
+// WithUppercaseCodes 让查询结果使用大写的国家码与洲码，如 HK、AS，适合按 ISO 3166-1 alpha-2 严格校验的调用方
+// 默认使用小写，与面板中保存的国家码一致；缓存中始终保存小写
+func WithUppercaseCodes(upper bool) Option {
+	return func(o *options) {
+		o.uppercaseCodes = upper
+	}
+}
+
+// WithContinentFallback 设置查不到国家、只查到洲时是否以洲码作为结果，默认开启
+// 关闭后这类结果返回 ErrNotFound，Lookup 不会再返回 eu、as 等不是国家码的结果
+func WithContinentFallback(fallback bool) Option {
+	return func(o *options) {
+		o.noContinentFallback = !fallback
+	}
+}
+
+// WithIPInfoFullJSON 让 ipinfo 使用完整的 JSON 接口代替 /country，结果中会带上城市、坐标、ASN 与主机名
+func WithIPInfoFullJSON(full bool) Option {
+	return func(o *options) {
+		o.ipinfoFull = full
+	}
+}
+
+// WithIPInfoToken 设置 ipinfo 的 token，多个 token 按顺序轮流使用以分摊每个 token 的额度
+func WithIPInfoToken(tokens ...string) Option {
+	return func(o *options) {
+		o.ipinfoTokens = tokens
+	}
+}
+
+// WithIPInfoTokenFile 从文件读取 ipinfo 的 token，多个 token 用逗号或换行分隔
+// 文件被修改后自动重新读取，文件为空或无法读取时使用 WithIPInfoToken 设置的 token
+func WithIPInfoTokenFile(path string) Option {
+	return func(o *options) {
+		o.ipinfoTokenFile = path
+	}
+}
+
+// WithIPAPICoKey 设置 ipapi.co 的 key，设置后默认查询链使用 ipapi.co 代替 ipinfo.io
+func WithIPAPICoKey(key string) Option {
+	return func(o *options) {
+		o.ipapicoKey = key
+	}
+}
+
+// WithMaxMind 设置 MaxMind GeoIP2 Precision 网络服务的账号，设置后默认查询链会最先使用它
+// service 为 country、city 或 insights，留空时使用 city
+func WithMaxMind(accountID, licenseKey, service string) Option {
+	return func(o *options) {
+		o.maxmindAccountID = accountID
+		o.maxmindLicenseKey = licenseKey
+		o.maxmindService = service
+	}
+}
+
+// WithMaxMindLicenseKeyFile 从文件读取 MaxMind 的 license key，文件被修改后自动重新读取
+// 设置后 WithMaxMind 的 licenseKey 可以留空
+func WithMaxMindLicenseKeyFile(path string) Option {
+	return func(o *options) {
+		o.maxmindKeyFile = path
+	}
+}
+
+// WithIP2Location 使用 IP2Location LITE 的 BIN 数据库（DB1~DB26），默认查询链会在 mmdb 之前查询它
+func WithIP2Location(path string) Option {
+	return func(o *options) {
+		o.ip2locationPath = path
+	}
+}
+
+// envOptions 从环境变量读取默认实例的配置
+//   - GEOIP_DB_PATH=/opt/nezha/ipinfo_lite.mmdb,/opt/nezha/GeoLite2-City.mmdb：外部 mmdb 的候选路径，按顺序使用第一个可以打开的文件
+//   - GEOIP_DB_LAYERS=/dashboard/data/GeoLite2-City.mmdb,/dashboard/data/GeoLite2-ASN.mmdb：同时加载的附加数据库
+//   - GEOIP_ASN_DB_PATH=/dashboard/data/asn.mmdb：ASN 数据库的候选路径
+//   - GEOIP_WATCH=0：不监听外部 mmdb 文件的变化
+//   - GEOIP_VERIFY_FULL=1：打开外部 mmdb 时完整校验整个文件
+//   - GEOIP_DB_IN_MEMORY=1：将外部 mmdb 完整读入内存而不是 mmap
+//   - GEOIP_DB_MAX_AGE=90d：数据库的最长使用时间，0 为不检查
+//   - GEOIP_STALE_PREFER_ONLINE=1：数据库过旧时优先使用在线数据源
+//   - GEOIP_UPDATE_URL、GEOIP_UPDATE_SHA256_URL、GEOIP_UPDATE_INTERVAL=24h：自动下载数据库的地址、sha256 地址与间隔
+//   - GEOIP_IP2LOCATION_PATH=/dashboard/data/IP2LOCATION-LITE-DB1.BIN：IP2Location 的 BIN 数据库
+//   - GEOIP_CACHE_SIZE=4096、GEOIP_CACHE_TTL=24h：在线查询结果缓存的容量与有效期，容量为 0 时不缓存
+//   - GEOIP_REDIS_URL=redis://127.0.0.1:6379/0：多个面板共享 Redis 中的缓存，优先于 GEOIP_CACHE_FILE
+//   - GEOIP_CACHE_FILE=/dashboard/data/geoip_cache.json：将缓存保存到文件，重启后继续使用
+//   - GEOIP_NEGATIVE_CACHE_TTL=1m：查询失败的结果缓存多久，0 为不缓存
+//   - GEOIP_OFFLINE=1：开启离线模式
+//   - GEOIP_PROXY=socks5://127.0.0.1:1080：仅用于在线查询的代理
+//   - GEOIP_TIMEOUT=3s：在线查询单次请求的超时
+//   - GEOIP_RETRIES=2：在线查询临时故障的最大重试次数
+//   - GEOIP_BREAKER_THRESHOLD=5、GEOIP_BREAKER_COOLDOWN=5m：在线数据源的熔断参数
+//   - IPINFO_RATE_PER_MINUTE、IPINFO_RATE_PER_MONTH：ipinfo 的请求额度，0 为不限制
+//   - GEOIP_HEALTH_INTERVAL=5m：定时检查各数据源的间隔
+//   - GEOIP_ADAPTIVE=1：自动将不健康的数据源移到查询链末尾
+//   - GEOIP_PROVIDERS=override,mmdb,ipinfo,ip-api：查询链的顺序，ip-api 使用明文 HTTP，只有列在这里时才会使用
+//   - GEOIP_IPINFO_FULL=1：ipinfo 使用完整的 JSON 接口
+//   - IPINFO_TOKEN=token1,token2：ipinfo 的 token，多个 token 轮流使用
+//   - IPINFO_TOKEN_FILE=/run/secrets/ipinfo_token：从文件读取 ipinfo 的 token
+//   - IPAPICO_KEY：ipapi.co 的 key
+//   - MAXMIND_ACCOUNT_ID、MAXMIND_LICENSE_KEY、MAXMIND_SERVICE：MaxMind 网络服务的账号与服务类型
+//   - MAXMIND_LICENSE_KEY_FILE：从文件读取 MaxMind 的 license key
+func envOptions() []Option {
+	var opts []Option
+	if paths := splitList(os.Getenv("GEOIP_DB_PATH")); len(paths) > 0 {
+		opts = append(opts, WithDBPaths(paths...))
+	}
+	if paths := splitList(os.Getenv("GEOIP_DB_LAYERS")); len(paths) > 0 {
+		opts = append(opts, WithDBLayers(paths...))
+	}
+	if paths := splitList(os.Getenv("GEOIP_ASN_DB_PATH")); len(paths) > 0 {
+		opts = append(opts, WithASNDBPaths(paths...))
+	}
+	if watch, err := strconv.ParseBool(os.Getenv("GEOIP_WATCH")); err == nil {
+		opts = append(opts, WithWatch(watch))
+	}
+	if full, _ := strconv.ParseBool(os.Getenv("GEOIP_VERIFY_FULL")); full {
+		opts = append(opts, WithFullVerify(true))
+	}
+	if inMemory, _ := strconv.ParseBool(os.Getenv("GEOIP_DB_IN_MEMORY")); inMemory {
+		opts = append(opts, WithDBInMemory(true))
+	}
+	if maxAge, err := parseAge(os.Getenv("GEOIP_DB_MAX_AGE")); err == nil && maxAge >= 0 {
+		opts = append(opts, WithMaxDBAge(maxAge))
+	}
+	if prefer, _ := strconv.ParseBool(os.Getenv("GEOIP_STALE_PREFER_ONLINE")); prefer {
+		opts = append(opts, WithPreferOnlineWhenStale(true))
+	}
+	if url := os.Getenv("GEOIP_UPDATE_URL"); url != "" {
+		interval, _ := time.ParseDuration(os.Getenv("GEOIP_UPDATE_INTERVAL"))
+		opts = append(opts, WithAutoUpdate(url, os.Getenv("GEOIP_UPDATE_SHA256_URL"), interval))
+	}
+	if path := os.Getenv("GEOIP_IP2LOCATION_PATH"); path != "" {
+		opts = append(opts, WithIP2Location(path))
+	}
+	cacheSize, cacheTTL := defaultCacheSize, defaultCacheTTL
+	if n, err := strconv.Atoi(os.Getenv("GEOIP_CACHE_SIZE")); err == nil && n >= 0 {
+		cacheSize = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("GEOIP_CACHE_TTL")); err == nil && d >= 0 {
+		cacheTTL = d
+	}
+	if cacheSize > 0 {
+		var cache Cache = NewLRUCache(cacheSize, cacheTTL)
+		if url := os.Getenv("GEOIP_REDIS_URL"); url != "" {
+			if c, err := NewRedisCacheURL(url, cacheTTL); err == nil {
+				cache = c
+			} else {
+				log.Printf("NEZHA>> geoip: invalid GEOIP_REDIS_URL, using in-memory cache: %v", err)
+			}
+		} else if path := os.Getenv("GEOIP_CACHE_FILE"); path != "" {
+			if c, err := NewPersistentCache(path, cacheSize, cacheTTL); err == nil {
+				cache = c
+			} else {
+				log.Printf("NEZHA>> geoip: failed to load cache file, using in-memory cache: %v", err)
+			}
+		}
+		opts = append(opts, WithCache(cache))
+	}
+	negativeTTL := defaultNegativeTTL
+	if d, err := time.ParseDuration(os.Getenv("GEOIP_NEGATIVE_CACHE_TTL")); err == nil && d >= 0 {
+		negativeTTL = d
+	}
+	opts = append(opts, WithNegativeCache(negativeTTL))
+	if offline, _ := strconv.ParseBool(os.Getenv("GEOIP_OFFLINE")); offline {
+		opts = append(opts, WithOffline(true))
+	}
+	if rdns, _ := strconv.ParseBool(os.Getenv("GEOIP_RDNS")); rdns {
+		timeout, _ := time.ParseDuration(os.Getenv("GEOIP_RDNS_TIMEOUT"))
+		if timeout <= 0 {
+			timeout = defaultRDNSTimeout
+		}
+		ttl, _ := time.ParseDuration(os.Getenv("GEOIP_RDNS_CACHE_TTL"))
+		opts = append(opts, WithReverseDNS(timeout, ttl))
+	}
+	if services := splitList(os.Getenv("GEOIP_SELF_IP_SERVICES")); len(services) > 0 {
+		opts = append(opts, WithSelfIPServices(services...))
+	}
+	if window, err := time.ParseDuration(os.Getenv("GEOIP_STATS_WINDOW")); err == nil && window > 0 {
+		opts = append(opts, WithLookupStats(window))
+	}
+	if names := splitList(os.Getenv("GEOIP_CONSENSUS")); len(names) > 0 {
+		opts = append(opts, WithConsensus(names...))
+	}
+	if proxy := os.Getenv("GEOIP_PROXY"); proxy != "" {
+		opts = append(opts, WithProxy(proxy))
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("GEOIP_TIMEOUT")); err == nil && timeout > 0 {
+		opts = append(opts, WithTimeout(timeout))
+	}
+	if retries, err := strconv.Atoi(os.Getenv("GEOIP_RETRIES")); err == nil && retries >= 0 {
+		opts = append(opts, WithRetries(retries, defaultBackoff))
+	}
+	threshold, cooldown, breakerSet := defaultBreakerThreshold, defaultBreakerCooldown, false
+	if n, err := strconv.Atoi(os.Getenv("GEOIP_BREAKER_THRESHOLD")); err == nil && n >= 0 {
+		threshold, breakerSet = n, true
+	}
+	if d, err := time.ParseDuration(os.Getenv("GEOIP_BREAKER_COOLDOWN")); err == nil && d > 0 {
+		cooldown, breakerSet = d, true
+	}
+	if breakerSet {
+		opts = append(opts, WithCircuitBreaker(threshold, cooldown))
+	}
+	perMinute, perMonth, rateSet := 0, defaultIPInfoMonthlyQuota, false
+	if n, err := strconv.Atoi(os.Getenv("IPINFO_RATE_PER_MINUTE")); err == nil && n >= 0 {
+		perMinute, rateSet = n, true
+	}
+	if n, err := strconv.Atoi(os.Getenv("IPINFO_RATE_PER_MONTH")); err == nil && n >= 0 {
+		perMonth, rateSet = n, true
+	}
+	if rateSet {
+		opts = append(opts, WithRateLimit(ProviderIPInfo, perMinute, perMonth))
+	}
+	if interval, err := time.ParseDuration(os.Getenv("GEOIP_HEALTH_INTERVAL")); err == nil && interval > 0 {
+		opts = append(opts, WithHealthCheck(interval))
+	}
+	if adaptive, _ := strconv.ParseBool(os.Getenv("GEOIP_ADAPTIVE")); adaptive {
+		opts = append(opts, WithAdaptiveOrder(true))
+	}
+	if chain := splitList(os.Getenv("GEOIP_PROVIDERS")); len(chain) > 0 {
+		opts = append(opts, WithChain(chain...))
+	}
+	if full, _ := strconv.ParseBool(os.Getenv("GEOIP_IPINFO_FULL")); full {
+		opts = append(opts, WithIPInfoFullJSON(true))
+	}
+	if tokens := splitList(os.Getenv("IPINFO_TOKEN")); len(tokens) > 0 {
+		opts = append(opts, WithIPInfoToken(tokens...))
+	}
+	if path := os.Getenv("IPINFO_TOKEN_FILE"); path != "" {
+		opts = append(opts, WithIPInfoTokenFile(path))
+	}
+	if key := os.Getenv("IPAPICO_KEY"); key != "" {
+		opts = append(opts, WithIPAPICoKey(key))
+	}
+	id, key, keyFile := os.Getenv("MAXMIND_ACCOUNT_ID"), os.Getenv("MAXMIND_LICENSE_KEY"), os.Getenv("MAXMIND_LICENSE_KEY_FILE")
+	if id != "" && (key != "" || keyFile != "") {
+		opts = append(opts, WithMaxMind(id, key, os.Getenv("MAXMIND_SERVICE")))
+	}
+	if keyFile != "" {
+		opts = append(opts, WithMaxMindLicenseKeyFile(keyFile))
+	}
+	return opts
+}
+
+// splitList 按逗号拆分环境变量，去掉空白与空项
+func splitList(s string) []string {
+	var list []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (o *options) fetcher(name string) httpFetcher {
+	return httpFetcher{
+		name:    name,
+		client:  o.httpClient,
+		timeout: o.timeout,
+		retries: o.retries,
+		backoff: o.backoff,
+		offline: o.offline,
+		breaker: newCircuitBreaker(o.breakerThreshold, o.breakerCooldown),
+		limiter: newRateLimiter(o.rateLimits[name]),
+	}
+}
+
+// New 创建一个新的 Resolver
+func New(opts ...Option) *Resolver {
+	o := options{
+		dbPaths:       defaultDBPaths,
+		asnDBPaths:    defaultASNDBPaths,
+		watch:         true,
+		maxDBAge:      defaultMaxDBAge,
+		overridePaths: defaultOverridePaths,
+		timeout:       defaultTimeout,
+		retries:       defaultRetries,
+		backoff:       defaultBackoff,
+
+		breakerThreshold: defaultBreakerThreshold,
+		breakerCooldown:  defaultBreakerCooldown,
+		rateLimits: map[string]rateLimit{
+			ProviderIPInfo: {perMonth: defaultIPInfoMonthlyQuota},
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// 默认额度按每个 token 的免费额度计算，多个 token 轮流使用时总额度相应增加
+	if n := len(o.ipinfoTokens); n > 1 && o.rateLimits[ProviderIPInfo] == (rateLimit{perMonth: defaultIPInfoMonthlyQuota}) {
+		o.rateLimits[ProviderIPInfo] = rateLimit{perMonth: n * defaultIPInfoMonthlyQuota}
+	}
+	if o.httpClient == nil {
+		o.httpClient = &http.Client{Transport: newTransport(o.proxy)}
+	}
+
+	r := &Resolver{
+		db:  newMMDBLayers(o.dbPaths, o.dbLayers),
+		asn: newASNStore(o.asnDBPaths),
+		ipinfo: &ipinfoProvider{
+			httpFetcher: o.fetcher(ProviderIPInfo),
+			endpoint:    ipinfoEndpoint,
+			full:        o.ipinfoFull,
+			tokens:      o.ipinfoTokens,
+			tokenFile:   newSecretFile(o.ipinfoTokenFile),
+		},
+		cloudflare: &cloudflareProvider{
+			httpFetcher: o.fetcher(ProviderCloudflare),
+			endpoints:   cloudflareTraceEndpoints,
+		},
+		overrides:    newOverrideTable(o.overridePaths),
+		cache:        o.cache,
+		negative:     newNegativeCache(o.negativeTTL),
+		health:       newHealthTable(),
+		adaptive:     o.adaptive,
+		preferOnline: o.preferOnline,
+
+		flightTimeout: o.timeout * time.Duration(o.retries+1) * flightProviders,
+	}
+	r.metrics = newMetrics(r)
+	r.log = newLogger(o.logger)
+	r.tracer = newTracer(o.tracerProvider)
+	r.rdns = newRDNSResolver(o.rdnsTimeout, o.rdnsTTL)
+	r.lookupStats = newLookupStats(o.statsWindow)
+	r.consensus = o.consensus
+	r.uppercaseCodes, r.noContinentFallback = o.uppercaseCodes, o.noContinentFallback
+	r.self = &selfIPDetector{services: o.selfIPServices, timeout: o.timeout, offline: o.offline}
+	if len(r.self.services) == 0 {
+		r.self.services = defaultSelfIPServices
+	}
+	r.overrides.log = r.log
+	for _, s := range append(slices.Clone(r.db), r.asn) {
+		s.fullVerify, s.inMemory, s.maxAge, s.log = o.fullVerify, o.dbInMemory, o.maxDBAge, r.log
+	}
+	r.builtins = map[string]Provider{
+		ProviderIPInfo:     r.ipinfo,
+		ProviderIPAPI:      &ipapiProvider{httpFetcher: o.fetcher(ProviderIPAPI), endpoint: ipapiEndpoint},
+		ProviderIPAPICo:    &ipapicoProvider{httpFetcher: o.fetcher(ProviderIPAPICo), endpoint: ipapicoEndpoint, key: o.ipapicoKey},
+		ProviderCloudflare: r.cloudflare,
+		ProviderMMDB:       &mmdbProvider{db: r.db},
+	}
+	if o.ip2locationPath != "" {
+		r.builtins[ProviderIP2Location] = &ip2locationProvider{path: o.ip2locationPath, log: r.log}
+	}
+	if o.maxmindAccountID != "" && (o.maxmindLicenseKey != "" || o.maxmindKeyFile != "") {
+		service := o.maxmindService
+		if service == "" {
+			service = "city"
+		}
+		r.builtins[ProviderMaxMind] = &maxmindProvider{
+			httpFetcher: o.fetcher(ProviderMaxMind),
+			endpoint:    maxmindEndpoint,
+			accountID:   o.maxmindAccountID,
+			licenseKey:  o.maxmindLicenseKey,
+			keyFile:     newSecretFile(o.maxmindKeyFile),
+			service:     service,
+		}
+	}
+
+	switch {
+	case o.providersSet:
+		r.providers = o.providers
+	case len(o.chain) > 0:
+		if err := r.SetChain(o.chain...); err != nil {
+			r.log.error("invalid provider chain, falling back to the default", "error", err)
+			r.providers = r.defaultChain(&o)
+		}
+	default:
+		r.providers = r.defaultChain(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.stop = cancel
+	if s, ok := r.cache.(saver); ok {
+		go r.saveLoop(ctx, s)
+	}
+	if o.healthInterval > 0 && !o.offline {
+		go r.healthLoop(ctx, o.healthInterval)
+	}
+	if o.watch && len(r.dbPaths()) > 0 {
+		go r.watchDB(ctx)
+	}
+	if o.update.url != "" && len(r.db[0].paths) > 0 {
+		u := o.update
+		u.path = r.db[0].paths[0]
+		u.client = o.httpClient
+		u.log = r.log
+		if u.interval <= 0 {
+			u.interval = defaultUpdateInterval
+		}
+		r.updater = &u
+		go r.updateLoop(ctx)
+	}
+	return r
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultResolver *Resolver
+)
+
+// defaultChain 返回未指定查询链时使用的默认顺序
+func (r *Resolver) defaultChain(o *options) []Provider {
+	// 本地数据库：配置了 IP2Location 时先查它，再查 mmdb
+	local := []Provider{r.builtins[ProviderMMDB]}
+	if p, ok := r.builtins[ProviderIP2Location]; ok {
+		local = slices.Insert(local, 0, p)
+	}
+	if o.offline {
+		return local
+	}
+
+	// 默认先查 ipinfo.io（配置了 ipapi.co 的 key 时改用 ipapi.co），失败后回退到本地数据库
+	// ip-api.com 的免费接口只支持明文 HTTP，不在默认查询链中，需要时通过 GEOIP_PROVIDERS 或 SetChain 启用
+	first := r.builtins[ProviderIPInfo]
+	if o.ipapicoKey != "" {
+		first = r.builtins[ProviderIPAPICo]
+	}
+	chain := append([]Provider{first}, local...)
+
+	// 商业账号的数据最准确，配置后最先查询
+	if p, ok := r.builtins[ProviderMaxMind]; ok {
+		chain = slices.Insert(chain, 0, p)
+	}
+	return chain
+}
+
+// Default 返回包级别函数使用的默认 Resolver
+func Default() *Resolver {
+	defaultOnce.Do(func() {
+		defaultResolver = NewFromEnv()
+	})
+	return defaultResolver
+}
+
+// NewFromEnv 按与默认实例相同的环境变量创建 Resolver，opts 在环境变量之后应用，可以覆盖环境变量的配置
+// 用于命令行工具等独立的进程，查询逻辑与面板完全相同
+func NewFromEnv(opts ...Option) *Resolver {
+	return New(append(envOptions(), opts...)...)
+}
+
+// Reload 重新选择并打开数据库，用于替换 mmdb 文件后无需重启即可生效
+// 新数据库打开失败时继续使用原来的数据库
+func (r *Resolver) Reload() error {
+	if p, ok := r.builtins[ProviderIP2Location].(*ip2locationProvider); ok {
+		p.close()
+	}
+	// 缓存的在线结果也用旧数据库补全过字段，一并清空
+	defer r.PurgeCache()
+	// ASN 数据库是可选的，不存在时不返回错误
+	r.asn.reload()
+	return r.db.reload()
+}
+
+// dbPaths 返回所有 mmdb 的候选路径，供文件监听使用
+func (r *Resolver) dbPaths() []string {
+	return append(r.db.paths(), r.asn.paths...)
+}
+
+// Close 关闭当前的数据库并释放资源，之后的查询会重新加载数据库；定时健康检查与文件监听也会停止，持久化的缓存会写入文件
+func (r *Resolver) Close() error {
+	r.stop()
+	if s, ok := r.cache.(saver); ok {
+		if err := s.Save(); err != nil {
+			r.log.error("failed to save cache", "error", err)
+		}
+	}
+	if p, ok := r.builtins[ProviderIP2Location].(*ip2locationProvider); ok {
+		p.close()
+	}
+	r.asn.close()
+	return r.db.close()
+}
+
+// Metadata 返回当前使用的数据库信息，用于确认外部 mmdb 是否被正确加载
+func (r *Resolver) Metadata() (*DBMetadata, error) {
+	md, err := r.db.metadata()
+	if err != nil {
+		return nil, err
+	}
+	if asn, err := r.asn.metadata(); err == nil {
+		md.ASN = asn
+	}
+	return md, nil
+}
+
+// LoadOverrides 从 YAML 或 CSV 文件加载 CIDR → 国家码覆盖表，替换当前的覆盖表
+// 文件类型由扩展名决定，.csv 按 CSV 解析，其余按 YAML 解析
+func (r *Resolver) LoadOverrides(path string) error {
+	return r.overrides.loadFile(path)
+}
+
+// SetOverrides 直接设置 CIDR → 国家码覆盖表，key 可以是 CIDR 或单个 IP
+func (r *Resolver) SetOverrides(table map[string]string) error {
+	return r.overrides.setTable(table)
+}
+
+// Reload 重新选择并打开默认实例的数据库，用于替换 /dashboard/data 下的 mmdb 后无需重启即可生效
+func Reload() error {
+	return Default().Reload()
+}
+
+// Close 关闭默认实例的数据库并释放资源
+func Close() error {
+	return Default().Close()
+}
+
+// Metadata 返回默认实例当前使用的数据库信息
+func Metadata() (*DBMetadata, error) {
+	return Default().Metadata()
+}
+
+// LoadOverrides 为默认实例从文件加载 CIDR 覆盖表
+func LoadOverrides(path string) error {
+	return Default().LoadOverrides(path)
+}
+
+// SetOverrides 为默认实例设置 CIDR 覆盖表
+func SetOverrides(table map[string]string) error {
+	return Default().SetOverrides(table)
+}
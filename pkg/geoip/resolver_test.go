@@ -0,0 +1,40 @@
+package geoip
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestOffline(t *testing.T) {
+	r := New(WithOffline(true), WithDBPaths())
+	if ps := r.Providers(); len(ps) != 1 || ps[0].Name() != ProviderMMDB {
+		t.Fatalf("offline chain = %v, expected only mmdb", ps)
+	}
+	if _, err := r.LookupASN(net.ParseIP("1.1.1.1")); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("offline LookupASN error = %v, expected ErrProviderUnavailable", err)
+	}
+}
+
+func TestNormalizationOptions(t *testing.T) {
+	continentOnly := &staticProvider{name: "continent", res: &Result{ContinentCode: "eu"}}
+	cases := []struct {
+		opts []Option
+		res  *Result
+		code string
+		err  error
+	}{
+		{nil, &Result{CountryCode: "fr"}, "fr", nil},
+		{[]Option{WithUppercaseCodes(true)}, &Result{CountryCode: "fr"}, "FR", nil},
+		{nil, continentOnly.res, "eu", nil},
+		{[]Option{WithUppercaseCodes(true)}, continentOnly.res, "EU", nil},
+		{[]Option{WithContinentFallback(false)}, continentOnly.res, "", ErrNotFound},
+	}
+	for i, c := range cases {
+		opts := append([]Option{WithProviders(&staticProvider{name: "static", res: c.res})}, c.opts...)
+		code, err := newTestResolver(t, opts...).Lookup(net.ParseIP("1.1.1.1"))
+		if code != c.code || !errors.Is(err, c.err) {
+			t.Fatalf("case %d: got %q, %v, want %q, %v", i, code, err, c.code, c.err)
+		}
+	}
+}
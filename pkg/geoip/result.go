@@ -0,0 +1,128 @@
+package geoip
+
+// 查询结果的数据来源，用于排查定位错误时判断是哪一层给出的结果
+const (
+	SourceIPInfo     = "ipinfo.io"
+	SourceExternalDB = "external-mmdb"
+	SourceEmbeddedDB = "embedded-db"
+)
+
+// Result 是对外暴露的完整查询结果，国家码与洲码统一为 2 位小写
+type Result struct {
+	CountryCode   string    `json:"country_code,omitempty"`
+	CountryName   string    `json:"country_name,omitempty"`
+	Flag          string    `json:"flag,omitempty"`  // 国旗 emoji
+	IsEU          bool      `json:"is_eu,omitempty"` // 是否为欧盟成员国
+	ContinentCode string    `json:"continent_code,omitempty"`
+	ContinentName string    `json:"continent_name,omitempty"`
+	City          *City     `json:"city,omitempty"`       // 仅城市级数据库提供
+	Location      *Location `json:"location,omitempty"`   // 仅带坐标的数据库提供
+	Timezone      string    `json:"timezone,omitempty"`   // IANA 时区，如 Asia/Hong_Kong
+	Privacy       *Privacy  `json:"privacy,omitempty"`    // 仅隐私检测数据库或 ipinfo /privacy 提供
+	ASN           *ASN      `json:"asn,omitempty"`        // 仅 ipinfo 完整 JSON 接口等提供
+	Hostname      string    `json:"hostname,omitempty"`   // 反向解析的主机名，仅 ipinfo 完整 JSON 接口提供
+	Network       string    `json:"network,omitempty"`    // 命中的网段，如 1.2.3.0/24，可用于按网段缓存
+	Source        string    `json:"source,omitempty"`     // 数据来源，见 Source* 常量
+	Private       bool      `json:"private,omitempty"`    // 内网或保留地址
+	Confidence    float64   `json:"confidence,omitempty"` // 仅 WithConsensus 模式下设置，与结果一致的数据源占比，小于 1 表示数据源之间存在分歧
+}
+
+// City 是城市级的位置信息
+type City struct {
+	Name        string `json:"name,omitempty"`
+	Subdivision string `json:"subdivision,omitempty"` // 省/州等一级行政区
+	PostalCode  string `json:"postal_code,omitempty"`
+}
+
+// Location 是经纬度坐标
+type Location struct {
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	AccuracyRadius uint16  `json:"accuracy_radius,omitempty"` // 精度半径，单位 km
+}
+
+// Privacy 是 VPN/代理/机房等隐私检测信息
+type Privacy struct {
+	Hosting bool   `json:"is_hosting"`        // 机房/云服务商 IP
+	VPN     bool   `json:"is_vpn"`            // 商业 VPN 出口
+	Proxy   bool   `json:"is_proxy"`          // 公开代理
+	Tor     bool   `json:"is_tor"`            // Tor 出口节点
+	Relay   bool   `json:"is_relay"`          // iCloud Private Relay 等中继
+	Service string `json:"service,omitempty"` // VPN 服务商名称，如 NordVPN
+}
+
+// ASN 是自治系统信息
+type ASN struct {
+	Number       uint   `json:"number,omitempty"`       // AS 号，如 13335
+	Organization string `json:"organization,omitempty"` // 组织名称，如 Cloudflare, Inc.
+	Domain       string `json:"domain,omitempty"`       // 组织的域名，如 cloudflare.com
+}
+
+// Code 返回国家码，查不到国家时以洲码兜底（极端情况用洲代码）
+func (r *Result) Code() string {
+	if r.CountryCode != "" {
+		return r.CountryCode
+	}
+	return r.ContinentCode
+}
+
+// found 判断结果中是否至少有国家码或洲码
+func (r *Result) found() bool {
+	return r != nil && (r.CountryCode != "" || r.ContinentCode != "")
+}
+
+// clone 返回 r 的深拷贝，修改返回值的 City 等指针字段不影响 r
+func (r *Result) clone() *Result {
+	res := *r
+	if r.City != nil {
+		city := *r.City
+		res.City = &city
+	}
+	if r.Location != nil {
+		loc := *r.Location
+		res.Location = &loc
+	}
+	if r.Privacy != nil {
+		privacy := *r.Privacy
+		res.Privacy = &privacy
+	}
+	if r.ASN != nil {
+		asn := *r.ASN
+		res.ASN = &asn
+	}
+	return &res
+}
+
+// fill 用 other 补全 r 中为空的字段，不覆盖已有的值
+func (r *Result) fill(other *Result) {
+	if r.CountryName == "" {
+		r.CountryName = other.CountryName
+	}
+	if r.ContinentCode == "" {
+		r.ContinentCode = other.ContinentCode
+	}
+	if r.ContinentName == "" {
+		r.ContinentName = other.ContinentName
+	}
+	if r.City == nil {
+		r.City = other.City
+	}
+	if r.Location == nil {
+		r.Location = other.Location
+	}
+	if r.Timezone == "" {
+		r.Timezone = other.Timezone
+	}
+	if r.Privacy == nil {
+		r.Privacy = other.Privacy
+	}
+	if r.Network == "" {
+		r.Network = other.Network
+	}
+	if r.ASN == nil {
+		r.ASN = other.ASN
+	}
+	if r.Hostname == "" {
+		r.Hostname = other.Hostname
+	}
+}
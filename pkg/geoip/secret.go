@@ -0,0 +1,66 @@
+package geoip
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 检查 token 文件是否被修改的间隔
+const secretCheckInterval = 30 * time.Second
+
+// secretFile 从文件读取 token，适用于 docker secrets 等不想把 token 写进环境变量的部署
+// 文件被修改后会在下一次使用时重新读取，无需重启面板
+type secretFile struct {
+	path string
+
+	mu      sync.Mutex
+	checked time.Time // 上一次检查文件的时间
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+func newSecretFile(path string) *secretFile {
+	if path == "" {
+		return nil
+	}
+	return &secretFile{path: path}
+}
+
+// get 返回文件内容去掉首尾空白后的值，文件无法读取时继续使用上一次读到的值
+func (s *secretFile) get() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !s.checked.IsZero() && now.Sub(s.checked) < secretCheckInterval {
+		return s.value
+	}
+	s.checked = now
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		log.Printf("NEZHA>> geoip: failed to read token file %s: %v", s.path, err)
+		return s.value
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.value
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		log.Printf("NEZHA>> geoip: failed to read token file %s: %v", s.path, err)
+		return s.value
+	}
+	if !s.modTime.IsZero() {
+		log.Printf("NEZHA>> geoip: token file %s changed, reloaded", s.path)
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+	s.value = strings.TrimSpace(string(data))
+	return s.value
+}
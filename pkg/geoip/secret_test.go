@@ -0,0 +1,38 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("tok1,tok2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := &ipinfoProvider{endpoint: "https://ipinfo.io", tokens: []string{"env"}, tokenFile: newSecretFile(path)}
+	ip := net.ParseIP("8.8.8.8")
+	if u := p.url(ip, "/country"); u != "https://ipinfo.io/8.8.8.8/country?token=tok1" {
+		t.Fatalf("url = %q", u)
+	}
+
+	// 文件修改后，超过检查间隔即重新读取
+	if err := os.WriteFile(path, []byte("tok3"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p.tokenFile.checked = time.Now().Add(-secretCheckInterval)
+	if u := p.url(ip, "/country"); !strings.HasSuffix(u, "token=tok3") {
+		t.Fatalf("url after reload = %q", u)
+	}
+
+	// 文件被删除时继续使用上一次读到的 token
+	os.Remove(path)
+	p.tokenFile.checked = time.Time{}
+	if u := p.url(ip, "/country"); !strings.HasSuffix(u, "token=tok3") {
+		t.Fatalf("url after removal = %q", u)
+	}
+}
@@ -0,0 +1,245 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSelfIPServices 是 SelfIP 默认按顺序尝试的服务，https:// 地址返回纯文本 IP 或 Cloudflare trace 格式，stun: 地址使用 STUN Binding 请求
+// 都是双栈服务，同一个地址分别通过 IPv4 与 IPv6 连接即可得到两个出口地址
+var defaultSelfIPServices = []string{
+	"https://www.cloudflare.com/cdn-cgi/trace",
+	"https://api64.ipify.org",
+	"https://icanhazip.com",
+	"stun:stun.l.google.com:19302",
+}
+
+// SelfAddr 是本机的一个公网出口地址及其定位结果
+type SelfAddr struct {
+	IP     net.IP  `json:"ip"`
+	Result *Result `json:"result,omitempty"` // 定位失败时为空
+}
+
+// SelfIPs 是 SelfIP 的结果，没有 IPv4 或 IPv6 出口时对应的字段为空
+type SelfIPs struct {
+	IPv4 *SelfAddr `json:"ipv4,omitempty"`
+	IPv6 *SelfAddr `json:"ipv6,omitempty"`
+}
+
+// selfIPDetector 通过外部服务获取本机的公网地址
+// 不使用 WithProxy 配置的代理，否则得到的是代理的出口地址
+type selfIPDetector struct {
+	services []string
+	timeout  time.Duration
+	offline  bool
+}
+
+// SelfIP 分别通过 IPv4 与 IPv6 连接 WithSelfIPServices 配置的服务，获取本机在 NAT 之后的公网地址，并查询各自的国家码
+// 按顺序尝试每个服务，某个协议栈没有公网出口时结果中对应的字段为空，两者都获取失败时返回错误
+func (r *Resolver) SelfIP(ctx context.Context) (*SelfIPs, error) {
+	if r.self.offline {
+		return nil, fmt.Errorf("%w: offline mode", ErrProviderUnavailable)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		out  SelfIPs
+		errs [2]error
+	)
+	for i, family := range []string{"4", "6"} {
+		wg.Go(func() {
+			ip, err := r.self.detect(ctx, family)
+			if err != nil {
+				errs[i] = fmt.Errorf("ipv%s: %w", family, err)
+				return
+			}
+			addr := &SelfAddr{IP: ip}
+			if res, err := r.LookupDetailContext(ctx, ip); err == nil {
+				addr.Result = res
+			}
+			if family == "4" {
+				out.IPv4 = addr
+			} else {
+				out.IPv6 = addr
+			}
+		})
+	}
+	wg.Wait()
+	if out.IPv4 == nil && out.IPv6 == nil {
+		return nil, errors.Join(errs[:]...)
+	}
+	return &out, nil
+}
+
+// detect 按顺序尝试各个服务，返回 family（4 或 6）协议栈的公网地址
+func (d *selfIPDetector) detect(ctx context.Context, family string) (net.IP, error) {
+	var errs []error
+	for _, service := range d.services {
+		sctx, cancel := context.WithTimeout(ctx, d.timeout)
+		var (
+			ip  net.IP
+			err error
+		)
+		if addr, ok := strings.CutPrefix(service, "stun:"); ok {
+			ip, err = stunBinding(sctx, "udp"+family, addr)
+		} else {
+			ip, err = echoIP(sctx, "tcp"+family, service)
+		}
+		cancel()
+		if err == nil && (ip.To4() != nil) != (family == "4") {
+			err = fmt.Errorf("got %s over ipv%s", ip, family)
+		}
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", service, err))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no self ip services configured")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// echoIP 只通过 network（tcp4 或 tcp6）请求 url，响应可以是纯文本 IP 或 Cloudflare trace 格式
+func echoIP(ctx context.Context, network, url string) (net.IP, error) {
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
+		ForceAttemptHTTP2: true,
+	}}
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(body, []byte("ip=")) {
+		ip, _, err := parseCloudflareTrace(body)
+		if ip != nil {
+			return ip, nil
+		}
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid response %q", bytes.TrimSpace(body))
+	}
+	return ip, nil
+}
+
+// STUN 协议的常量，见 RFC 5389
+const (
+	stunBindingRequest  = 0x0001
+	stunBindingResponse = 0x0101
+	stunMagicCookie     = 0x2112A442
+	stunMappedAddress   = 0x0001
+	stunXORMappedAddr   = 0x0020
+)
+
+// stunBinding 通过 network（udp4 或 udp6）向 STUN 服务器发送 Binding 请求，返回服务器看到的地址
+func stunBinding(ctx context.Context, network, addr string) (net.IP, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := make([]byte, 20)
+	binary.BigEndian.PutUint16(req[0:], stunBindingRequest)
+	binary.BigEndian.PutUint32(req[4:], stunMagicCookie)
+	rand.Read(req[8:20])
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return parseSTUNResponse(buf[:n], req[8:20])
+}
+
+// parseSTUNResponse 从 Binding 响应中读取 XOR-MAPPED-ADDRESS，没有时使用 MAPPED-ADDRESS
+func parseSTUNResponse(msg, txID []byte) (net.IP, error) {
+	if len(msg) < 20 || binary.BigEndian.Uint16(msg[0:]) != stunBindingResponse ||
+		binary.BigEndian.Uint32(msg[4:]) != stunMagicCookie || !bytes.Equal(msg[8:20], txID) {
+		return nil, errors.New("invalid stun response")
+	}
+	length := int(binary.BigEndian.Uint16(msg[2:]))
+	if 20+length > len(msg) {
+		return nil, errors.New("truncated stun response")
+	}
+
+	var mapped net.IP
+	attrs := msg[20 : 20+length]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		size := int(binary.BigEndian.Uint16(attrs[2:]))
+		if 4+size > len(attrs) {
+			break
+		}
+		value := attrs[4 : 4+size]
+		// 地址属性：1 字节保留，1 字节协议族（1 为 IPv4，2 为 IPv6），2 字节端口，之后为地址
+		if (typ == stunXORMappedAddr || typ == stunMappedAddress) && len(value) >= 8 {
+			ip := make(net.IP, 4)
+			if value[1] == 2 {
+				ip = make(net.IP, 16)
+			}
+			if len(value) < 4+len(ip) {
+				return nil, errors.New("invalid stun address")
+			}
+			copy(ip, value[4:])
+			if typ == stunXORMappedAddr {
+				// XOR-MAPPED-ADDRESS 与 magic cookie 及 transaction id 异或
+				key := msg[4:20]
+				for i := range ip {
+					ip[i] ^= key[i]
+				}
+				return ip, nil
+			}
+			mapped = ip
+		}
+		next := 4 + (size+3)&^3
+		if next > len(attrs) {
+			break
+		}
+		attrs = attrs[next:]
+	}
+	if mapped == nil {
+		return nil, errors.New("no mapped address in stun response")
+	}
+	return mapped, nil
+}
+
+// SelfIP 使用默认实例获取本机的公网地址，见 Resolver.SelfIP
+func SelfIP(ctx context.Context) (*SelfIPs, error) {
+	return Default().SelfIP(ctx)
+}
@@ -0,0 +1,62 @@
+package geoip
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelfIP(t *testing.T) {
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("198.51.100.9\n"))
+	}))
+	defer echo.Close()
+
+	// 返回客户端地址的 STUN 服务器
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil || n < 20 {
+				return
+			}
+			ip := addr.(*net.UDPAddr).IP.To4()
+			resp := make([]byte, 32)
+			binary.BigEndian.PutUint16(resp[0:], stunBindingResponse)
+			binary.BigEndian.PutUint16(resp[2:], 12)
+			copy(resp[4:20], buf[4:20])
+			binary.BigEndian.PutUint16(resp[20:], stunXORMappedAddr)
+			binary.BigEndian.PutUint16(resp[22:], 8)
+			resp[25] = 1
+			for i := range ip {
+				resp[28+i] = ip[i] ^ resp[4+i]
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	for _, service := range []string{echo.URL, "stun:" + conn.LocalAddr().String()} {
+		r := newTestResolver(t, WithProviders(), WithSelfIPServices(service), WithTimeout(time.Second))
+		self, err := r.SelfIP(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", service, err)
+		}
+		want := "198.51.100.9"
+		if strings.HasPrefix(service, "stun:") {
+			want = "127.0.0.1"
+		}
+		if self.IPv4 == nil || self.IPv4.IP.String() != want || self.IPv6 != nil {
+			t.Fatalf("%s: unexpected result %+v", service, self)
+		}
+	}
+}
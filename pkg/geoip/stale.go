@@ -0,0 +1,47 @@
+package geoip
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxDBAge 是数据库的默认最长使用时间，超过后记录警告，0 为不检查
+const defaultMaxDBAge = 90 * 24 * time.Hour
+
+// isStale 判断构建时间为 built 的数据库是否已超过 maxAge
+func (s *mmdbStore) isStale(built time.Time) bool {
+	return s.maxAge > 0 && time.Since(built) > s.maxAge
+}
+
+// stale 判断当前使用的数据库是否过旧，数据库不可用时返回 false
+func (s *mmdbStore) stale() bool {
+	md, err := s.metadata()
+	return err == nil && md.Stale
+}
+
+// demoteStale 数据库过旧且开启了 WithPreferOnlineWhenStale 时，将 mmdb 移到查询链末尾，优先使用在线数据源
+func (r *Resolver) demoteStale(chain []Provider) []Provider {
+	if !r.preferOnline || !r.db[0].stale() {
+		return chain
+	}
+	i := slices.IndexFunc(chain, func(p Provider) bool { return p.Name() == ProviderMMDB })
+	if i < 0 || i == len(chain)-1 {
+		return chain
+	}
+	sorted := slices.Delete(slices.Clone(chain), i, i+1)
+	return append(sorted, chain[i])
+}
+
+// parseAge 解析时长，在 time.ParseDuration 的基础上支持 "90d" 这样以天为单位的写法
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
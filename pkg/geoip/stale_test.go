@@ -0,0 +1,26 @@
+package geoip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"90d", 90 * 24 * time.Hour, true},
+		{"36h", 36 * time.Hour, true},
+		{"0", 0, true},
+		{"xd", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		got, err := parseAge(c.in)
+		if (err == nil) != c.ok || got != c.want {
+			t.Errorf("parseAge(%q) = %v, %v", c.in, got, err)
+		}
+	}
+}
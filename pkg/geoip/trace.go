@@ -0,0 +1,50 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/nezhahq/nezha/pkg/geoip"
+
+// startSpan 在 ctx 中已有正在记录的 span 时创建子 span，如面板 API 请求中的查询
+// 没有接入链路追踪或后台任务中的查询直接返回 ctx 中不记录的 span，不产生额外开销，调用方设置属性前应先检查 IsRecording
+func (r *Resolver) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.IsRecording() {
+		return ctx, parent
+	}
+	return r.tracer.Start(ctx, name)
+}
+
+// endSpan 记录查询结果并结束 span，查不到数据不视为错误
+func endSpan(span trace.Span, res *Result, err error) {
+	if !span.IsRecording() {
+		return
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		span.SetAttributes(attribute.Bool("geoip.found", false))
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case res != nil:
+		span.SetAttributes(
+			attribute.String("geoip.result", res.Code()),
+			attribute.String("geoip.source", res.Source),
+		)
+	}
+	span.End()
+}
+
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
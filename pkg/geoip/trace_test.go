@@ -0,0 +1,66 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan 记录 span 的名称与属性，用于检查查询创建的 span
+type recordingSpan struct {
+	noop.Span
+	name  string
+	attrs []attribute.KeyValue
+}
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) { s.attrs = append(s.attrs, kv...) }
+
+type recordingTracer struct {
+	noop.Tracer
+	spans []*recordingSpan
+}
+
+type recordingProvider struct {
+	noop.TracerProvider
+	tracer *recordingTracer
+}
+
+func (p recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer { return p.tracer }
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &recordingSpan{name: name}
+	t.spans = append(t.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &recordingTracer{}
+	r := New(WithProviders(&staticProvider{name: "static", res: &Result{CountryCode: "fr", Source: "static"}}), WithTracerProvider(recordingProvider{tracer: tracer}))
+
+	// 没有父 span 时不创建 span
+	r.Lookup(net.ParseIP("1.1.1.1"))
+	if len(tracer.spans) != 0 {
+		t.Fatalf("created %d spans without a parent span", len(tracer.spans))
+	}
+
+	ctx, _ := tracer.Start(context.Background(), "request")
+	r.LookupContext(ctx, net.ParseIP("1.1.1.1"))
+	var names []string
+	for _, s := range tracer.spans {
+		names = append(names, s.name)
+	}
+	if !slices.Equal(names, []string{"request", "geoip.Lookup", "geoip.provider", "geoip.mmdb"}) {
+		t.Fatalf("spans = %v", names)
+	}
+	provider := tracer.spans[2].attrs
+	if !slices.Contains(provider, attribute.String("geoip.provider", "static")) || !slices.Contains(provider, attribute.String("geoip.result", "fr")) {
+		t.Fatalf("provider span attributes = %v", provider)
+	}
+}
@@ -0,0 +1,235 @@
+package geoip
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+// 自动更新的默认间隔与单次下载的超时
+const (
+	defaultUpdateInterval = 24 * time.Hour
+	updateTimeout         = 10 * time.Minute
+)
+
+// updater 定时下载新的 mmdb，校验后原子替换本地文件并重新加载
+// 支持直接下载 .mmdb、.mmdb.gz 以及 MaxMind 提供的 .tar.gz 压缩包，例如：
+//   - https://ipinfo.io/data/ipinfo_lite.mmdb?token=xxx
+//   - https://download.maxmind.com/app/geoip_download?edition_id=GeoLite2-Country&license_key=xxx&suffix=tar.gz
+type updater struct {
+	url         string
+	checksumURL string // 可选，内容为 sha256，格式如 "<hex>  文件名"，MaxMind 的下载链接加上 .sha256 后缀即可
+	path        string // 下载后写入的路径
+	interval    time.Duration
+	client      *http.Client
+	log         *logger
+}
+
+// update 下载并替换数据库，返回是否有新的数据库
+// 本地文件存在时带上 If-Modified-Since，服务端返回 304 时不重复下载
+func (u *updater) update(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url, nil)
+	if err != nil {
+		return false, err
+	}
+	if info, err := os.Stat(u.path); err == nil {
+		req.Header.Set("If-Modified-Since", info.ModTime().UTC().Format(http.TimeFormat))
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("geoip: download database: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return false, nil
+	default:
+		return false, fmt.Errorf("geoip: download database: status %d", resp.StatusCode)
+	}
+
+	var want string
+	if u.checksumURL != "" {
+		if want, err = u.checksum(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// 先写入同一目录下的临时文件，校验通过后再 rename，查询不会读到写了一半的文件
+	if err := os.MkdirAll(filepath.Dir(u.path), 0o755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(u.path), ".geoip-*.mmdb")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	sum := sha256.New()
+	body := io.TeeReader(resp.Body, sum)
+	err = extractMMDB(body, tmp, u.url)
+	if err == nil {
+		// tar 包中找到 .mmdb 后可能还有剩余内容，读完以保证校验和覆盖下载的全部数据
+		_, err = io.Copy(io.Discard, body)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, fmt.Errorf("geoip: download database: %w", err)
+	}
+	if want != "" {
+		if got := hex.EncodeToString(sum.Sum(nil)); !strings.EqualFold(got, want) {
+			return false, fmt.Errorf("geoip: checksum mismatch: got %s, expected %s", got, want)
+		}
+	}
+	if err := verifyMMDB(tmp.Name(), u.log); err != nil {
+		return false, err
+	}
+
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		os.Chtimes(tmp.Name(), lastModified, lastModified)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), u.path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checksum 获取下载文件的 sha256
+func (u *updater) checksum(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.checksumURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("geoip: download checksum: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("geoip: download checksum: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("geoip: download checksum: %w", err)
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("geoip: invalid checksum %q", body)
+	}
+	return fields[0], nil
+}
+
+// extractMMDB 将下载的内容写入 w，gzip 压缩的内容会先解压，tar 包中取第一个 .mmdb 文件
+func extractMMDB(r io.Reader, w io.Writer, name string) error {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	// tar 包的第 257 字节开始是 "ustar" 标识
+	if header, err := br.Peek(262); err == nil && string(header[257:262]) == "ustar" {
+		tr := tar.NewReader(br)
+		for {
+			h, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("no .mmdb file in archive %s", name)
+			}
+			if err != nil {
+				return err
+			}
+			if h.Typeflag == tar.TypeReg && strings.HasSuffix(h.Name, ".mmdb") {
+				_, err = io.Copy(w, tr)
+				return err
+			}
+		}
+	}
+
+	_, err := io.Copy(w, br)
+	return err
+}
+
+// verifyMMDB 确认文件是完整可用的 mmdb，下载的文件只检查一次，因此总是完整校验
+func verifyMMDB(path string, lg *logger) error {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return fmt.Errorf("geoip: downloaded file is not a valid database: %w", err)
+	}
+	defer reader.Close()
+	if err := verifyReader(reader, path, true, lg); err != nil {
+		return fmt.Errorf("geoip: downloaded file is not a valid database: %w", err)
+	}
+	return nil
+}
+
+// updateLoop 启动时如果本地数据库不存在或已超过 interval 则立即更新，之后每隔 interval 更新一次
+func (r *Resolver) updateLoop(ctx context.Context) {
+	u := r.updater
+	next := time.Duration(0)
+	if info, err := os.Stat(u.path); err == nil {
+		next = max(u.interval-time.Since(info.ModTime()), 0)
+	}
+
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if err := r.Update(ctx); err != nil && ctx.Err() == nil {
+			r.log.error("failed to update database", "error", err)
+		}
+		timer.Reset(u.interval)
+	}
+}
+
+// Update 立即下载一次数据库，下载成功后重新加载；未配置下载地址时返回错误
+func (r *Resolver) Update(ctx context.Context) error {
+	if r.updater == nil {
+		return errors.New("geoip: database update url is not configured")
+	}
+	updated, err := r.updater.update(ctx)
+	if err != nil || !updated {
+		return err
+	}
+	r.log.info("downloaded new database", "path", r.updater.path)
+	defer r.PurgeCache()
+	return r.db.reload()
+}
+
+// Update 立即更新默认实例的数据库，见 Resolver.Update
+func Update(ctx context.Context) error {
+	return Default().Update(ctx)
+}
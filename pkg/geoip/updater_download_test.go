@@ -0,0 +1,89 @@
+package geoip_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "new.mmdb")
+	writeCountryDB(t, newPath, map[string]string{"1.0.0.0/8": "jp"})
+	newDB, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.mmdb":
+			w.Write(newDB)
+		case "/corrupt.mmdb":
+			w.Write([]byte("not a database"))
+		case "/truncated.mmdb":
+			w.Write(newDB[:len(newDB)/2])
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ip := net.ParseIP("1.1.1.1")
+	for _, c := range []struct {
+		name, url, err string
+	}{
+		{"non-200", srv.URL + "/missing.mmdb", "status 503"},
+		{"corrupt", srv.URL + "/corrupt.mmdb", "not a valid database"},
+		{"truncated", srv.URL + "/truncated.mmdb", "not a valid database"},
+		{"ok", srv.URL + "/ok.mmdb", ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			caseDir := t.TempDir()
+			path := filepath.Join(caseDir, "country.mmdb")
+			writeCountryDB(t, path, map[string]string{"1.0.0.0/8": "au"})
+			old, _ := os.ReadFile(path)
+			r := newDBResolver(t, path, geoip.WithAutoUpdate(c.url, "", time.Hour))
+			if code, _ := r.Lookup(ip); code != "au" {
+				t.Fatalf("Lookup before update = %q, expected au", code)
+			}
+
+			err := r.Update(context.Background())
+			current, _ := os.ReadFile(path)
+			code, _ := r.Lookup(ip)
+			if c.err != "" {
+				// 下载失败或文件无效时保留原来的数据库
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("Update error = %v, expected %q", err, c.err)
+				}
+				if !bytes.Equal(current, old) || code != "au" {
+					t.Fatalf("database replaced after a failed update, Lookup = %q", code)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Update: %v", err)
+				}
+				if !bytes.Equal(current, newDB) || code != "jp" {
+					t.Fatalf("database not replaced after update, Lookup = %q", code)
+				}
+			}
+
+			// 临时文件总是被清理
+			entries, _ := os.ReadDir(caseDir)
+			for _, e := range entries {
+				if e.Name() != "country.mmdb" {
+					t.Fatalf("leftover file %s after update", e.Name())
+				}
+			}
+		})
+	}
+}
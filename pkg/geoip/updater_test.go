@@ -0,0 +1,52 @@
+package geoip
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractMMDB(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "GeoLite2-Country_20260101/GeoLite2-Country.mmdb", Mode: 0o644, Size: 9, Typeflag: tar.TypeReg})
+	tw.Write([]byte("mmdb data"))
+	tw.Close()
+	gz.Close()
+
+	for name, input := range map[string][]byte{
+		"plain":  []byte("mmdb data"),
+		"tar.gz": archive.Bytes(),
+	} {
+		var out bytes.Buffer
+		if err := extractMMDB(bytes.NewReader(input), &out, name); err != nil || out.String() != "mmdb data" {
+			t.Fatalf("extractMMDB(%s) = %q, %v", name, out.String(), err)
+		}
+	}
+
+	// 校验和不一致时不替换本地文件
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			w.Write([]byte(strings.Repeat("0", 64) + "  GeoLite2-Country.tar.gz\n"))
+			return
+		}
+		w.Write(archive.Bytes())
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "GeoLite2-Country.mmdb")
+	u := &updater{url: srv.URL + "/db", checksumURL: srv.URL + "/db.sha256", path: path, client: srv.Client()}
+	if updated, err := u.update(context.Background()); updated || err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("update = %v, %v, expected checksum mismatch", updated, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("database should not be written, stat: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+)
+
+// canaries 是打开数据库后检查的地址，country 为空时只检查记录能否正常解码
+// 只包含一部分网段的数据库（如自己编译的纠错库）查不到这些地址是正常的，只有查到了错误的国家才警告
+var canaries = []struct {
+	ip      string
+	country string
+}{
+	{"8.8.8.8", "us"},
+	{"1.1.1.1", ""},
+	{"2001:4860:4860::8888", "us"},
+}
+
+// verifyReader 在使用数据库之前检查 metadata 与几个固定地址的查询结果，返回错误时不使用这个数据库
+// full 为 true 时额外遍历整个搜索树与数据区，大的城市库需要数秒
+func verifyReader(db *maxminddb.Reader, name string, full bool, lg *logger) error {
+	md := db.Metadata
+	switch {
+	case md.BinaryFormatMajorVersion != 2:
+		return fmt.Errorf("unsupported binary format version %d", md.BinaryFormatMajorVersion)
+	case md.DatabaseType == "":
+		return errors.New("missing database type")
+	case md.NodeCount == 0:
+		return errors.New("empty search tree")
+	case md.RecordSize != 24 && md.RecordSize != 28 && md.RecordSize != 32:
+		return fmt.Errorf("invalid record size %d", md.RecordSize)
+	case md.IPVersion != 4 && md.IPVersion != 6:
+		return fmt.Errorf("invalid ip version %d", md.IPVersion)
+	}
+
+	// 截断或损坏的文件通常在查询时才会出现解码错误
+	for _, c := range canaries {
+		ip := net.ParseIP(c.ip)
+		if ip.To4() == nil && md.IPVersion == 4 {
+			continue
+		}
+		_, country, err := lookupCountry(db, ip)
+		if err != nil {
+			return fmt.Errorf("lookup %s: %w", c.ip, err)
+		}
+		if c.country != "" && country != "" && country != c.country {
+			lg.warn("database returns an unexpected country, results may be wrong", "database", name, "ip", c.ip, "country", country, "expected", c.country)
+		}
+	}
+
+	if full {
+		if err := db.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// warmInterval 是预热时两次查询的间隔，避免启动时短时间内大量请求在线接口
+const warmInterval = 100 * time.Millisecond
+
+// Warm 依次查询 ips 并写入缓存，见 Resolver.WarmContext
+func (r *Resolver) Warm(ips []net.IP) int {
+	return r.WarmContext(context.Background(), ips)
+}
+
+// WarmContext 依次查询 ips，让结果进入缓存，面板首次打开时不需要等待大量冷查询
+// 每次查询之间间隔 warmInterval，重复的 IP 与内网地址会被跳过，ctx 取消时提前返回
+// 返回查询成功的 IP 数；未设置缓存时查询结果不会被保存，调用没有意义
+func (r *Resolver) WarmContext(ctx context.Context, ips []net.IP) int {
+	seen := make(map[string]bool, len(ips))
+	ticker := time.NewTicker(warmInterval)
+	defer ticker.Stop()
+
+	warmed := 0
+	for _, ip := range ips {
+		if ip == nil || IsBogon(ip) || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		if _, err := r.LookupDetailContext(ctx, ip); err == nil {
+			warmed++
+		}
+		select {
+		case <-ctx.Done():
+			return warmed
+		case <-ticker.C:
+		}
+	}
+	return warmed
+}
+
+// Warm 使用默认实例预热缓存
+func Warm(ips []net.IP) int {
+	return Default().Warm(ips)
+}
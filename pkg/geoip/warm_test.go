@@ -0,0 +1,20 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWarm(t *testing.T) {
+	p := &countingProvider{staticProvider: staticProvider{name: "static", res: &Result{CountryCode: "fr"}}}
+	r := New(WithProviders(p), WithCache(NewLRUCache(10, time.Hour)))
+	ips := []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("10.0.0.1"), net.ParseIP("1.1.1.1"), net.ParseIP("8.8.8.8")}
+	if n := r.Warm(ips); n != 2 || p.calls != 2 {
+		t.Fatalf("Warm = %d, calls = %d", n, p.calls)
+	}
+	r.Lookup(net.ParseIP("8.8.8.8"))
+	if p.calls != 2 {
+		t.Fatalf("warmed ip should be cached, calls = %d", p.calls)
+	}
+}
@@ -0,0 +1,70 @@
+package geoip
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 替换数据库时往往会连续触发多个事件，等待这段时间没有新事件后再重新加载
+const watchDebounce = time.Second
+
+// watchDB 监听候选数据库所在的目录，文件被创建、替换、修改或删除时重新加载数据库，直到 ctx 被取消
+// 监听目录而不是文件本身，这样通过 mv 原子替换文件或者首次放入数据库时也能收到事件
+func (r *Resolver) watchDB(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.log.error("failed to watch databases", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	paths := r.dbPaths()
+	var dirs []string
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if slices.Contains(dirs, dir) {
+			continue
+		}
+		// 目录不存在时不监听，比如非 docker 部署下的 /dashboard/data
+		if err := watcher.Add(dir); err != nil {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) || !slices.Contains(paths, filepath.Clean(event.Name)) {
+				continue
+			}
+			timer.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.log.warn("database watcher", "error", err)
+		case <-timer.C:
+			// 国家库与 ASN 库一起重新加载，Reload 同时清空缓存
+			if err := r.Reload(); err != nil {
+				r.log.error("failed to reload database", "error", err)
+			}
+		}
+	}
+}
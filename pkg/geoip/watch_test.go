@@ -0,0 +1,75 @@
+package geoip_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+// replaceAndWait 替换数据库文件后等待 cond 成立，用于等待文件监听的去抖结束
+// 监听在后台开始，第一次替换可能早于监听，因此没有生效时会再替换一次
+func replaceAndWait(t *testing.T, replace func(), cond func() bool) bool {
+	t.Helper()
+	time.Sleep(100 * time.Millisecond)
+	for range 3 {
+		replace()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if cond() {
+				return true
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	return false
+}
+
+func TestWatchReloadsASNDB(t *testing.T) {
+	dir := t.TempDir()
+	countryPath, asnPath := filepath.Join(dir, "country.mmdb"), filepath.Join(dir, "asn.mmdb")
+	writeCountryDB(t, countryPath, map[string]string{"1.0.0.0/8": "au"})
+	writeRecordDB(t, asnPath, "GeoLite2-ASN", map[string]builder.Record{"1.0.0.0/8": asnRecord(13335, "Cloudflare, Inc.")})
+	r := newDBResolver(t, countryPath, geoip.WithASNDBPaths(asnPath), geoip.WithWatch(true))
+
+	ip := net.ParseIP("1.1.1.1")
+	if asn, err := r.LookupASNLocal(ip); err != nil || asn.Number != 13335 {
+		t.Fatalf("LookupASNLocal = %+v, %v", asn, err)
+	}
+
+	// 只替换 ASN 库时也应重新加载
+	if !replaceAndWait(t, func() {
+		writeRecordDB(t, asnPath, "GeoLite2-ASN", map[string]builder.Record{"1.0.0.0/8": asnRecord(15169, "Google LLC")})
+	}, func() bool {
+		asn, err := r.LookupASNLocal(ip)
+		return err == nil && asn.Number == 15169
+	}) {
+		asn, err := r.LookupASNLocal(ip)
+		t.Fatalf("LookupASNLocal after replacing the ASN database = %+v, %v, expected AS15169", asn, err)
+	}
+}
+
+func TestWatchReloadsDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	writeCountryDB(t, path, map[string]string{"1.0.0.0/8": "au"})
+	r := newDBResolver(t, path, geoip.WithWatch(true))
+
+	ip := net.ParseIP("1.1.1.1")
+	if code, err := r.Lookup(ip); err != nil || code != "au" {
+		t.Fatalf("Lookup = %q, %v, expected au", code, err)
+	}
+
+	// 通过 mv 原子替换数据库后，去抖结束时查询使用新数据
+	if !replaceAndWait(t, func() {
+		writeCountryDB(t, path, map[string]string{"1.0.0.0/8": "jp"})
+	}, func() bool {
+		code, err := r.Lookup(ip)
+		return err == nil && code == "jp"
+	}) {
+		code, err := r.Lookup(ip)
+		t.Fatalf("Lookup after replacing the database = %q, %v, expected jp", code, err)
+	}
+}
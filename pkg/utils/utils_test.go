@@ -180,3 +180,25 @@ func TestUnique(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := ParsePrefixes("10.0.0.0/8, 192.168.1.7", "[::1]", "2001:db8::1/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8", "192.168.1.7/32", "::1/128", "2001:db8::/32"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("ParsePrefixes = %v", prefixes)
+	}
+	for i, p := range prefixes {
+		if p.String() != want[i] {
+			t.Fatalf("ParsePrefixes[%d] = %s, expected %s", i, p, want[i])
+		}
+	}
+
+	for _, v := range []string{"10.0.0.0/33", "localhost", "1.2.3"} {
+		if _, err := ParsePrefixes(v); err == nil {
+			t.Fatalf("expected error for %q", v)
+		}
+	}
+}
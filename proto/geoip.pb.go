@@ -0,0 +1,550 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/geoip.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GeoIPLookupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoIPLookupRequest) Reset() {
+	*x = GeoIPLookupRequest{}
+	mi := &file_proto_geoip_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPLookupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPLookupRequest) ProtoMessage() {}
+
+func (x *GeoIPLookupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPLookupRequest.ProtoReflect.Descriptor instead.
+func (*GeoIPLookupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GeoIPLookupRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type GeoIPBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ips           []string               `protobuf:"bytes,1,rep,name=ips,proto3" json:"ips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoIPBatchRequest) Reset() {
+	*x = GeoIPBatchRequest{}
+	mi := &file_proto_geoip_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPBatchRequest) ProtoMessage() {}
+
+func (x *GeoIPBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPBatchRequest.ProtoReflect.Descriptor instead.
+func (*GeoIPBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GeoIPBatchRequest) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+type GeoIPResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Ip             string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	CountryCode    string                 `protobuf:"bytes,2,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	CountryName    string                 `protobuf:"bytes,3,opt,name=country_name,json=countryName,proto3" json:"country_name,omitempty"`
+	ContinentCode  string                 `protobuf:"bytes,4,opt,name=continent_code,json=continentCode,proto3" json:"continent_code,omitempty"`
+	ContinentName  string                 `protobuf:"bytes,5,opt,name=continent_name,json=continentName,proto3" json:"continent_name,omitempty"`
+	IsEu           bool                   `protobuf:"varint,6,opt,name=is_eu,json=isEu,proto3" json:"is_eu,omitempty"`
+	City           string                 `protobuf:"bytes,7,opt,name=city,proto3" json:"city,omitempty"`
+	Subdivision    string                 `protobuf:"bytes,8,opt,name=subdivision,proto3" json:"subdivision,omitempty"`
+	Latitude       float64                `protobuf:"fixed64,9,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude      float64                `protobuf:"fixed64,10,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Timezone       string                 `protobuf:"bytes,11,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Asn            uint64                 `protobuf:"varint,12,opt,name=asn,proto3" json:"asn,omitempty"`
+	AsOrganization string                 `protobuf:"bytes,13,opt,name=as_organization,json=asOrganization,proto3" json:"as_organization,omitempty"`
+	Network        string                 `protobuf:"bytes,14,opt,name=network,proto3" json:"network,omitempty"`
+	Source         string                 `protobuf:"bytes,15,opt,name=source,proto3" json:"source,omitempty"`
+	Private        bool                   `protobuf:"varint,16,opt,name=private,proto3" json:"private,omitempty"`
+	Error          string                 `protobuf:"bytes,17,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GeoIPResult) Reset() {
+	*x = GeoIPResult{}
+	mi := &file_proto_geoip_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPResult) ProtoMessage() {}
+
+func (x *GeoIPResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPResult.ProtoReflect.Descriptor instead.
+func (*GeoIPResult) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GeoIPResult) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetCountryName() string {
+	if x != nil {
+		return x.CountryName
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetContinentCode() string {
+	if x != nil {
+		return x.ContinentCode
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetContinentName() string {
+	if x != nil {
+		return x.ContinentName
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetIsEu() bool {
+	if x != nil {
+		return x.IsEu
+	}
+	return false
+}
+
+func (x *GeoIPResult) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetSubdivision() string {
+	if x != nil {
+		return x.Subdivision
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoIPResult) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *GeoIPResult) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetAsn() uint64 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *GeoIPResult) GetAsOrganization() string {
+	if x != nil {
+		return x.AsOrganization
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GeoIPResult) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *GeoIPResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GeoIPBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*GeoIPResult         `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoIPBatchResponse) Reset() {
+	*x = GeoIPBatchResponse{}
+	mi := &file_proto_geoip_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPBatchResponse) ProtoMessage() {}
+
+func (x *GeoIPBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPBatchResponse.ProtoReflect.Descriptor instead.
+func (*GeoIPBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GeoIPBatchResponse) GetResults() []*GeoIPResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GeoIPMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoIPMetadataRequest) Reset() {
+	*x = GeoIPMetadataRequest{}
+	mi := &file_proto_geoip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPMetadataRequest) ProtoMessage() {}
+
+func (x *GeoIPMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GeoIPMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{4}
+}
+
+type GeoIPMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	DatabaseType  string                 `protobuf:"bytes,3,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	BuildTime     uint64                 `protobuf:"varint,4,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	Stale         bool                   `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	Chain         []string               `protobuf:"bytes,6,rep,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoIPMetadata) Reset() {
+	*x = GeoIPMetadata{}
+	mi := &file_proto_geoip_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIPMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIPMetadata) ProtoMessage() {}
+
+func (x *GeoIPMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_geoip_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIPMetadata.ProtoReflect.Descriptor instead.
+func (*GeoIPMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_geoip_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GeoIPMetadata) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GeoIPMetadata) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GeoIPMetadata) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *GeoIPMetadata) GetBuildTime() uint64 {
+	if x != nil {
+		return x.BuildTime
+	}
+	return 0
+}
+
+func (x *GeoIPMetadata) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GeoIPMetadata) GetChain() []string {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+var File_proto_geoip_proto protoreflect.FileDescriptor
+
+const file_proto_geoip_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/geoip.proto\x12\x05proto\"$\n" +
+	"\x12GeoIPLookupRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"%\n" +
+	"\x11GeoIPBatchRequest\x12\x10\n" +
+	"\x03ips\x18\x01 \x03(\tR\x03ips\"\xef\x03\n" +
+	"\vGeoIPResult\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12!\n" +
+	"\fcountry_code\x18\x02 \x01(\tR\vcountryCode\x12!\n" +
+	"\fcountry_name\x18\x03 \x01(\tR\vcountryName\x12%\n" +
+	"\x0econtinent_code\x18\x04 \x01(\tR\rcontinentCode\x12%\n" +
+	"\x0econtinent_name\x18\x05 \x01(\tR\rcontinentName\x12\x13\n" +
+	"\x05is_eu\x18\x06 \x01(\bR\x04isEu\x12\x12\n" +
+	"\x04city\x18\a \x01(\tR\x04city\x12 \n" +
+	"\vsubdivision\x18\b \x01(\tR\vsubdivision\x12\x1a\n" +
+	"\blatitude\x18\t \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\n" +
+	" \x01(\x01R\tlongitude\x12\x1a\n" +
+	"\btimezone\x18\v \x01(\tR\btimezone\x12\x10\n" +
+	"\x03asn\x18\f \x01(\x04R\x03asn\x12'\n" +
+	"\x0fas_organization\x18\r \x01(\tR\x0easOrganization\x12\x18\n" +
+	"\anetwork\x18\x0e \x01(\tR\anetwork\x12\x16\n" +
+	"\x06source\x18\x0f \x01(\tR\x06source\x12\x18\n" +
+	"\aprivate\x18\x10 \x01(\bR\aprivate\x12\x14\n" +
+	"\x05error\x18\x11 \x01(\tR\x05error\"B\n" +
+	"\x12GeoIPBatchResponse\x12,\n" +
+	"\aresults\x18\x01 \x03(\v2\x12.proto.GeoIPResultR\aresults\"\x16\n" +
+	"\x14GeoIPMetadataRequest\"\xab\x01\n" +
+	"\rGeoIPMetadata\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12#\n" +
+	"\rdatabase_type\x18\x03 \x01(\tR\fdatabaseType\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x04 \x01(\x04R\tbuildTime\x12\x14\n" +
+	"\x05stale\x18\x05 \x01(\bR\x05stale\x12\x14\n" +
+	"\x05chain\x18\x06 \x03(\tR\x05chain2\xd0\x01\n" +
+	"\fGeoIPService\x129\n" +
+	"\x06Lookup\x12\x19.proto.GeoIPLookupRequest\x1a\x12.proto.GeoIPResult\"\x00\x12D\n" +
+	"\vBatchLookup\x12\x18.proto.GeoIPBatchRequest\x1a\x19.proto.GeoIPBatchResponse\"\x00\x12?\n" +
+	"\bMetadata\x12\x1b.proto.GeoIPMetadataRequest\x1a\x14.proto.GeoIPMetadata\"\x00B\tZ\a./protob\x06proto3"
+
+var (
+	file_proto_geoip_proto_rawDescOnce sync.Once
+	file_proto_geoip_proto_rawDescData []byte
+)
+
+func file_proto_geoip_proto_rawDescGZIP() []byte {
+	file_proto_geoip_proto_rawDescOnce.Do(func() {
+		file_proto_geoip_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_geoip_proto_rawDesc), len(file_proto_geoip_proto_rawDesc)))
+	})
+	return file_proto_geoip_proto_rawDescData
+}
+
+var file_proto_geoip_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_geoip_proto_goTypes = []any{
+	(*GeoIPLookupRequest)(nil),   // 0: proto.GeoIPLookupRequest
+	(*GeoIPBatchRequest)(nil),    // 1: proto.GeoIPBatchRequest
+	(*GeoIPResult)(nil),          // 2: proto.GeoIPResult
+	(*GeoIPBatchResponse)(nil),   // 3: proto.GeoIPBatchResponse
+	(*GeoIPMetadataRequest)(nil), // 4: proto.GeoIPMetadataRequest
+	(*GeoIPMetadata)(nil),        // 5: proto.GeoIPMetadata
+}
+var file_proto_geoip_proto_depIdxs = []int32{
+	2, // 0: proto.GeoIPBatchResponse.results:type_name -> proto.GeoIPResult
+	0, // 1: proto.GeoIPService.Lookup:input_type -> proto.GeoIPLookupRequest
+	1, // 2: proto.GeoIPService.BatchLookup:input_type -> proto.GeoIPBatchRequest
+	4, // 3: proto.GeoIPService.Metadata:input_type -> proto.GeoIPMetadataRequest
+	2, // 4: proto.GeoIPService.Lookup:output_type -> proto.GeoIPResult
+	3, // 5: proto.GeoIPService.BatchLookup:output_type -> proto.GeoIPBatchResponse
+	5, // 6: proto.GeoIPService.Metadata:output_type -> proto.GeoIPMetadata
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_geoip_proto_init() }
+func file_proto_geoip_proto_init() {
+	if File_proto_geoip_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_geoip_proto_rawDesc), len(file_proto_geoip_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_geoip_proto_goTypes,
+		DependencyIndexes: file_proto_geoip_proto_depIdxs,
+		MessageInfos:      file_proto_geoip_proto_msgTypes,
+	}.Build()
+	File_proto_geoip_proto = out.File
+	file_proto_geoip_proto_goTypes = nil
+	file_proto_geoip_proto_depIdxs = nil
+}
@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v5.29.3
+// source: proto/geoip.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	GeoIPService_Lookup_FullMethodName      = "/proto.GeoIPService/Lookup"
+	GeoIPService_BatchLookup_FullMethodName = "/proto.GeoIPService/BatchLookup"
+	GeoIPService_Metadata_FullMethodName    = "/proto.GeoIPService/Metadata"
+)
+
+// GeoIPServiceClient is the client API for GeoIPService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GeoIPServiceClient interface {
+	Lookup(ctx context.Context, in *GeoIPLookupRequest, opts ...grpc.CallOption) (*GeoIPResult, error)
+	BatchLookup(ctx context.Context, in *GeoIPBatchRequest, opts ...grpc.CallOption) (*GeoIPBatchResponse, error)
+	Metadata(ctx context.Context, in *GeoIPMetadataRequest, opts ...grpc.CallOption) (*GeoIPMetadata, error)
+}
+
+type geoIPServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeoIPServiceClient(cc grpc.ClientConnInterface) GeoIPServiceClient {
+	return &geoIPServiceClient{cc}
+}
+
+func (c *geoIPServiceClient) Lookup(ctx context.Context, in *GeoIPLookupRequest, opts ...grpc.CallOption) (*GeoIPResult, error) {
+	out := new(GeoIPResult)
+	err := c.cc.Invoke(ctx, GeoIPService_Lookup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoIPServiceClient) BatchLookup(ctx context.Context, in *GeoIPBatchRequest, opts ...grpc.CallOption) (*GeoIPBatchResponse, error) {
+	out := new(GeoIPBatchResponse)
+	err := c.cc.Invoke(ctx, GeoIPService_BatchLookup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoIPServiceClient) Metadata(ctx context.Context, in *GeoIPMetadataRequest, opts ...grpc.CallOption) (*GeoIPMetadata, error) {
+	out := new(GeoIPMetadata)
+	err := c.cc.Invoke(ctx, GeoIPService_Metadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeoIPServiceServer is the server API for GeoIPService service.
+// All implementations should embed UnimplementedGeoIPServiceServer
+// for forward compatibility
+type GeoIPServiceServer interface {
+	Lookup(context.Context, *GeoIPLookupRequest) (*GeoIPResult, error)
+	BatchLookup(context.Context, *GeoIPBatchRequest) (*GeoIPBatchResponse, error)
+	Metadata(context.Context, *GeoIPMetadataRequest) (*GeoIPMetadata, error)
+}
+
+// UnimplementedGeoIPServiceServer should be embedded to have forward compatible implementations.
+type UnimplementedGeoIPServiceServer struct {
+}
+
+func (UnimplementedGeoIPServiceServer) Lookup(context.Context, *GeoIPLookupRequest) (*GeoIPResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
+}
+func (UnimplementedGeoIPServiceServer) BatchLookup(context.Context, *GeoIPBatchRequest) (*GeoIPBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchLookup not implemented")
+}
+func (UnimplementedGeoIPServiceServer) Metadata(context.Context, *GeoIPMetadataRequest) (*GeoIPMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Metadata not implemented")
+}
+
+// UnsafeGeoIPServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeoIPServiceServer will
+// result in compilation errors.
+type UnsafeGeoIPServiceServer interface {
+	mustEmbedUnimplementedGeoIPServiceServer()
+}
+
+func RegisterGeoIPServiceServer(s grpc.ServiceRegistrar, srv GeoIPServiceServer) {
+	s.RegisterService(&GeoIPService_ServiceDesc, srv)
+}
+
+func _GeoIPService_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoIPLookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoIPServiceServer).Lookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeoIPService_Lookup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoIPServiceServer).Lookup(ctx, req.(*GeoIPLookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeoIPService_BatchLookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoIPBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoIPServiceServer).BatchLookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeoIPService_BatchLookup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoIPServiceServer).BatchLookup(ctx, req.(*GeoIPBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeoIPService_Metadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeoIPMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoIPServiceServer).Metadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeoIPService_Metadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoIPServiceServer).Metadata(ctx, req.(*GeoIPMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GeoIPService_ServiceDesc is the grpc.ServiceDesc for GeoIPService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GeoIPService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.GeoIPService",
+	HandlerType: (*GeoIPServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Lookup",
+			Handler:    _GeoIPService_Lookup_Handler,
+		},
+		{
+			MethodName: "BatchLookup",
+			Handler:    _GeoIPService_BatchLookup_Handler,
+		},
+		{
+			MethodName: "Metadata",
+			Handler:    _GeoIPService_Metadata_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/geoip.proto",
+}
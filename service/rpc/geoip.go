@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	geoipx "github.com/nezhahq/nezha/pkg/geoip"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+var _ pb.GeoIPServiceServer = (*GeoIPHandler)(nil)
+
+const (
+	geoipMaxBatch     = 1000 // BatchLookup 一次最多查询的 IP 数
+	geoipBatchWorkers = 16   // BatchLookup 的最大并发查询数
+)
+
+// GeoIPHandler 通过 Agent 已有的 gRPC 连接提供面板的 IP 定位查询，认证方式与 NezhaService 相同
+type GeoIPHandler struct {
+	Auth *authHandler
+}
+
+func NewGeoIPHandler(auth *authHandler) *GeoIPHandler {
+	return &GeoIPHandler{Auth: auth}
+}
+
+func (s *GeoIPHandler) Lookup(ctx context.Context, req *pb.GeoIPLookupRequest) (*pb.GeoIPResult, error) {
+	if _, err := s.Auth.Check(ctx); err != nil {
+		return nil, err
+	}
+	res, err := geoipx.LookupDetailStringContext(ctx, req.GetIp())
+	switch {
+	case err == nil:
+		return geoipResultToPB(req.GetIp(), res), nil
+	case errors.Is(err, geoipx.ErrInvalidIP):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, geoipx.ErrNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	default:
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// BatchLookup 按请求的顺序返回每个 IP 的结果，单个 IP 查询失败时写入结果的 error 字段
+func (s *GeoIPHandler) BatchLookup(ctx context.Context, req *pb.GeoIPBatchRequest) (*pb.GeoIPBatchResponse, error) {
+	if _, err := s.Auth.Check(ctx); err != nil {
+		return nil, err
+	}
+	ips := req.GetIps()
+	if len(ips) > geoipMaxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "too many ips: %d, at most %d", len(ips), geoipMaxBatch)
+	}
+
+	results := make([]*pb.GeoIPResult, len(ips))
+	sem := make(chan struct{}, geoipBatchWorkers)
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			res, err := geoipx.LookupDetailStringContext(ctx, ip)
+			if err != nil {
+				results[i] = &pb.GeoIPResult{Ip: ip, Error: err.Error()}
+				return
+			}
+			results[i] = geoipResultToPB(ip, res)
+		})
+	}
+	wg.Wait()
+	return &pb.GeoIPBatchResponse{Results: results}, nil
+}
+
+func (s *GeoIPHandler) Metadata(ctx context.Context, req *pb.GeoIPMetadataRequest) (*pb.GeoIPMetadata, error) {
+	if _, err := s.Auth.Check(ctx); err != nil {
+		return nil, err
+	}
+	md, err := geoipx.Metadata()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	out := &pb.GeoIPMetadata{
+		Source:       md.Source,
+		Path:         md.Path,
+		DatabaseType: md.DatabaseType,
+		Stale:        md.Stale,
+	}
+	if !md.BuildTime.IsZero() {
+		out.BuildTime = uint64(md.BuildTime.Unix())
+	}
+	for _, p := range geoipx.Providers() {
+		out.Chain = append(out.Chain, p.Name())
+	}
+	return out, nil
+}
+
+func geoipResultToPB(ip string, res *geoipx.Result) *pb.GeoIPResult {
+	out := &pb.GeoIPResult{
+		Ip:            ip,
+		CountryCode:   res.CountryCode,
+		CountryName:   res.CountryName,
+		ContinentCode: res.ContinentCode,
+		ContinentName: res.ContinentName,
+		IsEu:          res.IsEU,
+		Timezone:      res.Timezone,
+		Network:       res.Network,
+		Source:        res.Source,
+		Private:       res.Private,
+	}
+	if res.City != nil {
+		out.City = res.City.Name
+		out.Subdivision = res.City.Subdivision
+	}
+	if res.Location != nil {
+		out.Latitude = res.Location.Latitude
+		out.Longitude = res.Location.Longitude
+	}
+	if res.ASN != nil {
+		out.Asn = uint64(res.ASN.Number)
+		out.AsOrganization = res.ASN.Organization
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func newTestGeoIPHandler(t *testing.T) (*GeoIPHandler, context.Context) {
+	t.Helper()
+	initSingleton(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"client_secret", "secret", "client_uuid", "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"))
+	return NewGeoIPHandler(NewNezhaHandler().Auth), ctx
+}
+
+func TestGeoIPLookup(t *testing.T) {
+	h, ctx := newTestGeoIPHandler(t)
+
+	res, err := h.Lookup(ctx, &pb.GeoIPLookupRequest{Ip: "3.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetCountryCode() != "us" || res.GetContinentCode() != "na" || res.GetNetwork() != "3.0.0.0/24" || res.GetPrivate() {
+		t.Fatalf("3.0.0.1 = %+v", res)
+	}
+	// 覆盖表优先于数据库
+	if res, err := h.Lookup(ctx, &pb.GeoIPLookupRequest{Ip: "1.0.0.1"}); err != nil || res.GetCountryCode() != "jp" {
+		t.Fatalf("1.0.0.1 = %+v, %v", res, err)
+	}
+	if res, err := h.Lookup(ctx, &pb.GeoIPLookupRequest{Ip: "192.168.1.1"}); err != nil || !res.GetPrivate() || res.GetCountryCode() != "" {
+		t.Fatalf("192.168.1.1 = %+v, %v", res, err)
+	}
+
+	for ip, code := range map[string]codes.Code{"not-an-ip": codes.InvalidArgument, "8.8.8.8": codes.NotFound} {
+		if _, err := h.Lookup(ctx, &pb.GeoIPLookupRequest{Ip: ip}); status.Code(err) != code {
+			t.Fatalf("%s: err = %v, want %s", ip, err, code)
+		}
+	}
+
+	if _, err := h.Lookup(context.Background(), &pb.GeoIPLookupRequest{Ip: "3.0.0.1"}); err == nil {
+		t.Fatal("expected error without client secret")
+	}
+}
+
+func TestGeoIPBatchLookup(t *testing.T) {
+	h, ctx := newTestGeoIPHandler(t)
+
+	resp, err := h.BatchLookup(ctx, &pb.GeoIPBatchRequest{Ips: []string{"3.0.0.1", "not-an-ip", "2.0.0.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := resp.GetResults()
+	// 按请求的顺序返回，单个 IP 失败时写入 error 字段
+	if len(results) != 3 || results[0].GetCountryCode() != "us" || results[1].GetIp() != "not-an-ip" ||
+		!strings.Contains(results[1].GetError(), "invalid") || results[2].GetCountryCode() != "de" {
+		t.Fatalf("results = %+v", results)
+	}
+
+	if _, err := h.BatchLookup(ctx, &pb.GeoIPBatchRequest{Ips: make([]string, geoipMaxBatch+1)}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("too many ips: err = %v", err)
+	}
+}
+
+func TestGeoIPMetadata(t *testing.T) {
+	h, ctx := newTestGeoIPHandler(t)
+
+	md, err := h.Metadata(ctx, &pb.GeoIPMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(md.GetPath(), "country.mmdb") || md.GetBuildTime() == 0 || len(md.GetChain()) == 0 {
+		t.Fatalf("metadata = %+v", md)
+	}
+}
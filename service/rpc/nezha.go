@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"net"
+	"net/netip"
 	"sync"
 	"time"
 
@@ -45,6 +45,7 @@ func (s *NezhaHandler) RequestTask(stream pb.NezhaService_RequestTaskServer) err
 
 	server, _ := singleton.ServerShared.Get(clientID)
 	server.TaskStream = stream
+	go tagPeerLocation(stream.Context(), clientID, server)
 	var result *pb.TaskResult
 	for {
 		result, err = stream.Recv()
@@ -54,6 +55,9 @@ func (s *NezhaHandler) RequestTask(stream pb.NezhaService_RequestTaskServer) err
 		}
 		switch result.GetType() {
 		case model.TaskTypeCommand:
+			if singleton.DispatchAgentCommandResult(result) {
+				continue
+			}
 			// 处理上报的计划任务
 			cr, _ := singleton.CronShared.Get(result.GetId())
 			if cr != nil {
@@ -230,9 +234,11 @@ func (s *NezhaHandler) ReportGeoIP(c context.Context, r *pb.GeoIP) (*pb.GeoIP, e
 		return nil, fmt.Errorf("server not found")
 	}
 
+	peerIP, oldGeoIP := singleton.ServerShared.GetGeoIP(server)
+
 	// 检查并更新DDNS
 	if server.EnableDDNS && joinedIP != "" &&
-		(server.GeoIP == nil || server.GeoIP.IP != geoip.IP) {
+		(oldGeoIP == nil || oldGeoIP.IP != geoip.IP) {
 		ipv4 := geoip.IP.IPv4Addr
 		ipv6 := geoip.IP.IPv6Addr
 
@@ -242,18 +248,19 @@ func (s *NezhaHandler) ReportGeoIP(c context.Context, r *pb.GeoIP) (*pb.GeoIP, e
 	}
 
 	// 发送IP变动通知
-	if server.GeoIP != nil && singleton.Conf.EnableIPChangeNotification &&
+	if oldGeoIP != nil && singleton.Conf.EnableIPChangeNotification &&
 		((singleton.Conf.Cover == model.ConfigCoverAll && !singleton.Conf.IgnoredIPNotificationServerIDs[clientID]) ||
 			(singleton.Conf.Cover == model.ConfigCoverIgnoreAll && singleton.Conf.IgnoredIPNotificationServerIDs[clientID])) &&
-		server.GeoIP.IP.Join() != "" &&
+		oldGeoIP.IP.Join() != "" &&
+		oldGeoIP.IP.Join() != peerIP && // 连接地址定位的结果与 Agent 上报的地址格式不同，不算变动
 		joinedIP != "" &&
-		server.GeoIP.IP != geoip.IP {
+		oldGeoIP.IP != geoip.IP {
 
 		singleton.NotificationShared.SendNotification(singleton.Conf.IPChangeNotificationGroupID,
 			fmt.Sprintf(
 				"[%s] %s, %s => %s",
 				singleton.Localizer.T("IP Changed"),
-				server.Name, singleton.IPDesensitize(server.GeoIP.IP.Join()),
+				server.Name, singleton.IPDesensitize(oldGeoIP.IP.Join()),
 				singleton.IPDesensitize(joinedIP),
 			),
 			"")
@@ -267,15 +274,75 @@ func (s *NezhaHandler) ReportGeoIP(c context.Context, r *pb.GeoIP) (*pb.GeoIP, e
 		ip = geoip.IP.IPv4Addr
 	}
 
-	netIP := net.ParseIP(ip)
-	location, err := geoipx.Lookup(netIP)
-	if err != nil {
+	var location string
+	if detail, err := geoipx.LookupDetailStringContext(c, ip); err != nil {
 		log.Printf("NEZHA>> geoip.Lookup: %v", err)
+	} else if !detail.Private {
+		location = detail.Code()
+		if oldGeoIP == nil || oldGeoIP.CountryCode != location {
+			log.Printf("NEZHA>> GeoIP of server %d (%s) resolved to %s by %s", clientID, singleton.IPDesensitize(ip), location, detail.Source)
+		}
 	}
-	geoip.CountryCode = location
+	singleton.SetGeoIPCountry(&geoip, location, singleton.Conf.Language)
 
 	// 将地区码写入到 Host
-	server.GeoIP = &geoip
+	var changed bool
+	singleton.ServerShared.SetGeoIP(server, func() {
+		changed = server.GeoIP == nil || server.GeoIP.CountryCode != location
+		server.GeoIP = &geoip
+	})
+	if changed {
+		go singleton.SyncGeoGroups()
+	}
 
 	return &pb.GeoIP{Ip: nil, CountryCode: location, DashboardBootTime: singleton.DashboardBootTime}, nil
 }
+
+// tagPeerLocation 在 Agent 建立连接时根据连接地址定位服务器，连接地址变化后重新定位
+// Agent 通过 ReportGeoIP 上报的地址更准确，已经上报过时不覆盖
+func tagPeerLocation(ctx context.Context, clientID uint64, server *model.Server) {
+	ip, _ := ctx.Value(model.CtxKeyRealIP{}).(string)
+	if ip == "" {
+		ip, _ = ctx.Value(model.CtxKeyConnectingIP{}).(string)
+	}
+	peerIP, oldGeoIP := singleton.ServerShared.GetGeoIP(server)
+	if ip == "" || ip == peerIP {
+		return
+	}
+	if oldGeoIP != nil && oldGeoIP.IP.Join() != "" && oldGeoIP.IP.Join() != peerIP {
+		return
+	}
+
+	// 连接断开不影响定位结果
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	detail, err := geoipx.LookupDetailStringContext(ctx, ip)
+	if err != nil || detail.Private {
+		return
+	}
+	geoip := &model.GeoIP{}
+	singleton.SetGeoIPCountry(geoip, detail.Code(), singleton.Conf.Language)
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Is6() {
+		geoip.IP.IPv6Addr = ip
+	} else {
+		geoip.IP.IPv4Addr = ip
+	}
+	var updated, changed bool
+	singleton.ServerShared.SetGeoIP(server, func() {
+		// 查询期间 Agent 已经通过 ReportGeoIP 上报过时不覆盖
+		if server.GeoIP != oldGeoIP {
+			return
+		}
+		updated = true
+		changed = server.GeoIP == nil || server.GeoIP.CountryCode != geoip.CountryCode
+		server.PeerIP = ip
+		server.GeoIP = geoip
+	})
+	if !updated {
+		return
+	}
+	if changed {
+		go singleton.SyncGeoGroups()
+	}
+	log.Printf("NEZHA>> GeoIP of server %d (%s) resolved to %s by %s from its connecting address", clientID, singleton.IPDesensitize(ip), geoip.CountryCode, detail.Source)
+}
@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/nezhahq/nezha/model"
+	geoipx "github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+	pb "github.com/nezhahq/nezha/proto"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+var initSingletonOnce sync.Once
+
+// initSingleton 只初始化一次，上次运行留下的 SyncGeoGroups 等 goroutine 还会读取这些全局变量
+func initSingleton(t *testing.T) {
+	initSingletonOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "nezha-rpc-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 默认实例只使用测试库：3.0.0.0/24 → us，不读取本机的 ASN 库
+		dbPath := filepath.Join(dir, "country.mmdb")
+		entries := []builder.Entry{{Network: netip.MustParsePrefix("3.0.0.0/24"), Country: "us"}}
+		if err := builder.BuildFile(dbPath, builder.Options{Description: "test"}, entries, nil); err != nil {
+			t.Fatal(err)
+		}
+		os.Setenv("GEOIP_DB_PATH", dbPath)
+		os.Setenv("GEOIP_ASN_DB_PATH", filepath.Join(dir, "missing-asn.mmdb"))
+		os.Setenv("GEOIP_WATCH", "0")
+		os.Setenv("GEOIP_OFFLINE", "true")
+		singleton.Conf = &singleton.ConfigClass{Config: &model.Config{}}
+		singleton.Conf.Language = "en_US"
+		if err := singleton.InitDBFromPath(filepath.Join(dir, "sqlite.db")); err != nil {
+			t.Fatal(err)
+		}
+		singleton.ServerShared = singleton.NewServerClass()
+		singleton.AgentSecretToUserId = map[string]uint64{"secret": 0}
+		// 覆盖表的结果不需要查询数据库或在线数据源
+		if err := geoipx.SetOverrides(map[string]string{"1.0.0.0/24": "jp", "2.0.0.0/24": "de"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// 用 go test -race 运行时检查连接时的定位与 ReportGeoIP 同时修改同一台服务器
+func TestGeoIPConcurrentUpdate(t *testing.T) {
+	initSingleton(t)
+
+	handler := NewNezhaHandler()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"client_secret", "secret", "client_uuid", "ffffffff-ffff-ffff-ffff-ffffffffffff"))
+	clientID, err := handler.Auth.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := singleton.ServerShared.Get(clientID)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			connCtx := context.WithValue(ctx, model.CtxKeyConnectingIP{}, fmt.Sprintf("1.0.0.%d", i+1))
+			tagPeerLocation(connCtx, clientID, server)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := handler.ReportGeoIP(ctx, &pb.GeoIP{Ip: &pb.IP{Ipv4: "2.0.0.1"}}); err != nil {
+				t.Error(err)
+			}
+		}()
+		// 遍历服务器列表的读取方
+		for _, s := range singleton.ServerShared.Range {
+			_ = s.GeoIP.CountryCode
+		}
+	}
+	wg.Wait()
+
+	// 上报过地址后，连接时的定位不再覆盖
+	tagPeerLocation(context.WithValue(ctx, model.CtxKeyConnectingIP{}, "1.0.0.100"), clientID, server)
+	if _, geoip := singleton.ServerShared.GetGeoIP(server); geoip == nil || geoip.CountryCode != "de" {
+		t.Fatalf("GeoIP = %+v, expected de", geoip)
+	}
+}
@@ -0,0 +1,86 @@
+package singleton
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+// 访问记录队列长度，写满后丢弃新的记录，避免登录洪泛时堆积 goroutine
+const accessLogQueueSize = 1024
+
+var (
+	accessLogMu    sync.Mutex
+	accessLogQueue chan *model.AccessLog // 为 nil 时 worker 还没有启动或已经停止
+	accessLogDone  chan struct{}         // worker 写完队列中的记录后关闭
+)
+
+// RecordAccess 将访问记录放入队列，由单个 worker 补全国家与 ASN 后写入数据库，不阻塞请求
+// 队列已满时直接丢弃该记录
+func RecordAccess(entry *model.AccessLog) {
+	accessLogMu.Lock()
+	defer accessLogMu.Unlock()
+	if accessLogQueue == nil {
+		accessLogQueue = make(chan *model.AccessLog, accessLogQueueSize)
+		accessLogDone = make(chan struct{})
+		go accessLogWorker(geoip.Default(), accessLogQueue, accessLogDone)
+	}
+	select {
+	case accessLogQueue <- entry:
+	default:
+		log.Printf("NEZHA>> Access log queue is full, dropping record from %s", entry.IP)
+	}
+}
+
+// CloseAccessLog 停止 worker，等待队列中的记录写入数据库，ctx 结束时不再等待
+// 之后再调用 RecordAccess 会启动新的 worker
+func CloseAccessLog(ctx context.Context) error {
+	accessLogMu.Lock()
+	queue, done := accessLogQueue, accessLogDone
+	accessLogQueue, accessLogDone = nil, nil
+	accessLogMu.Unlock()
+	if queue == nil {
+		return nil
+	}
+
+	close(queue)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func accessLogWorker(r *geoip.Resolver, queue <-chan *model.AccessLog, done chan<- struct{}) {
+	defer close(done)
+	for entry := range queue {
+		enrichAccessLog(r, entry)
+		if err := DB.Create(entry).Error; err != nil {
+			log.Printf("NEZHA>> Failed to record access log: %v", err)
+		}
+	}
+}
+
+// enrichAccessLog 补全国家与 ASN，ASN 只查询本地数据库，不会为访问记录发起网络请求
+func enrichAccessLog(r *geoip.Resolver, entry *model.AccessLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if detail, err := r.LookupDetailStringContext(ctx, entry.IP); err == nil && !detail.Private {
+		entry.Country = strings.ToLower(detail.CountryCode)
+		if detail.ASN != nil {
+			entry.ASN, entry.ASOrg = detail.ASN.Number, detail.ASN.Organization
+		}
+	}
+	if entry.ASN == 0 && entry.Country != "" {
+		if asn, err := r.LookupASNLocal(net.ParseIP(entry.IP)); err == nil && asn != nil {
+			entry.ASN, entry.ASOrg = asn.Number, asn.Organization
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package singleton
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/geoip/builder"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newAccessLogResolver 创建只有本地国家库与 ASN 库的 Resolver，在线数据源的请求都会失败并计入 requests
+func newAccessLogResolver(t *testing.T, requests *atomic.Int32) *geoip.Resolver {
+	t.Helper()
+	dir := t.TempDir()
+	countryDB, asnDB := filepath.Join(dir, "country.mmdb"), filepath.Join(dir, "asn.mmdb")
+	entries := []builder.Entry{
+		{Network: netip.MustParsePrefix("1.0.0.0/24"), Country: "jp"},
+		{Network: netip.MustParsePrefix("2.0.0.0/24"), Country: "de"},
+	}
+	if err := builder.BuildFile(countryDB, builder.Options{Description: "test"}, entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	w := builder.NewWriter("GeoLite2-ASN")
+	w.Description = "test"
+	if err := w.Insert(netip.MustParsePrefix("1.0.0.0/24"), builder.Record{
+		"autonomous_system_number": uint32(13335), "autonomous_system_organization": "Cloudflare",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(asnDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteTo(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := geoip.New(
+		geoip.WithDBPaths(countryDB),
+		geoip.WithASNDBPaths(asnDB),
+		geoip.WithOverridePaths(),
+		geoip.WithChain(geoip.ProviderMMDB),
+		geoip.WithWatch(false),
+		geoip.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return nil, http.ErrNotSupported
+		})}),
+	)
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestEnrichAccessLog(t *testing.T) {
+	var requests atomic.Int32
+	r := newAccessLogResolver(t, &requests)
+
+	cases := []struct {
+		ip      string
+		country string
+		asn     uint
+		org     string
+	}{
+		{"1.0.0.1", "jp", 13335, "Cloudflare"},
+		// ASN 库中没有的地址不请求在线数据源
+		{"2.0.0.1", "de", 0, ""},
+		{"192.168.1.1", "", 0, ""},
+	}
+	for _, c := range cases {
+		entry := &model.AccessLog{IP: c.ip}
+		enrichAccessLog(r, entry)
+		if entry.Country != c.country || entry.ASN != c.asn || entry.ASOrg != c.org {
+			t.Fatalf("%s: got %+v", c.ip, entry)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("%d requests to online providers", n)
+	}
+}
+
+func TestAccessLogQueue(t *testing.T) {
+	setTestDB(t, &model.Config{})
+	var requests atomic.Int32
+	r := newAccessLogResolver(t, &requests)
+
+	// worker 还没有开始读取时，队列满后丢弃新的记录
+	queue, done := make(chan *model.AccessLog, 1), make(chan struct{})
+	accessLogMu.Lock()
+	accessLogQueue, accessLogDone = queue, done
+	accessLogMu.Unlock()
+	RecordAccess(&model.AccessLog{Event: "kept", IP: "1.0.0.1"})
+	RecordAccess(&model.AccessLog{Event: "dropped", IP: "1.0.0.2"})
+	if len(queue) != 1 {
+		t.Fatalf("queue length = %d, want 1", len(queue))
+	}
+
+	// 停止时写完队列中的记录后 worker 退出
+	go accessLogWorker(r, queue, done)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := CloseAccessLog(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	default:
+		t.Fatal("worker is still running")
+	}
+	if err := CloseAccessLog(ctx); err != nil {
+		t.Fatalf("closing twice: %v", err)
+	}
+
+	var logs []model.AccessLog
+	if err := DB.Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Event != "kept" || logs[0].Country != "jp" || logs[0].ASN != 13335 {
+		t.Fatalf("access logs = %+v", logs)
+	}
+}
@@ -0,0 +1,69 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// 按需执行的命令使用计划任务 ID 不会用到的范围，Agent 回传结果时原样带回
+const agentCommandIDBase = 1 << 62
+
+var (
+	agentCommandSeq     atomic.Uint64
+	agentCommandsLock   sync.Mutex
+	agentCommandResults = make(map[uint64]chan *pb.TaskResult)
+)
+
+// RunAgentCommand 通过 TaskTypeCommand 让 Agent 执行 command 并等待输出，Agent 关闭了命令执行时返回错误
+func RunAgentCommand(ctx context.Context, server *model.Server, command string) (string, error) {
+	if server.TaskStream == nil {
+		return "", errors.New("server is offline")
+	}
+
+	id := agentCommandIDBase + agentCommandSeq.Add(1)
+	ch := make(chan *pb.TaskResult, 1)
+	agentCommandsLock.Lock()
+	agentCommandResults[id] = ch
+	agentCommandsLock.Unlock()
+	defer func() {
+		agentCommandsLock.Lock()
+		delete(agentCommandResults, id)
+		agentCommandsLock.Unlock()
+	}()
+
+	if err := server.TaskStream.Send(&pb.Task{Id: id, Type: model.TaskTypeCommand, Data: command}); err != nil {
+		return "", err
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", Localizer.ErrorT("operation timeout")
+	case result := <-ch:
+		if !result.GetSuccessful() {
+			return "", errors.New(result.GetData())
+		}
+		return result.GetData(), nil
+	}
+}
+
+// DispatchAgentCommandResult 把 RunAgentCommand 的结果交给等待的调用方，不是按需执行的命令时返回 false
+func DispatchAgentCommandResult(result *pb.TaskResult) bool {
+	if result.GetId() < agentCommandIDBase {
+		return false
+	}
+	agentCommandsLock.Lock()
+	ch, ok := agentCommandResults[result.GetId()]
+	agentCommandsLock.Unlock()
+	if ok {
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package singleton
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+var (
+	agentRolloutMu     sync.Mutex
+	agentRollout       *model.AgentRollout
+	agentRolloutCancel context.CancelFunc
+)
+
+// StartAgentRollout 按 form.ServerGroups 的顺序分批让 Agent 升级，同时只能有一次分批升级
+// 同一台服务器在多个分组中时只在第一个分组升级
+func StartAgentRollout(form *model.AgentRolloutForm) (*model.AgentRollout, error) {
+	agentRolloutMu.Lock()
+	defer agentRolloutMu.Unlock()
+	task := &model.AgentUpgradeTask{Version: form.Version, URL: form.URL, Checksums: form.Checksums}
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+
+	if agentRollout != nil && (agentRollout.Status == model.AgentRolloutPending || agentRollout.Status == model.AgentRolloutRunning) {
+		return nil, errors.New("another agent rollout is in progress")
+	}
+
+	var members []model.ServerGroupServer
+	if err := DB.Where("server_group_id IN (?)", form.ServerGroups).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint64]bool)
+	r := &model.AgentRollout{Version: form.Version, Status: model.AgentRolloutPending, StartedAt: time.Now()}
+	for _, gid := range form.ServerGroups {
+		stage := &model.AgentRolloutStage{ServerGroupID: gid, Status: model.AgentRolloutPending, Servers: []uint64{}}
+		for _, m := range members {
+			if m.ServerGroupId == gid && !seen[m.ServerId] {
+				seen[m.ServerId] = true
+				stage.Servers = append(stage.Servers, m.ServerId)
+			}
+		}
+		r.Stages = append(r.Stages, stage)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	agentRollout, agentRolloutCancel = r, cancel
+	go runAgentRollout(ctx, r, string(data), time.Duration(form.StageTimeout)*time.Second, time.Duration(form.StageDelay)*time.Second)
+	return cloneAgentRollout(r), nil
+}
+
+// GetAgentRollout 返回最近一次分批升级的进度，没有时返回 nil
+func GetAgentRollout() *model.AgentRollout {
+	agentRolloutMu.Lock()
+	defer agentRolloutMu.Unlock()
+	if agentRollout == nil {
+		return nil
+	}
+	return cloneAgentRollout(agentRollout)
+}
+
+// CancelAgentRollout 停止正在进行的分批升级，已经发出的升级指令不会撤回
+func CancelAgentRollout() error {
+	agentRolloutMu.Lock()
+	defer agentRolloutMu.Unlock()
+	if agentRollout == nil || !agentRollout.FinishedAt.IsZero() {
+		return errors.New("no agent rollout is in progress")
+	}
+	agentRolloutCancel()
+	return nil
+}
+
+func cloneAgentRollout(r *model.AgentRollout) *model.AgentRollout {
+	c := *r
+	c.Stages = make([]*model.AgentRolloutStage, len(r.Stages))
+	for i, stage := range r.Stages {
+		s := *stage
+		s.Servers = slices.Clone(stage.Servers)
+		s.Upgraded = slices.Clone(stage.Upgraded)
+		s.Offline = slices.Clone(stage.Offline)
+		c.Stages[i] = &s
+	}
+	return &c
+}
+
+func runAgentRollout(ctx context.Context, r *model.AgentRollout, data string, timeout, delay time.Duration) {
+	finish := func(status, errMsg string) {
+		agentRolloutMu.Lock()
+		r.Status, r.Error, r.FinishedAt = status, errMsg, time.Now()
+		agentRolloutMu.Unlock()
+		if errMsg != "" {
+			log.Printf("NEZHA>> Agent rollout to %s stopped: %s", r.Version, errMsg)
+		}
+	}
+
+	agentRolloutMu.Lock()
+	r.Status = model.AgentRolloutRunning
+	agentRolloutMu.Unlock()
+
+	for i, stage := range r.Stages {
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+				finish(model.AgentRolloutCanceled, "")
+				return
+			case <-time.After(delay):
+			}
+		}
+
+		waiting := make(map[uint64]bool)
+		var upgrade []*model.Server
+		agentRolloutMu.Lock()
+		stage.Status = model.AgentRolloutRunning
+		for _, id := range stage.Servers {
+			server, _ := ServerShared.Get(id)
+			switch {
+			case server == nil || server.TaskStream == nil:
+				stage.Offline = append(stage.Offline, id)
+			case server.Host != nil && model.AgentVersionMatch(server.Host.Version, r.Version):
+				stage.Upgraded = append(stage.Upgraded, id)
+			default:
+				upgrade = append(upgrade, server)
+				waiting[id] = true
+			}
+		}
+		agentRolloutMu.Unlock()
+
+		// 发送可能被慢的连接阻塞，不持有锁
+		for _, server := range upgrade {
+			// 发送失败时服务器正在断线，重连后还是旧版本会在超时后记为失败
+			if stream := server.TaskStream; stream != nil {
+				stream.Send(&pb.Task{Type: model.TaskTypeUpgrade, Data: data})
+			}
+		}
+
+		deadline := time.After(timeout)
+		ticker := time.NewTicker(10 * time.Second)
+		for len(waiting) > 0 {
+			select {
+			case <-ctx.Done():
+				ticker.Stop()
+				finish(model.AgentRolloutCanceled, "")
+				return
+			case <-deadline:
+				ticker.Stop()
+				agentRolloutMu.Lock()
+				stage.Status = model.AgentRolloutFailed
+				agentRolloutMu.Unlock()
+				finish(model.AgentRolloutFailed, fmt.Sprintf("%d server(s) in server group %d were not upgraded to %s in time", len(waiting), stage.ServerGroupID, r.Version))
+				return
+			case <-ticker.C:
+				agentRolloutMu.Lock()
+				for id := range waiting {
+					server, _ := ServerShared.Get(id)
+					if server != nil && server.TaskStream != nil && server.Host != nil && model.AgentVersionMatch(server.Host.Version, r.Version) {
+						stage.Upgraded = append(stage.Upgraded, id)
+						delete(waiting, id)
+					}
+				}
+				agentRolloutMu.Unlock()
+			}
+		}
+		ticker.Stop()
+
+		agentRolloutMu.Lock()
+		stage.Status = model.AgentRolloutDone
+		agentRolloutMu.Unlock()
+	}
+	finish(model.AgentRolloutDone, "")
+}
@@ -3,6 +3,7 @@ package singleton
 import (
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -167,6 +168,9 @@ func checkStatus() {
 					alertsPrevState[alert.ID][server.ID] = _RuleCheckFail
 					message := fmt.Sprintf("[%s] %s(%s) %s", Localizer.T("Incident"),
 						server.Name, IPDesensitize(server.GeoIP.IP.Join()), alert.Name)
+					if from, to, ok := alert.CountryChange(server.ID); ok {
+						message += fmt.Sprintf(", %s => %s", strings.ToUpper(from), strings.ToUpper(to))
+					}
 					go CronShared.SendTriggerTasks(alert.FailTriggerTasks, curServer.ID)
 					go NotificationShared.SendNotification(alert.NotificationGroupID, message, NotificationMuteLabel.ServerIncident(server.ID, alert.ID), &curServer)
 					// 清除恢复通知的静音缓存
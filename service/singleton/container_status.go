@@ -0,0 +1,59 @@
+package singleton
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+// UpdateContainerStatus 让 Agent 查询 server.WatchedContainers 的状态并更新 server.ContainerStatus
+func UpdateContainerStatus(ctx context.Context, server *model.Server) ([]*model.ContainerStatus, error) {
+	names := ServerShared.GetWatchedContainers(server)
+	if len(names) == 0 {
+		ServerShared.SetPolledStatus(server, func() {
+			server.ContainerStatus = nil
+		})
+		return []*model.ContainerStatus{}, nil
+	}
+
+	output, err := RunAgentCommand(ctx, server, model.ContainerStatusCommand)
+	if err != nil {
+		return nil, err
+	}
+	status, err := model.ParseContainerStatus(names, output)
+	if err != nil {
+		return nil, err
+	}
+	ServerShared.SetPolledStatus(server, func() {
+		// 查询期间修改了 WatchedContainers 时丢弃旧列表的结果
+		if slices.Equal(server.WatchedContainers, names) {
+			server.ContainerStatus = status
+		}
+	})
+	return status, nil
+}
+
+// PollContainerStatus 查询所有在线且配置了 WatchedContainers 的服务器，每分钟执行
+func PollContainerStatus() {
+	var wg sync.WaitGroup
+	for server := range utils.Seq2To1(ServerShared.Range) {
+		if server.TaskStream == nil || len(server.WatchedContainers) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if _, err := UpdateContainerStatus(ctx, server); err != nil {
+				log.Printf("NEZHA>> Failed to read container status of server %d: %v", server.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,45 @@
+package singleton
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func TestPollContainerStatus(t *testing.T) {
+	var commands atomic.Int32
+	stream := &fakeTaskStream{reply: func(command string) (string, bool) {
+		commands.Add(1)
+		if command != model.ContainerStatusCommand {
+			t.Errorf("command = %q", command)
+		}
+		return `{"ID":"a1","Image":"nginx","Names":"web","State":"running","Status":"Up 2 hours"}
+{"ID":"b2","Image":"redis","Names":"cache","State":"exited","Status":"Exited (0) 1 minute ago"}
+`, true
+	}}
+	watched := &model.Server{Common: model.Common{ID: 1}, TaskStream: stream, WatchedContainers: []string{"web", "cache"}}
+	unwatched := &model.Server{Common: model.Common{ID: 2}, TaskStream: stream}
+	offline := &model.Server{Common: model.Common{ID: 3}, WatchedContainers: []string{"web"}}
+	setTestServers(t, watched, unwatched, offline)
+
+	PollContainerStatus()
+
+	// 只查询在线且配置了容器的服务器
+	if n := commands.Load(); n != 1 {
+		t.Fatalf("%d commands sent, want 1", n)
+	}
+	if s := watched.ContainerStatus; len(s) != 2 || !s[0].Running || s[1].Running || s[1].State != "exited" {
+		t.Fatalf("status = %+v", s)
+	}
+	if unwatched.ContainerStatus != nil || offline.ContainerStatus != nil {
+		t.Fatalf("unexpected status %+v, %+v", unwatched.ContainerStatus, offline.ContainerStatus)
+	}
+
+	// 报警规则使用最近一次的结果
+	rule := &model.Rule{Type: "container_exited"}
+	watched.State = &model.HostState{}
+	if rule.Snapshot(nil, watched, nil) {
+		t.Fatal("container_exited passed with an exited container")
+	}
+}
@@ -0,0 +1,67 @@
+package singleton
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+// UpdateDiskHealth 让 Agent 读取 SMART 信息并更新 server.Disks
+func UpdateDiskHealth(ctx context.Context, server *model.Server) ([]*model.DiskHealth, error) {
+	output, err := RunAgentCommand(ctx, server, model.DiskHealthCommand)
+	if err != nil {
+		return nil, err
+	}
+	disks, err := model.ParseDiskHealth(output)
+	if err != nil {
+		return nil, err
+	}
+	ServerShared.SetPolledStatus(server, func() {
+		server.Disks = disks
+	})
+	return disks, nil
+}
+
+// PollDiskHealth 在配置了硬盘报警规则时读取所有在线服务器的 SMART 信息
+func PollDiskHealth() {
+	if !hasDiskRule() {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for server := range utils.Seq2To1(ServerShared.Range) {
+		if server.TaskStream == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if _, err := UpdateDiskHealth(ctx, server); err != nil {
+				log.Printf("NEZHA>> Failed to read disk health of server %d: %v", server.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func hasDiskRule() bool {
+	AlertsLock.RLock()
+	defer AlertsLock.RUnlock()
+	for _, alert := range Alerts {
+		if !alert.Enabled() {
+			continue
+		}
+		for _, rule := range alert.Rules {
+			if rule.IsDiskRule() {
+				return true
+			}
+		}
+	}
+	return false
+}
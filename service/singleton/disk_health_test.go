@@ -0,0 +1,43 @@
+package singleton
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+func TestUpdateDiskHealthConcurrent(t *testing.T) {
+	server := &model.Server{Common: model.Common{ID: 1}}
+	server.TaskStream = &fakeTaskStream{reply: func(string) (string, bool) {
+		return `{"device":{"name":"/dev/sda"},"smart_status":{"passed":true}}` + "\n---\n", true
+	}}
+	setTestServers(t, server)
+
+	// 手动读取与报警检查同时进行，用 -race 运行时不应报告数据竞争
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := UpdateDiskHealth(context.Background(), server); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for s := range utils.Seq2To1(ServerShared.Range) {
+				for _, d := range s.Disks {
+					_ = d.Passed
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(server.Disks) != 1 || server.Disks[0].Device != "/dev/sda" || !server.Disks[0].Passed {
+		t.Fatalf("disks = %+v", server.Disks)
+	}
+}
@@ -0,0 +1,47 @@
+package singleton
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// fakeTaskStream 模拟 Agent，收到命令后用 reply 的返回值作为命令输出回传
+type fakeTaskStream struct {
+	pb.NezhaService_RequestTaskServer
+	reply func(command string) (output string, ok bool)
+}
+
+func (s *fakeTaskStream) Send(task *pb.Task) error {
+	output, ok := s.reply(task.GetData())
+	DispatchAgentCommandResult(&pb.TaskResult{Id: task.GetId(), Type: task.GetType(), Data: output, Successful: ok})
+	return nil
+}
+
+// setTestServers 用 servers 替换 ServerShared，测试结束后恢复
+func setTestServers(t *testing.T, servers ...*model.Server) {
+	t.Helper()
+	sc := &ServerClass{
+		class:    class[uint64, *model.Server]{list: make(map[uint64]*model.Server)},
+		uuidToID: make(map[string]uint64),
+	}
+	for _, s := range servers {
+		sc.list[s.ID] = s
+	}
+	old := ServerShared
+	ServerShared = sc
+	t.Cleanup(func() { ServerShared = old })
+}
+
+// setTestDB 使用临时目录中的 SQLite 数据库与 cfg，测试结束后恢复 DB 与 Conf
+func setTestDB(t *testing.T, cfg *model.Config) {
+	t.Helper()
+	oldConf, oldDB := Conf, DB
+	t.Cleanup(func() { Conf, DB = oldConf, oldDB })
+	Conf = &ConfigClass{Config: cfg}
+	if err := InitDBFromPath(filepath.Join(t.TempDir(), "sqlite.db")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,97 @@
+package singleton
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+type geoRollupKey struct {
+	day       time.Time
+	country   string
+	serviceID uint64
+}
+
+var (
+	geoRollupMu      sync.Mutex
+	geoRollupPending = make(map[geoRollupKey]*model.GeoRollup)
+)
+
+// geoRollupCountry 返回服务器的国家码，只查到洲时返回洲代码，没有定位结果时返回空字符串
+func geoRollupCountry(server *model.Server) string {
+	country, continent := server.GeoIP.Region()
+	return utils.IfOr(country != "", country, continent)
+}
+
+func addGeoRollup(now time.Time, country string, serviceID uint64, up bool, delay float32) {
+	y, m, d := now.Date()
+	key := geoRollupKey{day: time.Date(y, m, d, 0, 0, 0, 0, now.Location()), country: country, serviceID: serviceID}
+
+	geoRollupMu.Lock()
+	defer geoRollupMu.Unlock()
+	r, ok := geoRollupPending[key]
+	if !ok {
+		r = &model.GeoRollup{Day: key.day, Country: country, ServiceID: serviceID}
+		geoRollupPending[key] = r
+	}
+	if !up {
+		r.Down++
+		return
+	}
+	r.Up++
+	if serviceID != 0 {
+		r.DelaySum += float64(delay)
+		r.DelayCount++
+	}
+}
+
+// RecordServiceGeoRollup 按执行监控的服务器所在国家汇总一次服务监控结果
+func RecordServiceGeoRollup(reporter, serviceID uint64, successful bool, delay float32) {
+	server, ok := ServerShared.Get(reporter)
+	if !ok || server == nil {
+		return
+	}
+	if country := geoRollupCountry(server); country != "" {
+		addGeoRollup(time.Now(), country, serviceID, successful, delay)
+	}
+}
+
+// SampleServerGeoRollup 按国家采样一次服务器是否在线，每分钟执行
+func SampleServerGeoRollup() {
+	now := time.Now()
+	for server := range utils.Seq2To1(ServerShared.Range) {
+		if country := geoRollupCountry(server); country != "" {
+			online := serverOnline(server, now)
+			addGeoRollup(now, country, 0, online, 0)
+		}
+	}
+}
+
+// FlushGeoRollups 把内存中的汇总累加到数据库
+func FlushGeoRollups() {
+	geoRollupMu.Lock()
+	pending := geoRollupPending
+	geoRollupPending = make(map[geoRollupKey]*model.GeoRollup)
+	geoRollupMu.Unlock()
+
+	for _, r := range pending {
+		if err := DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "day"}, {Name: "country"}, {Name: "service_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"up":          gorm.Expr("up + ?", r.Up),
+				"down":        gorm.Expr("down + ?", r.Down),
+				"delay_sum":   gorm.Expr("delay_sum + ?", r.DelaySum),
+				"delay_count": gorm.Expr("delay_count + ?", r.DelayCount),
+			}),
+		}).Create(r).Error; err != nil {
+			log.Printf("NEZHA>> Failed to save geo rollup of %s: %v", strings.ToUpper(r.Country), err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package singleton
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+var geoGroupMu sync.Mutex
+
+// SyncGeoGroups 按 Conf.GeoAutoGroup 把服务器加入对应洲或国家的分组
+// 分组不存在时自动创建，服务器的国家变化后移到新的分组，没有服务器的自动分组会被删除；
+// 还没有定位结果的服务器保留原来的分组。切换分组方式后，另一种方式的自动分组会被删除，关闭自动分组后所有自动分组都会被删除
+func SyncGeoGroups() {
+	mode := Conf.GeoAutoGroup
+	geoGroupMu.Lock()
+	defer geoGroupMu.Unlock()
+
+	desired := make(map[uint64]string)
+	for server := range utils.Seq2To1(ServerShared.Range) {
+		if code := geoGroupCode(mode, server); code != "" {
+			desired[server.ID] = code
+		}
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var groups []model.ServerGroup
+		if err := tx.Where("geo_code != ''").Find(&groups).Error; err != nil {
+			return err
+		}
+		byCode := make(map[string]uint64)
+		codeOf := make(map[uint64]string)
+		var stale []uint64
+		for _, g := range groups {
+			if mode == "" || !strings.HasPrefix(g.GeoCode, mode+":") {
+				stale = append(stale, g.ID)
+				continue
+			}
+			byCode[g.GeoCode] = g.ID
+			codeOf[g.ID] = g.GeoCode
+		}
+		if err := deleteServerGroups(tx, stale); err != nil {
+			return err
+		}
+		if mode == "" {
+			return nil
+		}
+
+		for _, code := range desired {
+			if _, ok := byCode[code]; ok {
+				continue
+			}
+			g := model.ServerGroup{Name: geoGroupName(code), GeoCode: code}
+			if err := tx.Create(&g).Error; err != nil {
+				return err
+			}
+			byCode[code] = g.ID
+			codeOf[g.ID] = code
+		}
+
+		var members []model.ServerGroupServer
+		if err := tx.Where("server_group_id IN (?)", utils.MapKeysToSlice(codeOf)).Find(&members).Error; err != nil {
+			return err
+		}
+		remaining := make(map[uint64]int)
+		joined := make(map[uint64]bool)
+		var moved []uint64
+		for _, m := range members {
+			code, ok := desired[m.ServerId]
+			if ok && code != codeOf[m.ServerGroupId] {
+				moved = append(moved, m.ID)
+				continue
+			}
+			remaining[m.ServerGroupId]++
+			joined[m.ServerId] = true
+		}
+		if len(moved) > 0 {
+			if err := tx.Unscoped().Delete(&model.ServerGroupServer{}, "id IN (?)", moved).Error; err != nil {
+				return err
+			}
+		}
+		for serverID, code := range desired {
+			if joined[serverID] {
+				continue
+			}
+			if err := tx.Create(&model.ServerGroupServer{ServerGroupId: byCode[code], ServerId: serverID}).Error; err != nil {
+				return err
+			}
+			remaining[byCode[code]]++
+		}
+
+		var empty []uint64
+		for id := range codeOf {
+			if remaining[id] == 0 {
+				empty = append(empty, id)
+			}
+		}
+		return deleteServerGroups(tx, empty)
+	})
+	if err != nil {
+		log.Printf("NEZHA>> Failed to sync geo groups: %v", err)
+	}
+}
+
+func deleteServerGroups(tx *gorm.DB, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Delete(&model.ServerGroupServer{}, "server_group_id IN (?)", ids).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&model.ServerGroup{}, "id IN (?)", ids).Error
+}
+
+// geoGroupCode 返回服务器应该加入的自动分组，如 continent:as 或 country:jp，还没有定位结果时返回空字符串
+func geoGroupCode(mode string, server *model.Server) string {
+	country, continent := server.GeoIP.Region()
+	switch {
+	case mode == model.GeoGroupCountry && country != "":
+		return mode + ":" + country
+	case mode == model.GeoGroupContinent && continent != "":
+		return mode + ":" + continent
+	}
+	return ""
+}
+
+// geoGroupName 返回自动分组按面板语言显示的名称
+func geoGroupName(geoCode string) string {
+	mode, code, _ := strings.Cut(geoCode, ":")
+	name := geoip.CountryName(code, Conf.Language)
+	if mode == model.GeoGroupContinent {
+		name = geoip.ContinentName(code, Conf.Language)
+	}
+	if name == "" {
+		return strings.ToUpper(code)
+	}
+	return name
+}
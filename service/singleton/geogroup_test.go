@@ -0,0 +1,84 @@
+package singleton
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+// geoGroupMembers 返回每个分组的服务器，自动分组用 GeoCode 表示，手动分组用名称表示
+func geoGroupMembers(t *testing.T) map[string][]uint64 {
+	t.Helper()
+	var groups []model.ServerGroup
+	if err := DB.Find(&groups).Error; err != nil {
+		t.Fatal(err)
+	}
+	ret := make(map[string][]uint64)
+	for _, g := range groups {
+		var members []model.ServerGroupServer
+		if err := DB.Where("server_group_id = ?", g.ID).Order("server_id").Find(&members).Error; err != nil {
+			t.Fatal(err)
+		}
+		key := g.GeoCode
+		if key == "" {
+			key = g.Name
+		}
+		ret[key] = []uint64{}
+		for _, m := range members {
+			ret[key] = append(ret[key], m.ServerId)
+		}
+	}
+	return ret
+}
+
+func TestSyncGeoGroups(t *testing.T) {
+	setTestDB(t, &model.Config{GeoAutoGroup: model.GeoGroupContinent})
+	Conf.Language = "en"
+	jp := &model.Server{Common: model.Common{ID: 1}, GeoIP: testGeoIP("jp")}
+	de := &model.Server{Common: model.Common{ID: 2}, GeoIP: testGeoIP("de")}
+	unknown := &model.Server{Common: model.Common{ID: 3}, GeoIP: &model.GeoIP{}}
+	setTestServers(t, jp, de, unknown)
+
+	manual := model.ServerGroup{Name: "manual"}
+	if err := DB.Create(&manual).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := DB.Create(&model.ServerGroupServer{ServerGroupId: manual.ID, ServerId: 3}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(want map[string][]uint64) {
+		t.Helper()
+		if got := geoGroupMembers(t); !maps.EqualFunc(got, want, slices.Equal) {
+			t.Fatalf("groups = %v, want %v", got, want)
+		}
+	}
+
+	SyncGeoGroups()
+	expect(map[string][]uint64{"continent:as": {1}, "continent:eu": {2}, "manual": {3}})
+	var as model.ServerGroup
+	if err := DB.Where("geo_code = ?", "continent:as").First(&as).Error; err != nil || as.Name != "Asia" {
+		t.Fatalf("continent:as = %+v, %v", as, err)
+	}
+
+	// 切换分组方式后删除另一种方式的自动分组
+	Conf.GeoAutoGroup = model.GeoGroupCountry
+	SyncGeoGroups()
+	expect(map[string][]uint64{"country:jp": {1}, "country:de": {2}, "manual": {3}})
+
+	// 国家变化后移到新的分组，没有服务器的自动分组被删除
+	jp.GeoIP = testGeoIP("de")
+	SyncGeoGroups()
+	expect(map[string][]uint64{"country:de": {1, 2}, "manual": {3}})
+
+	// 关闭自动分组后删除所有自动分组，手动分组不受影响
+	Conf.GeoAutoGroup = ""
+	SyncGeoGroups()
+	expect(map[string][]uint64{"manual": {3}})
+	var count int64
+	if err := DB.Model(&model.ServerGroupServer{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("server group members = %d, %v", count, err)
+	}
+}
@@ -0,0 +1,177 @@
+package singleton
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+// SetGeoIPCountry 设置国家码，并填充对应的国家名称、旗帜与所在的洲，lang 是显示名称使用的语言
+// 只查到洲时 code 是洲代码，国家码与洲代码相同
+func SetGeoIPCountry(g *model.GeoIP, code, lang string) {
+	g.CountryCode = code
+	g.CountryName = geoip.CountryName(code, lang)
+	g.FlagEmoji = geoip.FlagEmoji(code)
+	g.ContinentCode = geoip.ContinentOf(code)
+	if g.ContinentCode == "" && slices.Contains(geoip.Continents(), strings.ToLower(code)) {
+		g.ContinentCode = strings.ToLower(code)
+	}
+}
+
+// NewWorldMap 按国家与洲聚合服务器，国家与洲的名称使用 lang
+func NewWorldMap(servers []*model.Server, lang string, now time.Time) *model.WorldMap {
+	countries := make(map[string]*model.WorldMapRegion)
+	continents := make(map[string]*model.WorldMapRegion)
+	wm := &model.WorldMap{Countries: []*model.WorldMapRegion{}, Continents: []*model.WorldMapRegion{}}
+	for _, s := range servers {
+		// 只查到洲的服务器只计入洲
+		code, continent := s.GeoIP.Region()
+		if continent == "" {
+			wm.Unknown++
+			continue
+		}
+
+		if code != "" {
+			wm.Countries = addWorldMapRegion(countries, wm.Countries, code, geoip.CountryName(code, lang), geoip.CountryCentroid, s, now)
+		}
+		wm.Continents = addWorldMapRegion(continents, wm.Continents, continent, geoip.ContinentName(continent, lang), geoip.ContinentCentroid, s, now)
+	}
+
+	byServers := func(a, b *model.WorldMapRegion) int {
+		return cmp.Or(cmp.Compare(b.Servers, a.Servers), strings.Compare(a.Code, b.Code))
+	}
+	slices.SortFunc(wm.Countries, byServers)
+	slices.SortFunc(wm.Continents, byServers)
+	return wm
+}
+
+func addWorldMapRegion(index map[string]*model.WorldMapRegion, list []*model.WorldMapRegion, code, name string, centroid func(string) (geoip.Location, bool), s *model.Server, now time.Time) []*model.WorldMapRegion {
+	r, ok := index[code]
+	if !ok {
+		r = &model.WorldMapRegion{Code: code, Name: name}
+		if loc, ok := centroid(code); ok {
+			r.Latitude, r.Longitude = loc.Latitude, loc.Longitude
+		}
+		index[code] = r
+		list = append(list, r)
+	}
+	r.Servers++
+	if serverOnline(s, now) {
+		r.Online++
+	} else {
+		r.Offline++
+	}
+	if s.State != nil {
+		r.TransferIn += s.State.NetInTransfer
+		r.TransferOut += s.State.NetOutTransfer
+	}
+	return list
+}
+
+func serverOnline(s *model.Server, now time.Time) bool {
+	return !s.LastActive.IsZero() && now.Sub(s.LastActive) <= model.ServerOnlineTimeout
+}
+
+// NewStatusPage 按 groupBy（model.GeoGroupCountry 或 model.GeoGroupContinent）分组服务器，组内保持 servers 的顺序
+// 按国家分组时只查到洲的服务器归入洲的分组
+func NewStatusPage(servers []*model.Server, groupBy, lang string, now time.Time) *model.StatusPage {
+	if groupBy != model.GeoGroupContinent {
+		groupBy = model.GeoGroupCountry
+	}
+	sp := &model.StatusPage{GroupBy: groupBy, Regions: []*model.StatusPageRegion{}}
+	index := make(map[string]*model.StatusPageRegion)
+	for _, s := range servers {
+		country, continent := s.GeoIP.Region()
+		code, name, flag := continent, geoip.ContinentName(continent, lang), ""
+		if groupBy == model.GeoGroupCountry && country != "" {
+			code, name, flag = country, geoip.CountryName(country, lang), geoip.FlagEmoji(country)
+		}
+
+		r, ok := index[code]
+		if !ok {
+			r = &model.StatusPageRegion{Code: code, Name: name, FlagEmoji: flag}
+			index[code] = r
+			sp.Regions = append(sp.Regions, r)
+		}
+		online := serverOnline(s, now)
+		if online {
+			r.Online++
+		}
+		r.Servers = append(r.Servers, &model.StatusPageServer{ID: s.ID, Name: s.Name, Online: online})
+	}
+
+	for _, r := range sp.Regions {
+		r.Uptime = float32(r.Online) / float32(len(r.Servers)) * 100
+	}
+	slices.SortFunc(sp.Regions, func(a, b *model.StatusPageRegion) int {
+		if (a.Code == "") != (b.Code == "") {
+			return utils.IfOr(a.Code == "", 1, -1)
+		}
+		return cmp.Or(cmp.Compare(len(b.Servers), len(a.Servers)), strings.Compare(a.Code, b.Code))
+	})
+	return sp
+}
+
+// SummarizeGeoRollups 把按天的 model.GeoRollup 按 groupBy（model.GeoGroupCountry 或 model.GeoGroupContinent）合并，
+// 服务器按代码排列，服务按服务 ID 再按代码排列，ServiceName 由调用方填写
+func SummarizeGeoRollups(rows []*model.GeoRollup, groupBy, lang string) *model.GeoRollupSummary {
+	if groupBy != model.GeoGroupContinent {
+		groupBy = model.GeoGroupCountry
+	}
+	summary := &model.GeoRollupSummary{GroupBy: groupBy, Servers: []*model.GeoRollupItem{}, Services: []*model.GeoRollupItem{}}
+
+	type key struct {
+		code      string
+		serviceID uint64
+	}
+	index := make(map[key]*model.GeoRollupItem)
+	delays := make(map[*model.GeoRollupItem][2]float64)
+	for _, row := range rows {
+		var geo model.GeoIP
+		SetGeoIPCountry(&geo, row.Country, lang)
+		country, continent := geo.Region()
+		code, name := continent, geoip.ContinentName(continent, lang)
+		if groupBy == model.GeoGroupCountry && country != "" {
+			code, name = country, geoip.CountryName(country, lang)
+		}
+		if code == "" {
+			continue
+		}
+
+		item, ok := index[key{code, row.ServiceID}]
+		if !ok {
+			item = &model.GeoRollupItem{Code: code, Name: name, ServiceID: row.ServiceID}
+			index[key{code, row.ServiceID}] = item
+			if row.ServiceID == 0 {
+				summary.Servers = append(summary.Servers, item)
+			} else {
+				summary.Services = append(summary.Services, item)
+			}
+		}
+		item.Up += row.Up
+		item.Down += row.Down
+		d := delays[item]
+		delays[item] = [2]float64{d[0] + row.DelaySum, d[1] + float64(row.DelayCount)}
+	}
+
+	for item, d := range delays {
+		if item.Up+item.Down > 0 {
+			item.Uptime = float32(item.Up) / float32(item.Up+item.Down) * 100
+		}
+		if d[1] > 0 {
+			item.AvgDelay = float32(d[0] / d[1])
+		}
+	}
+	slices.SortFunc(summary.Servers, func(a, b *model.GeoRollupItem) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	slices.SortFunc(summary.Services, func(a, b *model.GeoRollupItem) int {
+		return cmp.Or(cmp.Compare(a.ServiceID, b.ServiceID), strings.Compare(a.Code, b.Code))
+	})
+	return summary
+}
@@ -0,0 +1,135 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func testGeoIP(code string) *model.GeoIP {
+	g := &model.GeoIP{}
+	SetGeoIPCountry(g, code, "en")
+	return g
+}
+
+func TestSetGeoIPCountry(t *testing.T) {
+	cases := []struct {
+		code, country, continent, name string
+	}{
+		{"JP", "jp", "as", "Japan"},
+		{"as", "as", "oc", "American Samoa"},
+		{"eu", "", "eu", ""},
+		{"", "", "", ""},
+		{"zz", "", "", ""},
+	}
+	for _, c := range cases {
+		g := testGeoIP(c.code)
+		country, continent := g.Region()
+		if country != c.country || continent != c.continent || g.CountryName != c.name || g.CountryCode != c.code {
+			t.Fatalf("%q: got %+v (%q, %q)", c.code, g, country, continent)
+		}
+	}
+}
+
+func TestNewWorldMap(t *testing.T) {
+	now := time.Now()
+	servers := []*model.Server{
+		{GeoIP: testGeoIP("jp"), LastActive: now, State: &model.HostState{NetInTransfer: 10, NetOutTransfer: 20}},
+		{GeoIP: testGeoIP("jp"), LastActive: now.Add(-time.Hour), State: &model.HostState{NetInTransfer: 1, NetOutTransfer: 2}},
+		{GeoIP: testGeoIP("hk"), LastActive: now},
+		{GeoIP: testGeoIP("eu"), LastActive: now},
+		{GeoIP: &model.GeoIP{}},
+		{},
+	}
+	wm := NewWorldMap(servers, "en", now)
+
+	if wm.Unknown != 2 {
+		t.Fatalf("unknown = %d, want 2", wm.Unknown)
+	}
+	if len(wm.Countries) != 2 {
+		t.Fatalf("countries = %+v", wm.Countries)
+	}
+	jp := wm.Countries[0]
+	if jp.Code != "jp" || jp.Name != "Japan" || jp.Servers != 2 || jp.Online != 1 || jp.Offline != 1 || jp.TransferIn != 11 || jp.TransferOut != 22 || jp.Latitude == 0 {
+		t.Fatalf("jp = %+v", jp)
+	}
+	if len(wm.Continents) != 2 {
+		t.Fatalf("continents = %+v", wm.Continents)
+	}
+	if as := wm.Continents[0]; as.Code != "as" || as.Servers != 3 || as.Online != 2 {
+		t.Fatalf("as = %+v", as)
+	}
+	// 只查到洲的服务器只计入洲
+	if eu := wm.Continents[1]; eu.Code != "eu" || eu.Servers != 1 || eu.Name != "Europe" {
+		t.Fatalf("eu = %+v", eu)
+	}
+}
+
+func TestNewStatusPage(t *testing.T) {
+	now := time.Now()
+	servers := []*model.Server{
+		{Common: model.Common{ID: 1}, GeoIP: testGeoIP("jp"), LastActive: now},
+		{Common: model.Common{ID: 2}},
+		{Common: model.Common{ID: 3}, GeoIP: testGeoIP("hk"), LastActive: now},
+		{Common: model.Common{ID: 4}, GeoIP: testGeoIP("jp")},
+		{Common: model.Common{ID: 5}, GeoIP: testGeoIP("eu"), LastActive: now},
+	}
+
+	sp := NewStatusPage(servers, "", "en", now)
+	if sp.GroupBy != model.GeoGroupCountry || len(sp.Regions) != 4 {
+		t.Fatalf("status page = %+v", sp)
+	}
+	jp := sp.Regions[0]
+	if jp.Code != "jp" || jp.Name != "Japan" || jp.FlagEmoji != "🇯🇵" || len(jp.Servers) != 2 || jp.Online != 1 || jp.Uptime != 50 {
+		t.Fatalf("jp = %+v", jp)
+	}
+	if jp.Servers[0].ID != 1 || !jp.Servers[0].Online || jp.Servers[1].ID != 4 || jp.Servers[1].Online {
+		t.Fatalf("jp servers = %+v, %+v", jp.Servers[0], jp.Servers[1])
+	}
+	if eu := sp.Regions[1]; eu.Code != "eu" || eu.FlagEmoji != "" {
+		t.Fatalf("eu = %+v", eu)
+	}
+	if unknown := sp.Regions[3]; unknown.Code != "" || unknown.Servers[0].ID != 2 || unknown.Uptime != 0 {
+		t.Fatalf("unknown = %+v", unknown)
+	}
+
+	sp = NewStatusPage(servers, model.GeoGroupContinent, "en", now)
+	if as := sp.Regions[0]; as.Code != "as" || len(as.Servers) != 3 || as.Name != "Asia" {
+		t.Fatalf("as = %+v", as)
+	}
+}
+
+func TestSummarizeGeoRollups(t *testing.T) {
+	rows := []*model.GeoRollup{
+		{Country: "jp", Up: 90, Down: 10},
+		{Country: "jp", Up: 100},
+		{Country: "hk", Up: 50, Down: 50},
+		{Country: "eu", Up: 10},
+		{Country: "jp", ServiceID: 2, Up: 3, Down: 1, DelaySum: 60, DelayCount: 3},
+		{Country: "hk", ServiceID: 2, Up: 1, DelaySum: 40, DelayCount: 1},
+		{Country: "", Up: 1},
+	}
+
+	s := SummarizeGeoRollups(rows, "", "en")
+	if len(s.Servers) != 3 || len(s.Services) != 2 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if jp := s.Servers[2]; jp.Code != "jp" || jp.Name != "Japan" || jp.Up != 190 || jp.Down != 10 || jp.Uptime != 95 || jp.AvgDelay != 0 {
+		t.Fatalf("jp = %+v", jp)
+	}
+	if svc := s.Services[1]; svc.Code != "jp" || svc.ServiceID != 2 || svc.Uptime != 75 || svc.AvgDelay != 20 {
+		t.Fatalf("jp service = %+v", svc)
+	}
+
+	s = SummarizeGeoRollups(rows, model.GeoGroupContinent, "en")
+	if len(s.Servers) != 2 || len(s.Services) != 1 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if as := s.Servers[0]; as.Code != "as" || as.Up != 240 || as.Down != 60 || as.Uptime != 80 {
+		t.Fatalf("as = %+v", as)
+	}
+	if svc := s.Services[0]; svc.Up != 4 || svc.AvgDelay != 25 {
+		t.Fatalf("as service = %+v", svc)
+	}
+}
@@ -0,0 +1,150 @@
+package singleton
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+// 多个插件同时上报同一台服务器时，合并指标需要串行
+var serverMetricsMu sync.Mutex
+
+type MetricPluginClass struct {
+	class[uint64, *model.MetricPlugin]
+	*cron.Cron
+}
+
+func NewMetricPluginClass() *MetricPluginClass {
+	cronx := cron.New(cron.WithSeconds(), cron.WithLocation(Loc))
+	list := make(map[uint64]*model.MetricPlugin)
+
+	var sortedList []*model.MetricPlugin
+	DB.Find(&sortedList)
+
+	for _, plugin := range sortedList {
+		var err error
+		if plugin.CronJobID, err = cronx.AddFunc(MetricPluginScheduler(plugin), MetricPluginTrigger(plugin)); err != nil {
+			log.Printf("NEZHA>> Failed to register metric plugin %d: %v", plugin.ID, err)
+		}
+		list[plugin.ID] = plugin
+	}
+	cronx.Start()
+
+	return &MetricPluginClass{
+		class: class[uint64, *model.MetricPlugin]{
+			list:       list,
+			sortedList: sortedList,
+		},
+		Cron: cronx,
+	}
+}
+
+func (c *MetricPluginClass) Update(p *model.MetricPlugin) {
+	c.listMu.Lock()
+	if old := c.list[p.ID]; old != nil && old.CronJobID != 0 {
+		c.Cron.Remove(old.CronJobID)
+	}
+	c.list[p.ID] = p
+	c.listMu.Unlock()
+
+	c.sortList()
+}
+
+func (c *MetricPluginClass) Delete(idList []uint64) {
+	c.listMu.Lock()
+	for _, id := range idList {
+		if p := c.list[id]; p != nil && p.CronJobID != 0 {
+			c.Cron.Remove(p.CronJobID)
+		}
+		delete(c.list, id)
+	}
+	c.listMu.Unlock()
+
+	c.sortList()
+}
+
+func (c *MetricPluginClass) sortList() {
+	c.listMu.RLock()
+	defer c.listMu.RUnlock()
+
+	sortedList := utils.MapValuesToSlice(c.list)
+	slices.SortFunc(sortedList, func(a, b *model.MetricPlugin) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	c.sortedListMu.Lock()
+	defer c.sortedListMu.Unlock()
+	c.sortedList = sortedList
+}
+
+// MetricPluginScheduler 把插件的执行间隔转为 cron 表达式
+func MetricPluginScheduler(p *model.MetricPlugin) string {
+	return fmt.Sprintf("@every %ds", p.Duration)
+}
+
+// MetricPluginTrigger 返回让覆盖范围内的在线服务器执行插件并记录指标的任务
+func MetricPluginTrigger(p *model.MetricPlugin) func() {
+	servers := make(map[uint64]bool)
+	for _, id := range p.Servers {
+		servers[id] = true
+	}
+	timeout := time.Duration(p.Duration) * time.Second
+	return func() {
+		for server := range utils.Seq2To1(ServerShared.Range) {
+			if p.Cover == model.CronCoverAll && servers[server.ID] {
+				continue
+			}
+			if p.Cover == model.CronCoverIgnoreAll && !servers[server.ID] {
+				continue
+			}
+			if server.TaskStream == nil {
+				continue
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				if err := runMetricPlugin(ctx, p, server); err != nil {
+					log.Printf("NEZHA>> Failed to run metric plugin %s on server %d: %v", p.Name, server.ID, err)
+				}
+			}()
+		}
+	}
+}
+
+func runMetricPlugin(ctx context.Context, p *model.MetricPlugin, server *model.Server) error {
+	output, err := RunAgentCommand(ctx, server, p.Command)
+	if err != nil {
+		return err
+	}
+	metrics, err := model.ParseMetricOutput(p.Name, output)
+	if err != nil {
+		return err
+	}
+
+	// 复制后整体替换，避免与报警检查同时读写同一个 map
+	serverMetricsMu.Lock()
+	merged := maps.Clone(server.Metrics)
+	if merged == nil {
+		merged = make(map[string]float64, len(metrics))
+	}
+	now := time.Now()
+	history := make([]*model.MetricHistory, 0, len(metrics))
+	for name, value := range metrics {
+		merged[name] = value
+		history = append(history, &model.MetricHistory{CreatedAt: now, ServerID: server.ID, Name: name, Value: value})
+	}
+	server.Metrics = merged
+	serverMetricsMu.Unlock()
+
+	return DB.Create(history).Error
+}
@@ -89,6 +89,48 @@ func (c *ServerClass) UUIDToID(uuid string) (id uint64, ok bool) {
 	return
 }
 
+// GetGeoIP 返回服务器的连接地址与定位结果，与 SetGeoIP 的修改互斥
+func (c *ServerClass) GetGeoIP(s *model.Server) (peerIP string, geoip *model.GeoIP) {
+	c.listMu.RLock()
+	defer c.listMu.RUnlock()
+
+	return s.PeerIP, s.GeoIP
+}
+
+// SetGeoIP 在持有列表写锁时调用 fn 修改服务器的 PeerIP 与 GeoIP，通过 Range 遍历服务器时读取这两个字段不会与修改冲突
+// fn 中不能再调用 ServerClass 的方法
+func (c *ServerClass) SetGeoIP(s *model.Server, fn func()) {
+	c.listMu.Lock()
+	defer c.listMu.Unlock()
+
+	fn()
+}
+
+// GetWatchedUnits 返回服务器需要监控状态的 systemd unit
+func (c *ServerClass) GetWatchedUnits(s *model.Server) []string {
+	c.listMu.RLock()
+	defer c.listMu.RUnlock()
+
+	return s.WatchedUnits
+}
+
+// GetWatchedContainers 返回服务器需要监控状态的 Docker 容器
+func (c *ServerClass) GetWatchedContainers(s *model.Server) []string {
+	c.listMu.RLock()
+	defer c.listMu.RUnlock()
+
+	return s.WatchedContainers
+}
+
+// SetPolledStatus 在持有列表写锁时调用 fn 修改服务器定时从 Agent 读取的状态，如 Disks、WatchedUnits 与 UnitStatus，
+// 报警检查通过 Range 遍历服务器时读取这些字段不会与修改冲突，fn 中不能再调用 ServerClass 的方法
+func (c *ServerClass) SetPolledStatus(s *model.Server, fn func()) {
+	c.listMu.Lock()
+	defer c.listMu.Unlock()
+
+	fn()
+}
+
 func (c *ServerClass) UpdateDDNS(server *model.Server, ip *model.IP) error {
 	confServers := strings.Split(Conf.DNSServers, ",")
 	ctx := context.WithValue(context.Background(), ddns.DNSServerKey{}, utils.IfOr(confServers[0] != "", confServers, utils.DNSServers))
@@ -432,6 +432,8 @@ func (ss *ServiceSentinel) worker() {
 			serviceTcpMap[r.Reporter] = ts
 		}
 
+		RecordServiceGeoRollup(r.Reporter, mh.GetId(), mh.Successful, mh.Delay)
+
 		ss.serviceResponseDataStoreLock.Lock()
 		// 写入当天状态
 		if mh.Successful {
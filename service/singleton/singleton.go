@@ -34,6 +34,7 @@ var (
 	NotificationShared    *NotificationClass
 	NATShared             *NATClass
 	CronShared            *CronClass
+	MetricPluginShared    *MetricPluginClass
 )
 
 //go:embed frontend-templates.yaml
@@ -59,6 +60,7 @@ func LoadSingleton(bus chan<- *model.Service) (err error) {
 	NotificationShared = NewNotificationClass()
 	ServerShared = NewServerClass()
 	CronShared = NewCronClass()
+	MetricPluginShared = NewMetricPluginClass()
 	// 最后初始化 ServiceSentinel
 	ServiceSentinelShared, err = NewServiceSentinel(bus)
 	return
@@ -89,7 +91,8 @@ func InitDBFromPath(path string) error {
 		model.Notification{}, model.AlertRule{}, model.Service{}, model.NotificationGroupNotification{},
 		model.ServiceHistory{}, model.Cron{}, model.Transfer{}, model.ServerGroupServer{},
 		model.NAT{}, model.DDNSProfile{}, model.NotificationGroupNotification{},
-		model.WAF{}, model.Oauth2Bind{})
+		model.WAF{}, model.Oauth2Bind{}, model.AccessLog{}, model.GeoRollup{},
+		model.MetricPlugin{}, model.MetricHistory{})
 	if err != nil {
 		return err
 	}
@@ -139,6 +142,12 @@ func CleanServiceHistory() {
 	// server_id = 0 的数据会用于/service页面的可用性展示
 	DB.Unscoped().Delete(&model.ServiceHistory{}, "(created_at < ? AND server_id != 0) OR service_id NOT IN (SELECT `id` FROM services)", time.Now().AddDate(0, 0, -1))
 	DB.Unscoped().Delete(&model.Transfer{}, "server_id NOT IN (SELECT `id` FROM servers)")
+	// 后台访问记录保留 30 天
+	DB.Unscoped().Delete(&model.AccessLog{}, "created_at < ?", time.Now().AddDate(0, 0, -30))
+	// 按国家汇总的在线率保留 400 天，足够按月和按年对比
+	DB.Unscoped().Delete(&model.GeoRollup{}, "day < ?", time.Now().AddDate(0, 0, -400))
+	// 自定义指标记录保留 30 天
+	DB.Unscoped().Delete(&model.MetricHistory{}, "created_at < ? OR server_id NOT IN (SELECT `id` FROM servers)", time.Now().AddDate(0, 0, -30))
 	// 计算可清理流量记录的时长
 	var allServerKeep time.Time
 	specialServerKeep := make(map[uint64]time.Time)
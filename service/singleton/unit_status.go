@@ -0,0 +1,64 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+// UpdateUnitStatus 让 Agent 查询 server.WatchedUnits 的状态并更新 server.UnitStatus
+func UpdateUnitStatus(ctx context.Context, server *model.Server) ([]*model.UnitStatus, error) {
+	units := ServerShared.GetWatchedUnits(server)
+	if len(units) == 0 {
+		ServerShared.SetPolledStatus(server, func() {
+			server.UnitStatus = nil
+		})
+		return []*model.UnitStatus{}, nil
+	}
+	if server.Host != nil && strings.EqualFold(server.Host.Platform, "windows") {
+		return nil, errors.New("systemd units are not supported on windows")
+	}
+
+	output, err := RunAgentCommand(ctx, server, model.UnitStatusCommand(units))
+	if err != nil {
+		return nil, err
+	}
+	status, err := model.ParseUnitStatus(units, output)
+	if err != nil {
+		return nil, err
+	}
+	ServerShared.SetPolledStatus(server, func() {
+		// 查询期间修改了 WatchedUnits 时丢弃旧列表的结果
+		if slices.Equal(server.WatchedUnits, units) {
+			server.UnitStatus = status
+		}
+	})
+	return status, nil
+}
+
+// PollUnitStatus 查询所有在线且配置了 WatchedUnits 的服务器，每分钟执行
+func PollUnitStatus() {
+	var wg sync.WaitGroup
+	for server := range utils.Seq2To1(ServerShared.Range) {
+		if server.TaskStream == nil || len(server.WatchedUnits) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if _, err := UpdateUnitStatus(ctx, server); err != nil {
+				log.Printf("NEZHA>> Failed to read unit status of server %d: %v", server.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package singleton
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+func TestUpdateUnitStatusConcurrent(t *testing.T) {
+	server := &model.Server{Common: model.Common{ID: 1}, WatchedUnits: []string{"nginx.service"}}
+	server.TaskStream = &fakeTaskStream{reply: func(string) (string, bool) {
+		return "active\n", true
+	}}
+	setTestServers(t, server)
+
+	// 定时查询、修改 WatchedUnits 与报警检查同时进行，用 -race 运行时不应报告数据竞争
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			if _, err := UpdateUnitStatus(context.Background(), server); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ServerShared.SetPolledStatus(server, func() {
+				server.WatchedUnits = []string{"nginx.service"}
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			for s := range utils.Seq2To1(ServerShared.Range) {
+				for _, u := range s.UnitStatus {
+					_ = u.Active
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	status, err := UpdateUnitStatus(context.Background(), server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 || !status[0].Active || len(server.UnitStatus) != 1 {
+		t.Fatalf("status = %+v, server.UnitStatus = %+v", status, server.UnitStatus)
+	}
+}